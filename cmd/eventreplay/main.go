@@ -0,0 +1,121 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/G-Research/yunikorn-core/pkg/common/configs"
+	"github.com/G-Research/yunikorn-core/pkg/events"
+	"github.com/G-Research/yunikorn-core/pkg/scheduler"
+	"github.com/G-Research/yunikorn-core/pkg/webservice"
+)
+
+var (
+	speed    = flag.Float64("speed", 1, "replay speed: 1 is real time, 10 is ten times faster, 0 replays without delay")
+	format   = flag.String("format", "", "file format: jsonl or protobuf, derived from the file extension if not set")
+	capacity = flag.Uint64("capacity", configs.DefaultEventRingBufferCapacity, "ring buffer capacity of the event system")
+	exit     = flag.Bool("exit", false, "exit after the replay instead of serving the events until interrupted")
+)
+
+/*
+A utility command to replay captured event records into a fresh event system.
+The events are served on the REST endpoints of the scheduler, port 9080, including
+/ws/v1/events/batch and /ws/v1/events/stream.
+*/
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <event-file>...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	configs.SetConfigMap(map[string]string{
+		configs.CMEventTrackingEnabled:    "true",
+		configs.CMEventRingBufferCapacity: strconv.FormatUint(*capacity, 10),
+	})
+	// the REST handlers need a scheduler context, the replayed events are not linked to it.
+	// It is created before the event system is started so that it does not add any events.
+	clusterContext, err := scheduler.NewClusterContext("replay", "default", []byte(configs.DefaultSchedulerConfig))
+	if err != nil {
+		log.Printf("Could not create scheduler context: %v", err)
+		os.Exit(2)
+	}
+	eventSystem := events.GetEventSystem().(*events.EventSystemImpl) //nolint:errcheck
+	// there is no shim to publish the events to
+	eventSystem.StartServiceWithPublisher(false)
+	defer eventSystem.Stop()
+	webApp := webservice.NewWebApp(clusterContext, nil)
+	webApp.StartWebApp()
+	defer func() {
+		if err := webApp.StopWebApp(); err != nil {
+			log.Printf("Could not stop web app: %v", err)
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	total := 0
+	for _, name := range flag.Args() {
+		count, err := replayFile(ctx, eventSystem, name)
+		total += count
+		if errors.Is(err, context.Canceled) {
+			return
+		}
+		if err != nil {
+			log.Printf("Replay of %s failed after %d events: %v", name, count, err)
+			return
+		}
+		log.Printf("Replayed %d events from %s", count, name)
+	}
+	log.Printf("Replay finished, %d events replayed", total)
+	if !*exit {
+		<-ctx.Done()
+	}
+}
+
+func replayFile(ctx context.Context, eventSystem *events.EventSystemImpl, name string) (int, error) {
+	fileFormat := *format
+	if fileFormat == "" {
+		fileFormat = events.GetEventFileFormat(name)
+	}
+	file, err := os.Open(name)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	reader, err := events.NewEventRecordReader(file, fileFormat)
+	if err != nil {
+		return 0, err
+	}
+	// wait for the event system instead of dropping events when replaying faster than they are processed
+	return events.ReplayEvents(ctx, reader, *speed, eventSystem.AddEventWait)
+}
@@ -27,10 +27,14 @@ import (
 
 const (
 	// prefixes
-	PrefixEvent  = "event."
-	PrefixHealth = "health."
+	PrefixEvent    = "event."
+	PrefixHealth   = "health."
+	PrefixWebhook  = "webhook."
+	PrefixREST     = "rest."
+	PrefixFairness = "fairness."
 
-	HealthCheckInterval = PrefixHealth + "checkInterval"
+	HealthCheckInterval      = PrefixHealth + "checkInterval"
+	HealthEventDropThreshold = PrefixHealth + "eventDropThreshold" // warn when more events were dropped between health checks
 
 	// events
 	CMEventTrackingEnabled    = PrefixEvent + "trackingEnabled"    // Application Tracking
@@ -39,6 +43,48 @@ const (
 	CMMaxEventStreams         = PrefixEvent + "maxStreams"
 	CMMaxEventStreamsPerHost  = PrefixEvent + "maxStreamsPerHost"
 	CMRESTResponseSize        = PrefixEvent + "RESTResponseSize"
+	CMEventFileSinkEnabled    = PrefixEvent + "fileSinkEnabled"   // write events to local files
+	CMEventFileSinkDirectory  = PrefixEvent + "fileSinkDirectory" // directory for the event files
+	CMEventFileSinkFormat     = PrefixEvent + "fileSinkFormat"    // "jsonl" or "protobuf" (length-delimited)
+	CMEventFileSinkMaxSize    = PrefixEvent + "fileSinkMaxSize"   // rotate after the file reaches this size in bytes
+	CMEventFileSinkMaxAge     = PrefixEvent + "fileSinkMaxAge"    // rotate after the file is open for this duration
+	CMEventFileSinkMaxFiles   = PrefixEvent + "fileSinkMaxFiles"  // rotated files to keep, 0 keeps all files
+	CMEventFileSinkQueueSize  = PrefixEvent + "fileSinkQueueSize" // events queued for writing before dropping
+
+	// event sinks registered from the configuration, followed by <name>.<property>, see the events package
+	CMEventSinkPrefix = PrefixEvent + "sink."
+
+	// retention of the ring buffer on top of the capacity
+	CMEventRingBufferMaxAge      = PrefixEvent + "ringBufferMaxAge" // remove events older than this duration
+	CMEventRingBufferQuotaPrefix = PrefixEvent + "ringBufferQuota." // followed by the record type, percentage of the capacity
+
+	// state snapshots of application, queue and node events
+	CMEventStateDeltaEnabled     = PrefixEvent + "stateDeltaEnabled"     // send JSON merge patches instead of full snapshots
+	CMEventStateKeyframeInterval = PrefixEvent + "stateKeyframeInterval" // full snapshot every N snapshots of an object
+
+	// application lifecycle webhooks
+	CMWebhookSecret      = PrefixWebhook + "secret"      // HMAC-SHA256 key to sign the payloads, unsigned if not set
+	CMWebhookMaxRetries  = PrefixWebhook + "maxRetries"  // retries of a failed delivery
+	CMWebhookTimeout     = PrefixWebhook + "timeout"     // timeout of a single delivery attempt
+	CMWebhookQueueSize   = PrefixWebhook + "queueSize"   // deliveries queued before dropping
+	CMWebhookDeliveryLog = PrefixWebhook + "deliveryLog" // file to append the delivery attempts to, no log if not set
+
+	// REST API authentication
+	CMRESTAuthEnabled    = PrefixREST + "auth.enabled"    // require authentication for the REST API
+	CMRESTAuthTokenFile  = PrefixREST + "auth.tokenFile"  // static bearer tokens, one "token,user[,group...]" per line
+	CMRESTAuthClientCert = PrefixREST + "auth.clientCert" // use the verified TLS client certificate as identity
+
+	// REST API TLS, read on start
+	CMRESTTLSCertFile          = PrefixREST + "tls.certFile"          // PEM server certificate chain, TLS is enabled if set
+	CMRESTTLSKeyFile           = PrefixREST + "tls.keyFile"           // PEM private key of the server certificate
+	CMRESTTLSClientCAFile      = PrefixREST + "tls.clientCAFile"      // PEM CA bundle to verify client certificates
+	CMRESTTLSRequireClientCert = PrefixREST + "tls.requireClientCert" // reject clients without a verified certificate
+
+	// REST API node actions
+	CMRESTNodeDrainGracePeriod = PrefixREST + "nodeDrainGracePeriod" // default wait before a drain releases the allocations
+
+	// fairness
+	CMFairnessUsageHalfLife = PrefixFairness + "usageHalfLife" // half-life of the usage history, 0 disables the decay
 
 	// defaults
 	DefaultHealthCheckInterval     = 30 * time.Second
@@ -49,6 +95,28 @@ const (
 	DefaultMaxStreams              = uint64(100)
 	DefaultMaxStreamsPerHost       = uint64(15)
 	DefaultRESTResponseSize        = uint64(10000)
+	DefaultEventFileSinkEnabled    = false
+	DefaultEventFileSinkFormat     = "jsonl"
+	DefaultEventFileSinkMaxSize    = uint64(100 * 1024 * 1024)
+	DefaultEventFileSinkMaxAge     = time.Hour
+	DefaultEventFileSinkMaxFiles   = uint64(10)
+	DefaultEventFileSinkQueueSize  = uint64(10000)
+
+	DefaultHealthEventDropThreshold = uint64(1000)
+
+	DefaultEventSinkMaxRetries = uint64(3)
+
+	DefaultEventStateDeltaEnabled     = false
+	DefaultEventStateKeyframeInterval = uint64(100)
+
+	DefaultWebhookMaxRetries = uint64(3)
+	DefaultWebhookTimeout    = 10 * time.Second
+	DefaultWebhookQueueSize  = uint64(1000)
+
+	DefaultRESTAuthEnabled          = false
+	DefaultRESTAuthClientCert       = false
+	DefaultRESTTLSRequireClientCert = false
+	DefaultRESTNodeDrainGracePeriod = 30 * time.Second
 )
 
 var ConfigContext *SchedulerConfigContext
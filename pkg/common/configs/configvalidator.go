@@ -43,10 +43,13 @@ const (
 
 	ApplicationSortPolicy   = "application.sort.policy"
 	ApplicationSortPriority = "application.sort.priority"
+	QueueSortPolicy         = "queue.sort.policy"
+	QueueWeight             = "weight"
 	PriorityPolicy          = "priority.policy"
 	PriorityOffset          = "priority.offset"
 	PreemptionPolicy        = "preemption.policy"
 	PreemptionDelay         = "preemption.delay"
+	ApplicationWebhookURL   = "application.webhook.url"
 
 	// app sort priority values
 	ApplicationSortPriorityEnabled  = "enabled"
@@ -67,6 +70,9 @@ var MaxPriority int32 = math.MaxInt32
 
 var DefaultPreemptionDelay = 30 * time.Second
 
+// DefaultQueueWeight is the weight of a queue for fair sharing when not set
+const DefaultQueueWeight = 1.0
+
 // A queue can be a username with the dot replaced. Most systems allow a 32 character user name.
 // The queue name must thus allow for at least that length with the replacement of dots.
 var QueueNameRegExp = regexp.MustCompile(`^[a-zA-Z0-9_:#/@-]{1,64}$`)
@@ -0,0 +1,182 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package resources
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultUsageHalfLife is the half-life of the decayed usage when not configured
+	DefaultUsageHalfLife = time.Hour
+	// negligibleUsage is the decayed usage, in resource-seconds, below which an unused resource type is dropped
+	negligibleUsage = 0.5
+)
+
+// halfLifeChange is a change of the half-life of all decayed usage.
+type halfLifeChange struct {
+	halfLife time.Duration // half-life from the change on, 0 disables the decay
+	changed  time.Time     // time of the change, zero for the initial half-life
+}
+
+// usageHalfLives are all changes of the half-life, the last one is the current half-life. The slice is replaced on
+// a change and never modified.
+var usageHalfLives atomic.Pointer[[]halfLifeChange]
+
+func init() {
+	usageHalfLives.Store(&[]halfLifeChange{{halfLife: DefaultUsageHalfLife}})
+}
+
+// SetUsageHalfLife sets the half-life used by all decayed usage from now on. The usage before now keeps the decay
+// of the half-life at that time. A zero or negative half-life disables the decay: the usage is integrated without
+// ever being forgotten.
+func SetUsageHalfLife(halfLife time.Duration) {
+	setUsageHalfLifeAt(halfLife, time.Now())
+}
+
+func setUsageHalfLifeAt(halfLife time.Duration, now time.Time) {
+	if halfLife < 0 {
+		halfLife = 0
+	}
+	for {
+		current := usageHalfLives.Load()
+		if (*current)[len(*current)-1].halfLife == halfLife {
+			return
+		}
+		changes := append(make([]halfLifeChange, 0, len(*current)+1), *current...)
+		changes = append(changes, halfLifeChange{halfLife: halfLife, changed: now})
+		if usageHalfLives.CompareAndSwap(current, &changes) {
+			return
+		}
+	}
+}
+
+// GetUsageHalfLife returns the half-life used by all decayed usage.
+func GetUsageHalfLife() time.Duration {
+	changes := *usageHalfLives.Load()
+	return changes[len(changes)-1].halfLife
+}
+
+// DecayedUsage integrates a resource usage over time into resource-seconds, with an exponential decay: usage that
+// happened one half-life ago counts for half. A constant usage converges to usage * half-life / ln(2).
+// The caller must update the usage on every change. Not thread safe, the caller must hold the lock of the owner.
+type DecayedUsage struct {
+	integral map[string]float64 // decayed usage per resource type at the time of the last update
+	usage    *Resource          // usage since the last update
+	updated  time.Time          // time of the last update
+	change   int                // index of the half-life change in use at the last update
+}
+
+// NewDecayedUsage returns a decayed usage without history.
+func NewDecayedUsage() *DecayedUsage {
+	return &DecayedUsage{
+		integral: make(map[string]float64),
+		change:   len(*usageHalfLives.Load()) - 1,
+	}
+}
+
+// Update integrates the current usage up to now and replaces it with the new usage.
+func (du *DecayedUsage) Update(now time.Time, usage *Resource) {
+	if du == nil {
+		return
+	}
+	changes := *usageHalfLives.Load()
+	du.integral = du.projectChanges(changes, now)
+	du.usage = usage.Clone()
+	du.updated = now
+	du.change = len(changes) - 1
+}
+
+// Value returns the decayed usage at the time passed in, in resource-seconds.
+func (du *DecayedUsage) Value(now time.Time) *Resource {
+	result := NewResource()
+	if du == nil {
+		return result
+	}
+	for name, value := range du.project(now) {
+		result.Resources[name] = Quantity(math.Round(value))
+	}
+	return result
+}
+
+// IsNegligible returns true if there is no current usage and the history has decayed to nothing.
+func (du *DecayedUsage) IsNegligible(now time.Time) bool {
+	if du == nil {
+		return true
+	}
+	return IsZero(du.usage) && len(du.project(now)) == 0
+}
+
+// project returns the integral at the time passed in, without changing the stored integral.
+func (du *DecayedUsage) project(now time.Time) map[string]float64 {
+	return du.projectChanges(*usageHalfLives.Load(), now)
+}
+
+// projectChanges returns the integral at the time passed in. The time since the last update is split on the half-life
+// changes made after the last update: each part is decayed with the half-life in use at that time.
+func (du *DecayedUsage) projectChanges(changes []halfLifeChange, now time.Time) map[string]float64 {
+	result := make(map[string]float64, len(du.integral))
+	for name, value := range du.integral {
+		result[name] = value
+	}
+	from := du.updated
+	if !from.IsZero() {
+		for i := du.change; i < len(changes); i++ {
+			to := now
+			if i+1 < len(changes) && changes[i+1].changed.Before(now) {
+				to = changes[i+1].changed
+			}
+			elapsed := 0.0
+			if to.After(from) {
+				elapsed = to.Sub(from).Seconds()
+				from = to
+			}
+			du.decay(result, elapsed, changes[i].halfLife)
+		}
+	}
+	// only drop types that are not used anymore: a small usage must keep adding up
+	for name, value := range result {
+		if math.Abs(value) < negligibleUsage && (du.usage == nil || du.usage.Resources[name] == 0) {
+			delete(result, name)
+		}
+	}
+	return result
+}
+
+// decay decays the values over the elapsed seconds with the half-life and adds the usage over that time.
+func (du *DecayedUsage) decay(values map[string]float64, elapsed float64, halfLife time.Duration) {
+	// decay factor of the existing integral and the part of the usage that is left after the decay
+	factor := 1.0
+	weight := elapsed
+	if seconds := halfLife.Seconds(); seconds > 0 {
+		rate := math.Ln2 / seconds
+		factor = math.Exp(-rate * elapsed)
+		weight = (1 - factor) / rate
+	}
+	for name, value := range values {
+		values[name] = value * factor
+	}
+	if du.usage != nil {
+		for name, quantity := range du.usage.Resources {
+			values[name] += float64(quantity) * weight
+		}
+	}
+}
@@ -0,0 +1,113 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package resources
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func setUsageHalfLife(t *testing.T, halfLife time.Duration) {
+	original := GetUsageHalfLife()
+	SetUsageHalfLife(halfLife)
+	t.Cleanup(func() {
+		SetUsageHalfLife(original)
+	})
+}
+
+func TestDecayedUsageNoDecay(t *testing.T) {
+	setUsageHalfLife(t, 0)
+	start := time.Now()
+	du := NewDecayedUsage()
+	assert.Assert(t, du.IsNegligible(start), "new usage should be negligible")
+	du.Update(start, NewResourceFromMap(map[string]Quantity{"vcore": 2}))
+	assert.Assert(t, !du.IsNegligible(start), "usage should not be negligible")
+	assert.DeepEqual(t, NewResourceFromMap(map[string]Quantity{"vcore": 0}), du.Value(start))
+	assert.DeepEqual(t, NewResourceFromMap(map[string]Quantity{"vcore": 20}), du.Value(start.Add(10*time.Second)))
+
+	// usage changes after 10s, the first part stays
+	du.Update(start.Add(10*time.Second), NewResourceFromMap(map[string]Quantity{"vcore": 1, "memory": 100}))
+	assert.DeepEqual(t, NewResourceFromMap(map[string]Quantity{"vcore": 30, "memory": 1000}), du.Value(start.Add(20*time.Second)))
+	du.Update(start.Add(20*time.Second), nil)
+	assert.DeepEqual(t, NewResourceFromMap(map[string]Quantity{"vcore": 30, "memory": 1000}), du.Value(start.Add(time.Hour)))
+	assert.Assert(t, !du.IsNegligible(start.Add(time.Hour)), "history without decay should not be negligible")
+
+	// time going backwards does not change the value
+	assert.DeepEqual(t, NewResourceFromMap(map[string]Quantity{"vcore": 30, "memory": 1000}), du.Value(start))
+	var nilUsage *DecayedUsage
+	nilUsage.Update(start, NewResourceFromMap(map[string]Quantity{"vcore": 2}))
+	assert.DeepEqual(t, NewResource(), nilUsage.Value(start))
+	assert.Assert(t, nilUsage.IsNegligible(start), "nil usage should be negligible")
+}
+
+func TestDecayedUsageHalfLife(t *testing.T) {
+	setUsageHalfLife(t, time.Minute)
+	start := time.Now()
+	du := NewDecayedUsage()
+	du.Update(start, NewResourceFromMap(map[string]Quantity{"vcore": 1000}))
+	// integral of 1000 * e^(-ln2 t/60) over one half-life: 1000 * 60 / ln2 * (1 - 1/2)
+	expected := Quantity(math.Round(1000 * 60 / math.Ln2 / 2))
+	assert.DeepEqual(t, NewResourceFromMap(map[string]Quantity{"vcore": expected}), du.Value(start.Add(time.Minute)))
+	// constant usage converges to usage * half-life / ln2
+	converged := Quantity(math.Round(1000 * 60 / math.Ln2))
+	value := du.Value(start.Add(time.Hour)).Resources["vcore"]
+	assert.Assert(t, math.Abs(float64(converged-value)) <= 1, "expected %d got %d", converged, value)
+
+	// after the usage stops the history halves every half-life
+	du.Update(start.Add(time.Minute), nil)
+	assert.DeepEqual(t, NewResourceFromMap(map[string]Quantity{"vcore": expected / 2}), du.Value(start.Add(2*time.Minute)))
+	assert.Assert(t, !du.IsNegligible(start.Add(2*time.Minute)), "history should not be negligible after one half-life")
+	assert.Assert(t, du.IsNegligible(start.Add(time.Hour)), "history should be negligible after 59 half-lives")
+
+	// update splits the interval without changing the result
+	split := NewDecayedUsage()
+	split.Update(start, NewResourceFromMap(map[string]Quantity{"vcore": 1000}))
+	split.Update(start.Add(30*time.Second), NewResourceFromMap(map[string]Quantity{"vcore": 1000}))
+	assert.DeepEqual(t, NewResourceFromMap(map[string]Quantity{"vcore": expected}), split.Value(start.Add(time.Minute)))
+}
+
+func TestDecayedUsageHalfLifeChange(t *testing.T) {
+	setUsageHalfLife(t, 0)
+	start := time.Now()
+	du := NewDecayedUsage()
+	du.Update(start, NewResourceFromMap(map[string]Quantity{"vcore": 1000}))
+	// the first minute is integrated without decay, the second minute with a half-life of one minute
+	setUsageHalfLifeAt(time.Minute, start.Add(time.Minute))
+	assert.Equal(t, time.Minute, GetUsageHalfLife())
+	assert.DeepEqual(t, NewResourceFromMap(map[string]Quantity{"vcore": 60000}), du.Value(start.Add(time.Minute)))
+	expected := Quantity(math.Round(60000/2 + 1000*60/math.Ln2/2))
+	assert.DeepEqual(t, NewResourceFromMap(map[string]Quantity{"vcore": expected}), du.Value(start.Add(2*time.Minute)))
+	// an update after the change keeps the value
+	du.Update(start.Add(90*time.Second), NewResourceFromMap(map[string]Quantity{"vcore": 1000}))
+	value := du.Value(start.Add(2 * time.Minute)).Resources["vcore"]
+	assert.Assert(t, math.Abs(float64(expected-value)) <= 1, "expected %d got %d", expected, value)
+
+	// setting the same half-life is not a change
+	changes := len(*usageHalfLives.Load())
+	setUsageHalfLifeAt(time.Minute, start.Add(3*time.Minute))
+	assert.Equal(t, changes, len(*usageHalfLives.Load()), "same half-life should not be added")
+
+	// a usage created after the change only uses the new half-life
+	later := NewDecayedUsage()
+	later.Update(start.Add(time.Minute), NewResourceFromMap(map[string]Quantity{"vcore": 1000}))
+	assert.DeepEqual(t, NewResourceFromMap(map[string]Quantity{"vcore": Quantity(math.Round(1000 * 60 / math.Ln2 / 2))}), later.Value(start.Add(2*time.Minute)))
+}
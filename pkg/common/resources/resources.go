@@ -455,6 +455,27 @@ func (r *Resource) FitInMaxUndef(smaller *Resource) bool {
 	return r.fitIn(smaller, true)
 }
 
+// NotFitInMaxUndef returns the sorted resource types of smaller that do not fit in the defined resource.
+// Types not defined in resource this is called against are considered the maximum value for Quantity, same as for
+// FitInMaxUndef. An empty slice is returned if smaller fits.
+func (r *Resource) NotFitInMaxUndef(smaller *Resource) []string {
+	if r == nil || smaller == nil {
+		return []string{}
+	}
+	notFit := make([]string, 0)
+	for k, v := range smaller.Resources {
+		largerValue, ok := r.Resources[k]
+		if !ok {
+			continue
+		}
+		if v > max(0, largerValue) {
+			notFit = append(notFit, k)
+		}
+	}
+	sort.Strings(notFit)
+	return notFit
+}
+
 // Check if smaller fits in the defined resource
 // Negative values will be treated as 0
 // A nil resource is treated as an empty resource, behaviour defined by skipUndef
@@ -616,6 +637,25 @@ func CompUsageRatioSeparately(leftAllocated, leftGuaranteed, leftFairMax, rightA
 	}
 }
 
+// Calculate the dominant share of left and right compared to the capacity, see DominantShare.
+// This returns the same value as compareShares does:
+// 0 for equal shares
+// 1 if the left share is larger
+// -1 if the right share is larger
+func CompDominantShare(left, right, capacity *Resource) int {
+	lshare := left.DominantShare(capacity)
+	rshare := right.DominantShare(capacity)
+
+	switch {
+	case lshare > rshare:
+		return 1
+	case lshare < rshare:
+		return -1
+	default:
+		return 0
+	}
+}
+
 // Get fairness ratio calculated by:
 // highest share for left resource from total divided by
 // highest share for right resource from total.
@@ -1155,3 +1195,21 @@ func (r *Resource) DominantResourceType(capacity *Resource) string {
 	}
 	return dominant
 }
+
+// DominantShare returns the ratio of the usage of the dominant resource type, see DominantResourceType, compared to
+// the capacity. The share is 0 if there is no dominant type, and 1 if the type has a usage but no capacity.
+func (r *Resource) DominantShare(capacity *Resource) float64 {
+	dominant := r.DominantResourceType(capacity)
+	if dominant == "" {
+		return 0
+	}
+	usedVal := r.Resources[dominant]
+	capVal := capacity.Resources[dominant]
+	if capVal == 0 {
+		if usedVal == 0 {
+			return 0
+		}
+		return 1
+	}
+	return float64(usedVal) / float64(capVal)
+}
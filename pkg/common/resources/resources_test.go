@@ -1946,6 +1946,29 @@ func TestCompUsageRatioSeparately(t *testing.T) {
 	}
 }
 
+func TestNotFitInMaxUndef(t *testing.T) {
+	tests := []struct {
+		larger   *Resource
+		smaller  *Resource
+		expected []string
+	}{
+		{nil, NewResourceFromMap(map[string]Quantity{"a": 1}), []string{}},
+		{NewResourceFromMap(map[string]Quantity{"a": 1}), nil, []string{}},
+		{NewResource(), NewResourceFromMap(map[string]Quantity{"a": 1}), []string{}},
+		{NewResourceFromMap(map[string]Quantity{"a": 5, "b": 5}), NewResourceFromMap(map[string]Quantity{"a": 5, "b": 1}), []string{}},
+		{NewResourceFromMap(map[string]Quantity{"a": 5, "b": 5}), NewResourceFromMap(map[string]Quantity{"b": 6, "a": 6, "c": 10}), []string{"a", "b"}},
+		{NewResourceFromMap(map[string]Quantity{"a": -1}), NewResourceFromMap(map[string]Quantity{"a": 0}), []string{}},
+		{NewResourceFromMap(map[string]Quantity{"a": -1}), NewResourceFromMap(map[string]Quantity{"a": 1}), []string{"a"}},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("%v-%v", tc.larger, tc.smaller), func(t *testing.T) {
+			notFit := tc.larger.NotFitInMaxUndef(tc.smaller)
+			assert.DeepEqual(t, tc.expected, notFit)
+			assert.Equal(t, len(notFit) == 0, tc.larger.FitInMaxUndef(tc.smaller), "inconsistent with FitInMaxUndef")
+		})
+	}
+}
+
 func TestFitInScoreNil(t *testing.T) {
 	// make sure we're nil safe IDE will complain about the non nil check
 	defer func() {
@@ -2301,6 +2324,38 @@ func TestResource_DominantResource(t *testing.T) {
 	}
 }
 
+func TestResource_DominantShare(t *testing.T) {
+	tests := []struct {
+		name      string
+		used      *Resource
+		capacity  *Resource
+		wantShare float64
+	}{
+		{"nil receiver", nil, Zero, 0},
+		{"nil cap", Zero, nil, 0},
+		{"usage not in cap", NewResourceFromMap(map[string]Quantity{"B": 10}), NewResourceFromMap(map[string]Quantity{"A": 10}), 0},
+		{"over cap", NewResourceFromMap(map[string]Quantity{"A": 20}), NewResourceFromMap(map[string]Quantity{"A": 10}), 2},
+		{"multiple usages", NewResourceFromMap(map[string]Quantity{"B": 10, "A": 5}), NewResourceFromMap(map[string]Quantity{"A": 10, "B": 40}), 0.5},
+		{"0 usage with 0 cap", NewResourceFromMap(map[string]Quantity{"A": 0}), NewResourceFromMap(map[string]Quantity{"A": 0}), 0},
+		{"usage with 0 cap", NewResourceFromMap(map[string]Quantity{"A": 10, "B": 5}), NewResourceFromMap(map[string]Quantity{"A": 0, "B": 10}), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantShare, tt.used.DominantShare(tt.capacity))
+		})
+	}
+}
+
+func TestCompDominantShare(t *testing.T) {
+	capacity := NewResourceFromMap(map[string]Quantity{"vcore": 9, "memory": 18})
+	cpuHeavy := NewResourceFromMap(map[string]Quantity{"vcore": 6, "memory": 2})
+	memHeavy := NewResourceFromMap(map[string]Quantity{"vcore": 1, "memory": 12})
+	assert.Equal(t, 0, CompDominantShare(cpuHeavy, memHeavy, capacity), "both use 2/3 of their dominant resource")
+	assert.Equal(t, 1, CompDominantShare(cpuHeavy, NewResourceFromMap(map[string]Quantity{"vcore": 5, "memory": 10}), capacity))
+	assert.Equal(t, -1, CompDominantShare(nil, memHeavy, capacity))
+	assert.Equal(t, 0, CompDominantShare(nil, NewResource(), capacity))
+}
+
 func TestResource_PruneNil(t *testing.T) {
 	// make sure we're nil safe IDE will complain about the receiver being nil
 	defer func() {
@@ -28,6 +28,7 @@ import (
 	"github.com/G-Research/yunikorn-core/pkg/metrics/history"
 	"github.com/G-Research/yunikorn-core/pkg/rmproxy"
 	"github.com/G-Research/yunikorn-core/pkg/scheduler"
+	"github.com/G-Research/yunikorn-core/pkg/webhook"
 	"github.com/G-Research/yunikorn-core/pkg/webservice"
 )
 
@@ -78,6 +79,8 @@ func StartAllServicesWithManualScheduler() *ServiceContext {
 func startAllServicesWithParameters(opts startupOptions) *ServiceContext {
 	log.Log(log.Entrypoint).Info("Starting event system")
 	events.GetEventSystem().StartService()
+	log.Log(log.Entrypoint).Info("Starting webhook notifier")
+	webhook.GetNotifier().StartService()
 
 	sched := scheduler.NewScheduler()
 	proxy := rmproxy.NewRMProxy(sched)
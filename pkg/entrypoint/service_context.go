@@ -25,6 +25,7 @@ import (
 	"github.com/G-Research/yunikorn-core/pkg/log"
 	"github.com/G-Research/yunikorn-core/pkg/metrics"
 	"github.com/G-Research/yunikorn-core/pkg/scheduler"
+	"github.com/G-Research/yunikorn-core/pkg/webhook"
 	"github.com/G-Research/yunikorn-core/pkg/webservice"
 	"github.com/G-Research/yunikorn-scheduler-interface/lib/go/api"
 )
@@ -49,5 +50,6 @@ func (s *ServiceContext) StopAll() {
 	}
 	s.Scheduler.Stop()
 	s.RMProxy.Stop()
+	webhook.GetNotifier().Stop()
 	events.GetEventSystem().Stop()
 }
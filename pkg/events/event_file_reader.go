@@ -0,0 +1,119 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protodelim"
+
+	"github.com/G-Research/yunikorn-scheduler-interface/lib/go/si"
+)
+
+// maximum size of a single line in a JSONL event file, state snapshots can be large
+const maxEventLineSize = 16 * 1024 * 1024
+
+// lines in a captured event stream that are not event records: the stream header, gap markers and errors
+var nonEventKeys = []string{"InstanceUUID", "gap", "StatusCode"}
+
+// EventRecordReader reads event records from a file written by the event file sink or captured from the
+// event stream REST endpoint.
+type EventRecordReader struct {
+	format  string
+	scanner *bufio.Scanner
+	reader  *bufio.Reader
+	line    int
+}
+
+// NewEventRecordReader creates a reader for the "jsonl" or "protobuf" (length-delimited) format.
+func NewEventRecordReader(reader io.Reader, format string) (*EventRecordReader, error) {
+	erReader := &EventRecordReader{format: format}
+	switch format {
+	case FileSinkFormatJSONL:
+		erReader.scanner = bufio.NewScanner(reader)
+		erReader.scanner.Buffer(make([]byte, 64*1024), maxEventLineSize)
+	case FileSinkFormatProtobuf:
+		erReader.reader = bufio.NewReader(reader)
+	default:
+		return nil, fmt.Errorf("unknown event file format: %s", format)
+	}
+	return erReader, nil
+}
+
+// GetEventFileFormat returns the format of an event file based on the file extension.
+// Files with a ".pb" or ".protobuf" extension are length-delimited protobuf, all others JSONL.
+func GetEventFileFormat(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".pb", "." + FileSinkFormatProtobuf:
+		return FileSinkFormatProtobuf
+	default:
+		return FileSinkFormatJSONL
+	}
+}
+
+// Read returns the next event record. Returns io.EOF if there are no more records.
+// For JSONL input empty lines and lines that are not event records, like the header and gap markers
+// of a captured event stream, are skipped.
+func (r *EventRecordReader) Read() (*si.EventRecord, error) {
+	if r.format == FileSinkFormatProtobuf {
+		record := &si.EventRecord{}
+		if err := protodelim.UnmarshalFrom(r.reader, record); err != nil {
+			return nil, err
+		}
+		return record, nil
+	}
+	for r.scanner.Scan() {
+		r.line++
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(line, &fields); err != nil {
+			return nil, fmt.Errorf("line %d: %w", r.line, err)
+		}
+		if isNonEventLine(fields) {
+			continue
+		}
+		record := &si.EventRecord{}
+		if err := json.Unmarshal(line, record); err != nil {
+			return nil, fmt.Errorf("line %d: %w", r.line, err)
+		}
+		return record, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+func isNonEventLine(fields map[string]json.RawMessage) bool {
+	for _, key := range nonEventKeys {
+		if _, ok := fields[key]; ok {
+			return true
+		}
+	}
+	return false
+}
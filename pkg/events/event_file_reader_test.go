@@ -0,0 +1,105 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package events
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/G-Research/yunikorn-scheduler-interface/lib/go/si"
+)
+
+func TestGetEventFileFormat(t *testing.T) {
+	assert.Equal(t, FileSinkFormatProtobuf, GetEventFileFormat("events.pb"))
+	assert.Equal(t, FileSinkFormatProtobuf, GetEventFileFormat("/tmp/events-1.PROTOBUF"))
+	assert.Equal(t, FileSinkFormatJSONL, GetEventFileFormat("events.jsonl"))
+	assert.Equal(t, FileSinkFormatJSONL, GetEventFileFormat("events.json"))
+	assert.Equal(t, FileSinkFormatJSONL, GetEventFileFormat("events"))
+}
+
+func TestEventRecordReader_Stream(t *testing.T) {
+	_, err := NewEventRecordReader(strings.NewReader(""), "xml")
+	assert.ErrorContains(t, err, "unknown event file format")
+
+	// output captured from the event stream endpoint
+	capture := `{"InstanceUUID":"7a8f3b0e"}
+{"gap":true,"requestedID":1,"lowestID":5}
+{"type":2,"objectID":"app-1","timestampNano":100,"eventID":5}
+
+{"type":3,"objectID":"node-1","timestampNano":200,"state":"{\"id\":\"x\"}","eventID":6}
+{"StatusCode":200,"Message":"Event stream was closed by the producer","Description":"Event stream was closed by the producer"}
+`
+	reader, err := NewEventRecordReader(strings.NewReader(capture), FileSinkFormatJSONL)
+	assert.NilError(t, err)
+	record, err := reader.Read()
+	assert.NilError(t, err)
+	assert.Equal(t, si.EventRecord_APP, record.Type)
+	assert.Equal(t, "app-1", record.ObjectID)
+	assert.Equal(t, int64(100), record.TimestampNano)
+	record, err = reader.Read()
+	assert.NilError(t, err)
+	assert.Equal(t, "node-1", record.ObjectID)
+	assert.Equal(t, `{"id":"x"}`, record.State)
+	_, err = reader.Read()
+	assert.Assert(t, errors.Is(err, io.EOF))
+
+	reader, err = NewEventRecordReader(strings.NewReader("{\"objectID\":\"app-1\"}\nnot json\n"), FileSinkFormatJSONL)
+	assert.NilError(t, err)
+	_, err = reader.Read()
+	assert.NilError(t, err)
+	_, err = reader.Read()
+	assert.ErrorContains(t, err, "line 2")
+}
+
+func TestEventRecordReader_FileSink(t *testing.T) {
+	for _, format := range []string{FileSinkFormatJSONL, FileSinkFormatProtobuf} {
+		t.Run(format, func(t *testing.T) {
+			dir := t.TempDir()
+			sink, err := newEventFileSink(newTestFileSinkConfig(dir, format))
+			assert.NilError(t, err)
+			sink.start()
+			for i := 0; i < 3; i++ {
+				sink.add(&si.EventRecord{ObjectID: "app-1", TimestampNano: int64(i + 1)})
+			}
+			sink.close()
+			files, err := listEventFiles(dir, format)
+			assert.NilError(t, err)
+			assert.Equal(t, 1, len(files))
+			assert.Equal(t, format, GetEventFileFormat(files[0]))
+
+			file, err := os.Open(files[0])
+			assert.NilError(t, err)
+			defer file.Close()
+			reader, err := NewEventRecordReader(file, format)
+			assert.NilError(t, err)
+			for i := 0; i < 3; i++ {
+				record, err := reader.Read()
+				assert.NilError(t, err)
+				assert.Equal(t, int64(i+1), record.TimestampNano)
+			}
+			_, err = reader.Read()
+			assert.Assert(t, errors.Is(err, io.EOF))
+		})
+	}
+}
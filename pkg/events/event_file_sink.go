@@ -0,0 +1,299 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protodelim"
+
+	"github.com/G-Research/yunikorn-core/pkg/common"
+	"github.com/G-Research/yunikorn-core/pkg/common/configs"
+	"github.com/G-Research/yunikorn-core/pkg/log"
+	"github.com/G-Research/yunikorn-core/pkg/metrics"
+	"github.com/G-Research/yunikorn-scheduler-interface/lib/go/si"
+)
+
+const (
+	FileSinkFormatJSONL    = "jsonl"
+	FileSinkFormatProtobuf = "protobuf"
+
+	fileSinkPrefix        = "events-"
+	fileSinkTimeFormat    = "20060102T150405.000000000Z"
+	fileSinkFlushInterval = time.Second
+)
+
+// fileSinkConfig the settings of the file sink, read from the "event." configuration.
+type fileSinkConfig struct {
+	directory string
+	format    string
+	maxSize   uint64
+	maxAge    time.Duration
+	maxFiles  uint64
+	queueSize uint64
+}
+
+// eventFileSink appends event records to local files so that the event history survives a restart.
+// Events are queued and written by a single goroutine. If the queue is full new events are dropped, the
+// scheduler is never blocked by the sink.
+// Files are named "events-<UTC creation time>.<format>" and rotated on size and age. Only the newest
+// files are kept if a maximum number of files is configured.
+type eventFileSink struct {
+	config  fileSinkConfig
+	queue   chan *si.EventRecord
+	stop    chan struct{}
+	stopped chan struct{}
+
+	// only accessed from the writer goroutine
+	file    *os.File
+	writer  *bufio.Writer
+	size    uint64
+	created time.Time
+}
+
+// newEventFileSink creates the sink and makes sure the directory exists. The sink is not started.
+func newEventFileSink(config fileSinkConfig) (*eventFileSink, error) {
+	if config.directory == "" {
+		return nil, fmt.Errorf("event file sink directory is not set")
+	}
+	if config.format != FileSinkFormatJSONL && config.format != FileSinkFormatProtobuf {
+		return nil, fmt.Errorf("unknown event file sink format: %s", config.format)
+	}
+	if err := os.MkdirAll(config.directory, 0o750); err != nil {
+		return nil, fmt.Errorf("cannot create event file sink directory: %w", err)
+	}
+	return &eventFileSink{
+		config:  config,
+		queue:   make(chan *si.EventRecord, config.queueSize),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}, nil
+}
+
+// start starts the writer goroutine.
+func (s *eventFileSink) start() {
+	go s.run()
+	log.Log(log.Events).Info("Started event file sink",
+		zap.String("directory", s.config.directory),
+		zap.String("format", s.config.format))
+}
+
+// add queues an event for writing. The event is dropped if the queue is full.
+func (s *eventFileSink) add(event *si.EventRecord) {
+	if event == nil {
+		return
+	}
+	select {
+	case s.queue <- event:
+	default:
+		metrics.GetEventMetrics().IncEventsSinkDropped()
+	}
+}
+
+// close stops the writer goroutine after writing the queued events and closes the current file.
+func (s *eventFileSink) close() {
+	close(s.stop)
+	<-s.stopped
+	log.Log(log.Events).Info("Stopped event file sink")
+}
+
+func (s *eventFileSink) run() {
+	defer close(s.stopped)
+	ticker := time.NewTicker(fileSinkFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case event := <-s.queue:
+			s.write(event)
+		case <-ticker.C:
+			s.flush()
+			if s.file != nil && time.Since(s.created) >= s.config.maxAge {
+				s.closeFile()
+			}
+		case <-s.stop:
+			for {
+				select {
+				case event := <-s.queue:
+					s.write(event)
+				default:
+					s.closeFile()
+					return
+				}
+			}
+		}
+	}
+}
+
+// write appends the event to the current file, rotating the file first if needed.
+func (s *eventFileSink) write(event *si.EventRecord) {
+	if s.file != nil && (s.size >= s.config.maxSize || time.Since(s.created) >= s.config.maxAge) {
+		s.closeFile()
+	}
+	if s.file == nil {
+		if err := s.openFile(); err != nil {
+			log.Log(log.Events).Warn("Cannot open event file", zap.Error(err))
+			metrics.GetEventMetrics().IncEventsSinkFailed()
+			return
+		}
+	}
+	n, err := s.encode(event)
+	s.size += uint64(n)
+	if err != nil {
+		log.Log(log.Events).Warn("Cannot write event to file",
+			zap.String("file", s.file.Name()),
+			zap.Error(err))
+		metrics.GetEventMetrics().IncEventsSinkFailed()
+		return
+	}
+	metrics.GetEventMetrics().IncEventsSinkWritten()
+}
+
+func (s *eventFileSink) encode(event *si.EventRecord) (int, error) {
+	if s.config.format == FileSinkFormatProtobuf {
+		return protodelim.MarshalTo(s.writer, event)
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return 0, err
+	}
+	return s.writer.Write(append(data, '\n'))
+}
+
+func (s *eventFileSink) openFile() error {
+	s.created = time.Now()
+	name := filepath.Join(s.config.directory, fileSinkPrefix+s.created.UTC().Format(fileSinkTimeFormat)+"."+s.config.format)
+	file, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return err
+	}
+	s.file = file
+	s.writer = bufio.NewWriter(file)
+	s.size = 0
+	s.removeOldFiles()
+	return nil
+}
+
+func (s *eventFileSink) flush() {
+	if s.writer == nil {
+		return
+	}
+	if err := s.writer.Flush(); err != nil {
+		log.Log(log.Events).Warn("Cannot flush event file",
+			zap.String("file", s.file.Name()),
+			zap.Error(err))
+	}
+}
+
+func (s *eventFileSink) closeFile() {
+	if s.file == nil {
+		return
+	}
+	s.flush()
+	if err := s.file.Close(); err != nil {
+		log.Log(log.Events).Warn("Cannot close event file",
+			zap.String("file", s.file.Name()),
+			zap.Error(err))
+	}
+	s.file = nil
+	s.writer = nil
+}
+
+// removeOldFiles removes the oldest event files from the directory if there are more than the configured
+// maximum, including the current file. The creation time in the name sorts the files from oldest to newest.
+func (s *eventFileSink) removeOldFiles() {
+	if s.config.maxFiles == 0 {
+		return
+	}
+	files, err := listEventFiles(s.config.directory, s.config.format)
+	if err != nil {
+		log.Log(log.Events).Warn("Cannot list event files", zap.Error(err))
+		return
+	}
+	for uint64(len(files)) > s.config.maxFiles {
+		if err = os.Remove(files[0]); err != nil {
+			log.Log(log.Events).Warn("Cannot remove event file",
+				zap.String("file", files[0]),
+				zap.Error(err))
+		}
+		files = files[1:]
+	}
+}
+
+// listEventFiles returns the event files of the format in the directory, sorted from oldest to newest.
+func listEventFiles(directory, format string) ([]string, error) {
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.Type().IsRegular() && strings.HasPrefix(name, fileSinkPrefix) && strings.HasSuffix(name, "."+format) {
+			files = append(files, filepath.Join(directory, name))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// getFileSinkConfig reads the file sink settings from the configuration.
+// The boolean return value is false if the file sink is disabled.
+func getFileSinkConfig() (fileSinkConfig, bool) {
+	configMap := configs.GetConfigMap()
+	if !common.GetConfigurationBool(configMap, configs.CMEventFileSinkEnabled, configs.DefaultEventFileSinkEnabled) {
+		return fileSinkConfig{}, false
+	}
+	config := fileSinkConfig{
+		directory: configMap[configs.CMEventFileSinkDirectory],
+		format:    configs.DefaultEventFileSinkFormat,
+		maxSize:   common.GetConfigurationUint(configMap, configs.CMEventFileSinkMaxSize, configs.DefaultEventFileSinkMaxSize),
+		maxAge:    configs.DefaultEventFileSinkMaxAge,
+		maxFiles:  common.GetConfigurationUint(configMap, configs.CMEventFileSinkMaxFiles, configs.DefaultEventFileSinkMaxFiles),
+		queueSize: common.GetConfigurationUint(configMap, configs.CMEventFileSinkQueueSize, configs.DefaultEventFileSinkQueueSize),
+	}
+	if format, ok := configMap[configs.CMEventFileSinkFormat]; ok {
+		config.format = strings.ToLower(format)
+	}
+	if value, ok := configMap[configs.CMEventFileSinkMaxAge]; ok {
+		maxAge, err := time.ParseDuration(value)
+		if err != nil || maxAge <= 0 {
+			log.Log(log.Events).Warn("Failed to parse configuration value",
+				zap.String("key", configs.CMEventFileSinkMaxAge),
+				zap.String("value", value),
+				zap.Error(err))
+		} else {
+			config.maxAge = maxAge
+		}
+	}
+	if config.maxSize == 0 {
+		config.maxSize = configs.DefaultEventFileSinkMaxSize
+	}
+	if config.queueSize == 0 {
+		config.queueSize = configs.DefaultEventFileSinkQueueSize
+	}
+	return config, true
+}
@@ -0,0 +1,234 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protodelim"
+	"gotest.tools/v3/assert"
+
+	"github.com/G-Research/yunikorn-core/pkg/common/configs"
+	"github.com/G-Research/yunikorn-core/pkg/metrics"
+	"github.com/G-Research/yunikorn-scheduler-interface/lib/go/si"
+)
+
+func newTestFileSinkConfig(dir, format string) fileSinkConfig {
+	return fileSinkConfig{
+		directory: dir,
+		format:    format,
+		maxSize:   configs.DefaultEventFileSinkMaxSize,
+		maxAge:    configs.DefaultEventFileSinkMaxAge,
+		maxFiles:  configs.DefaultEventFileSinkMaxFiles,
+		queueSize: configs.DefaultEventFileSinkQueueSize,
+	}
+}
+
+func TestNewEventFileSink(t *testing.T) {
+	_, err := newEventFileSink(newTestFileSinkConfig("", FileSinkFormatJSONL))
+	assert.ErrorContains(t, err, "directory is not set")
+	_, err = newEventFileSink(newTestFileSinkConfig(t.TempDir(), "xml"))
+	assert.ErrorContains(t, err, "unknown event file sink format")
+
+	dir := filepath.Join(t.TempDir(), "sub", "dir")
+	sink, err := newEventFileSink(newTestFileSinkConfig(dir, FileSinkFormatJSONL))
+	assert.NilError(t, err)
+	assert.Assert(t, sink != nil)
+	info, err := os.Stat(dir)
+	assert.NilError(t, err, "directory should have been created")
+	assert.Assert(t, info.IsDir())
+}
+
+func TestEventFileSink_JSONL(t *testing.T) {
+	dir := t.TempDir()
+	sink, err := newEventFileSink(newTestFileSinkConfig(dir, FileSinkFormatJSONL))
+	assert.NilError(t, err)
+	sink.start()
+	for i := 0; i < 3; i++ {
+		sink.add(&si.EventRecord{ObjectID: "app-1", TimestampNano: int64(i), State: `{"id":"app-1"}`})
+	}
+	sink.add(nil)
+	sink.close()
+
+	files, err := listEventFiles(dir, FileSinkFormatJSONL)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(files))
+	file, err := os.Open(files[0])
+	assert.NilError(t, err)
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	var records []*si.EventRecord
+	for scanner.Scan() {
+		record := &si.EventRecord{}
+		assert.NilError(t, json.Unmarshal(scanner.Bytes(), record))
+		records = append(records, record)
+	}
+	assert.Equal(t, 3, len(records))
+	for i, record := range records {
+		assert.Equal(t, int64(i), record.TimestampNano)
+		assert.Equal(t, `{"id":"app-1"}`, record.State)
+	}
+}
+
+func TestEventFileSink_Protobuf(t *testing.T) {
+	dir := t.TempDir()
+	sink, err := newEventFileSink(newTestFileSinkConfig(dir, FileSinkFormatProtobuf))
+	assert.NilError(t, err)
+	sink.start()
+	for i := 0; i < 3; i++ {
+		sink.add(&si.EventRecord{ObjectID: "node-1", TimestampNano: int64(i)})
+	}
+	sink.close()
+
+	files, err := listEventFiles(dir, FileSinkFormatProtobuf)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(files))
+	file, err := os.Open(files[0])
+	assert.NilError(t, err)
+	defer file.Close()
+	reader := bufio.NewReader(file)
+	var records []*si.EventRecord
+	for {
+		record := &si.EventRecord{}
+		err = protodelim.UnmarshalFrom(reader, record)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		assert.NilError(t, err)
+		records = append(records, record)
+	}
+	assert.Equal(t, 3, len(records))
+	assert.Equal(t, "node-1", records[2].ObjectID)
+	assert.Equal(t, int64(2), records[2].TimestampNano)
+}
+
+func TestEventFileSink_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	config := newTestFileSinkConfig(dir, FileSinkFormatJSONL)
+	// every event is larger than the max size: one event per file
+	config.maxSize = 1
+	config.maxFiles = 2
+	sink, err := newEventFileSink(config)
+	assert.NilError(t, err)
+	sink.start()
+	for i := 0; i < 5; i++ {
+		sink.add(&si.EventRecord{TimestampNano: int64(i + 1)})
+	}
+	sink.close()
+
+	files, err := listEventFiles(dir, FileSinkFormatJSONL)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, len(files), "old files should have been removed")
+	// newest events are kept
+	for i, file := range files {
+		data, err := os.ReadFile(file)
+		assert.NilError(t, err)
+		record := &si.EventRecord{}
+		assert.NilError(t, json.Unmarshal(data, record))
+		assert.Equal(t, int64(i+4), record.TimestampNano)
+	}
+
+	// age based rotation
+	dir = t.TempDir()
+	config = newTestFileSinkConfig(dir, FileSinkFormatJSONL)
+	config.maxAge = time.Nanosecond
+	sink, err = newEventFileSink(config)
+	assert.NilError(t, err)
+	sink.start()
+	sink.add(&si.EventRecord{TimestampNano: 1})
+	sink.add(&si.EventRecord{TimestampNano: 2})
+	sink.close()
+	files, err = listEventFiles(dir, FileSinkFormatJSONL)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, len(files))
+}
+
+func TestEventFileSink_Dropped(t *testing.T) {
+	metrics.GetEventMetrics().Reset()
+	config := newTestFileSinkConfig(t.TempDir(), FileSinkFormatJSONL)
+	config.queueSize = 1
+	sink, err := newEventFileSink(config)
+	assert.NilError(t, err)
+	// not started: the queue is not drained
+	sink.add(&si.EventRecord{TimestampNano: 1})
+	sink.add(&si.EventRecord{TimestampNano: 2})
+	sink.add(&si.EventRecord{TimestampNano: 3})
+	dropped, err := metrics.GetEventMetrics().GetEventsSinkDropped()
+	assert.NilError(t, err)
+	assert.Equal(t, 2, dropped)
+
+	sink.start()
+	sink.close()
+	written, err := metrics.GetEventMetrics().GetEventsSinkWritten()
+	assert.NilError(t, err)
+	assert.Equal(t, 1, written)
+}
+
+func TestGetFileSinkConfig(t *testing.T) {
+	original := configs.GetConfigMap()
+	defer configs.SetConfigMap(original)
+
+	configs.SetConfigMap(map[string]string{})
+	_, enabled := getFileSinkConfig()
+	assert.Assert(t, !enabled)
+
+	configs.SetConfigMap(map[string]string{
+		configs.CMEventFileSinkEnabled: "true",
+	})
+	config, enabled := getFileSinkConfig()
+	assert.Assert(t, enabled)
+	assert.Equal(t, newTestFileSinkConfig("", FileSinkFormatJSONL), config)
+
+	configs.SetConfigMap(map[string]string{
+		configs.CMEventFileSinkEnabled:   "true",
+		configs.CMEventFileSinkDirectory: "/tmp/events",
+		configs.CMEventFileSinkFormat:    "Protobuf",
+		configs.CMEventFileSinkMaxSize:   "1024",
+		configs.CMEventFileSinkMaxAge:    "10m",
+		configs.CMEventFileSinkMaxFiles:  "0",
+		configs.CMEventFileSinkQueueSize: "5",
+	})
+	config, enabled = getFileSinkConfig()
+	assert.Assert(t, enabled)
+	assert.Equal(t, fileSinkConfig{
+		directory: "/tmp/events",
+		format:    FileSinkFormatProtobuf,
+		maxSize:   1024,
+		maxAge:    10 * time.Minute,
+		maxFiles:  0,
+		queueSize: 5,
+	}, config)
+
+	// invalid values fall back to the defaults
+	configs.SetConfigMap(map[string]string{
+		configs.CMEventFileSinkEnabled:   "true",
+		configs.CMEventFileSinkMaxSize:   "0",
+		configs.CMEventFileSinkMaxAge:    "-1s",
+		configs.CMEventFileSinkQueueSize: "xyz",
+	})
+	config, _ = getFileSinkConfig()
+	assert.Equal(t, newTestFileSinkConfig("", FileSinkFormatJSONL), config)
+}
@@ -0,0 +1,105 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package events
+
+import (
+	"slices"
+
+	"github.com/G-Research/yunikorn-scheduler-interface/lib/go/si"
+)
+
+// PartitionMatcher reports whether the object an event refers to belongs to the named partition.
+// Event records do not carry the partition, the scheduler must resolve it from the object.
+type PartitionMatcher func(event *si.EventRecord, partition string) bool
+
+// EventFilter selects the event records a consumer is interested in.
+// Each non-empty field restricts the events returned: an event must match one of the values of
+// every field that is set. An empty field matches all events. A nil filter matches all events.
+type EventFilter struct {
+	Types         []si.EventRecord_Type
+	ChangeTypes   []si.EventRecord_ChangeType
+	ChangeDetails []si.EventRecord_ChangeDetail
+	ObjectIDs     []string
+	ReferenceIDs  []string
+	Partitions    []string
+	// PartitionMatcher resolves the partition of an event, required when Partitions is set.
+	// Without a matcher no event matches a partition filter.
+	PartitionMatcher PartitionMatcher
+}
+
+// IsEmpty returns true if the filter does not restrict the events in any way.
+func (f *EventFilter) IsEmpty() bool {
+	if f == nil {
+		return true
+	}
+	return len(f.Types) == 0 && len(f.ChangeTypes) == 0 && len(f.ChangeDetails) == 0 &&
+		len(f.ObjectIDs) == 0 && len(f.ReferenceIDs) == 0 && len(f.Partitions) == 0
+}
+
+// Matches returns true if the event passes the filter.
+func (f *EventFilter) Matches(event *si.EventRecord) bool {
+	if event == nil {
+		return false
+	}
+	if f.IsEmpty() {
+		return true
+	}
+	if len(f.Types) != 0 && !slices.Contains(f.Types, event.Type) {
+		return false
+	}
+	if len(f.ChangeTypes) != 0 && !slices.Contains(f.ChangeTypes, event.EventChangeType) {
+		return false
+	}
+	if len(f.ChangeDetails) != 0 && !slices.Contains(f.ChangeDetails, event.EventChangeDetail) {
+		return false
+	}
+	if len(f.ObjectIDs) != 0 && !slices.Contains(f.ObjectIDs, event.ObjectID) {
+		return false
+	}
+	if len(f.ReferenceIDs) != 0 && !slices.Contains(f.ReferenceIDs, event.ReferenceID) {
+		return false
+	}
+	if len(f.Partitions) != 0 {
+		if f.PartitionMatcher == nil {
+			return false
+		}
+		for _, partition := range f.Partitions {
+			if f.PartitionMatcher(event, partition) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Filter returns the events that pass the filter, keeping the order of the input.
+// If the filter is empty the input is returned as is.
+func (f *EventFilter) Filter(records []*si.EventRecord) []*si.EventRecord {
+	if f.IsEmpty() {
+		return records
+	}
+	filtered := make([]*si.EventRecord, 0, len(records))
+	for _, event := range records {
+		if f.Matches(event) {
+			filtered = append(filtered, event)
+		}
+	}
+	return filtered
+}
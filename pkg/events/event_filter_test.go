@@ -0,0 +1,87 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package events
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/G-Research/yunikorn-scheduler-interface/lib/go/si"
+)
+
+func TestEventFilter_Matches(t *testing.T) {
+	event := &si.EventRecord{
+		Type:              si.EventRecord_APP,
+		ObjectID:          "app-1",
+		ReferenceID:       "alloc-1",
+		EventChangeType:   si.EventRecord_ADD,
+		EventChangeDetail: si.EventRecord_APP_ALLOC,
+	}
+	matcher := func(event *si.EventRecord, partition string) bool {
+		return event.ObjectID == "app-1" && partition == "default"
+	}
+
+	var nilFilter *EventFilter
+	assert.Assert(t, nilFilter.IsEmpty())
+	assert.Assert(t, nilFilter.Matches(event))
+	assert.Assert(t, !nilFilter.Matches(nil))
+
+	tests := map[string]struct {
+		filter *EventFilter
+		match  bool
+	}{
+		"empty":                {&EventFilter{}, true},
+		"type":                 {&EventFilter{Types: []si.EventRecord_Type{si.EventRecord_NODE, si.EventRecord_APP}}, true},
+		"type mismatch":        {&EventFilter{Types: []si.EventRecord_Type{si.EventRecord_NODE}}, false},
+		"change type":          {&EventFilter{ChangeTypes: []si.EventRecord_ChangeType{si.EventRecord_ADD}}, true},
+		"change type mismatch": {&EventFilter{ChangeTypes: []si.EventRecord_ChangeType{si.EventRecord_REMOVE}}, false},
+		"detail":               {&EventFilter{ChangeDetails: []si.EventRecord_ChangeDetail{si.EventRecord_APP_ALLOC}}, true},
+		"detail mismatch":      {&EventFilter{ChangeDetails: []si.EventRecord_ChangeDetail{si.EventRecord_APP_NEW}}, false},
+		"object":               {&EventFilter{ObjectIDs: []string{"app-1"}}, true},
+		"object mismatch":      {&EventFilter{ObjectIDs: []string{"app-2"}}, false},
+		"reference":            {&EventFilter{ReferenceIDs: []string{"alloc-1"}}, true},
+		"reference mismatch":   {&EventFilter{ReferenceIDs: []string{"alloc-2"}}, false},
+		"partition":            {&EventFilter{Partitions: []string{"other", "default"}, PartitionMatcher: matcher}, true},
+		"partition mismatch":   {&EventFilter{Partitions: []string{"other"}, PartitionMatcher: matcher}, false},
+		"partition no matcher": {&EventFilter{Partitions: []string{"default"}}, false},
+		"combined":             {&EventFilter{Types: []si.EventRecord_Type{si.EventRecord_APP}, ObjectIDs: []string{"app-1"}}, true},
+		"combined mismatch":    {&EventFilter{Types: []si.EventRecord_Type{si.EventRecord_APP}, ObjectIDs: []string{"app-2"}}, false},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.match, tc.filter.Matches(event))
+		})
+	}
+}
+
+func TestEventFilter_Filter(t *testing.T) {
+	records := []*si.EventRecord{
+		{ObjectID: "app-1", TimestampNano: 1},
+		{ObjectID: "app-2", TimestampNano: 2},
+		{ObjectID: "app-1", TimestampNano: 3},
+	}
+	var nilFilter *EventFilter
+	assert.Equal(t, 3, len(nilFilter.Filter(records)))
+
+	filtered := (&EventFilter{ObjectIDs: []string{"app-1"}}).Filter(records)
+	assert.Equal(t, 2, len(filtered))
+	assert.Equal(t, int64(1), filtered[0].TimestampNano)
+	assert.Equal(t, int64(3), filtered[1].TimestampNano)
+}
@@ -55,6 +55,8 @@ func (sp *EventPublisher) StartService() {
 			case <-time.After(sp.pushEventInterval):
 				messages := sp.store.CollectEvents()
 				if len(messages) > 0 {
+					// the registered sinks receive the records independently of the shim
+					publishToSinks(messages)
 					if eventPlugin := plugins.GetResourceManagerCallbackPlugin(); eventPlugin != nil {
 						log.Log(log.Events).Debug("Sending eventChannel", zap.Int("number of messages", len(messages)))
 						eventPlugin.SendEvent(messages)
@@ -0,0 +1,65 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package events
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/G-Research/yunikorn-scheduler-interface/lib/go/si"
+)
+
+// ReplayEvents reads all event records and passes them to "add", keeping the original time between the events
+// divided by "speed": 1 is real time, 2 twice as fast. A speed of 0 or less replays without any delay.
+// The "add" function should wait for room instead of dropping events, as a replay without delay adds the events
+// faster than they are processed. Replay stops when the context is cancelled or "add" fails.
+// Returns the number of events replayed.
+func ReplayEvents(ctx context.Context, reader *EventRecordReader, speed float64, add func(ctx context.Context, event *si.EventRecord) error) (int, error) {
+	var count int
+	var previous int64
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+		if speed > 0 && count > 0 && record.TimestampNano > previous {
+			delay := time.Duration(float64(record.TimestampNano-previous) / speed)
+			select {
+			case <-ctx.Done():
+				return count, ctx.Err()
+			case <-time.After(delay):
+			}
+		} else if ctx.Err() != nil {
+			return count, ctx.Err()
+		}
+		// out of order events are replayed immediately and do not move the replay clock back
+		if record.TimestampNano > previous {
+			previous = record.TimestampNano
+		}
+		if err = add(ctx, record); err != nil {
+			return count, err
+		}
+		count++
+	}
+}
@@ -0,0 +1,117 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package events
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/G-Research/yunikorn-scheduler-interface/lib/go/si"
+)
+
+// three events, 100ms apart, with one event out of order
+const replayInput = `{"objectID":"a","timestampNano":1000000000}
+{"objectID":"b","timestampNano":1100000000}
+{"objectID":"c","timestampNano":1050000000}
+{"objectID":"d","timestampNano":1200000000}
+`
+
+func TestReplayEvents(t *testing.T) {
+	tests := map[string]struct {
+		speed float64
+		min   time.Duration
+		max   time.Duration
+	}{
+		"no delay":  {0, 0, 50 * time.Millisecond},
+		"real time": {1, 200 * time.Millisecond, time.Second},
+		"faster":    {4, 50 * time.Millisecond, 150 * time.Millisecond},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			reader, err := NewEventRecordReader(strings.NewReader(replayInput), FileSinkFormatJSONL)
+			assert.NilError(t, err)
+			var ids []string
+			start := time.Now()
+			count, err := ReplayEvents(context.Background(), reader, tc.speed, func(_ context.Context, event *si.EventRecord) error {
+				ids = append(ids, event.ObjectID)
+				return nil
+			})
+			elapsed := time.Since(start)
+			assert.NilError(t, err)
+			assert.Equal(t, 4, count)
+			assert.DeepEqual(t, []string{"a", "b", "c", "d"}, ids)
+			assert.Assert(t, elapsed >= tc.min && elapsed < tc.max, "unexpected replay duration %v", elapsed)
+		})
+	}
+}
+
+func TestReplayEvents_Cancel(t *testing.T) {
+	reader, err := NewEventRecordReader(strings.NewReader(replayInput), FileSinkFormatJSONL)
+	assert.NilError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	count, err := ReplayEvents(ctx, reader, 0.001, func(_ context.Context, _ *si.EventRecord) error {
+		cancel()
+		return nil
+	})
+	assert.Assert(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, 1, count)
+
+	reader, err = NewEventRecordReader(strings.NewReader("{\"objectID\":\"a\"}\nxyz\n"), FileSinkFormatJSONL)
+	assert.NilError(t, err)
+	count, err = ReplayEvents(context.Background(), reader, 0, func(_ context.Context, _ *si.EventRecord) error { return nil })
+	assert.ErrorContains(t, err, "line 2")
+	assert.Equal(t, 1, count)
+
+	// replay stops when the event cannot be added
+	reader, err = NewEventRecordReader(strings.NewReader(replayInput), FileSinkFormatJSONL)
+	assert.NilError(t, err)
+	count, err = ReplayEvents(context.Background(), reader, 0, func(_ context.Context, event *si.EventRecord) error {
+		if event.ObjectID == "c" {
+			return context.DeadlineExceeded
+		}
+		return nil
+	})
+	assert.Assert(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, 2, count)
+}
+
+func TestAddEventWait(t *testing.T) {
+	ec := &EventSystemImpl{channel: make(chan *si.EventRecord, 1)}
+	assert.NilError(t, ec.AddEventWait(context.Background(), &si.EventRecord{ObjectID: "a"}))
+
+	// full channel: waits for room instead of dropping the event
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		<-ec.channel
+	}()
+	assert.NilError(t, ec.AddEventWait(context.Background(), &si.EventRecord{ObjectID: "b"}))
+	assert.Equal(t, "b", (<-ec.channel).ObjectID)
+
+	// full channel and context done: not added
+	ec.channel <- &si.EventRecord{ObjectID: "c"}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Assert(t, errors.Is(ec.AddEventWait(ctx, &si.EventRecord{ObjectID: "d"}), context.Canceled))
+	assert.Equal(t, "c", (<-ec.channel).ObjectID)
+}
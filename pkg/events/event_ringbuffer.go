@@ -19,15 +19,26 @@
 package events
 
 import (
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/G-Research/yunikorn-core/pkg/locking"
 	"github.com/G-Research/yunikorn-core/pkg/log"
+	"github.com/G-Research/yunikorn-core/pkg/metrics"
 	"github.com/G-Research/yunikorn-scheduler-interface/lib/go/si"
 )
 
+// reasons for removing a record from the ring buffer, used as metric label
+const (
+	evictionCapacity = "capacity"
+	evictionAge      = "age"
+	evictionQuota    = "quota"
+)
+
 type eventRange struct {
 	start uint64
 	end   uint64
@@ -42,6 +53,10 @@ type eventRange struct {
 // If needed, we calculate the id of the event based on slice positions.
 //
 // Retrieving the records can be achieved with GetEventsFromID.
+//
+// Optionally records are also removed when they are older than the maximum age, or when their record type uses
+// more than its quota (a percentage of the capacity). Records removed before they are overwritten leave an empty
+// slot behind which is skipped when reading, the IDs of the remaining records do not change.
 type eventRingBuffer struct {
 	events       []*si.EventRecord
 	capacity     uint64 // capacity of the buffer
@@ -51,23 +66,117 @@ type eventRingBuffer struct {
 	lowestId     uint64 // lowest id of an event record available in the buffer at any given time
 	resizeOffset uint64 // used to aid the calculation of id->pos after resize (see id2pos)
 
+	maxAge     time.Duration                    // maximum age of a record, 0 means no limit
+	quotas     map[si.EventRecord_Type]uint64   // percentage of the capacity a record type can use
+	typeLimits map[si.EventRecord_Type]uint64   // number of records a record type can use, calculated from the quotas
+	typeIDs    map[si.EventRecord_Type][]uint64 // IDs of the records in the buffer per record type, oldest first
+
 	locking.RWMutex
 }
 
 // Add adds an event to the ring buffer. If the buffer is full, the oldest element is overwritten.
-// This method never fails.
-func (e *eventRingBuffer) Add(event *si.EventRecord) {
+// This method never fails, it returns the ID assigned to the event.
+func (e *eventRingBuffer) Add(event *si.EventRecord) uint64 {
 	e.Lock()
 	defer e.Unlock()
 
+	id := e.id
+	// a record still in the slot is the oldest record in the buffer
+	if old := e.events[e.head]; old != nil {
+		e.removeOldestOfType(old.Type, evictionCapacity)
+	}
 	e.events[e.head] = event
 	if !e.full {
 		e.full = e.head == e.capacity-1
-	} else {
-		e.lowestId++
+	}
+	// records might have been removed by age: the lowest ID only moves if the new record needs the slot
+	if id-e.lowestId >= e.capacity {
+		e.lowestId = id - e.capacity + 1
 	}
 	e.head = (e.head + 1) % e.capacity
 	e.id++
+	e.typeIDs[event.Type] = append(e.typeIDs[event.Type], id)
+	e.enforceQuota(event.Type)
+	e.removeExpired(time.Now().UnixNano())
+	return id
+}
+
+// SetRetention sets the maximum age of the records and the quotas of the record types. The quota of a record
+// type is the percentage of the capacity it can use, types without a quota can use the whole buffer.
+// Records that are too old or over quota are removed immediately.
+func (e *eventRingBuffer) SetRetention(maxAge time.Duration, quotas map[si.EventRecord_Type]uint64) {
+	e.Lock()
+	defer e.Unlock()
+
+	e.maxAge = maxAge
+	e.quotas = quotas
+	e.updateTypeLimits()
+	e.removeExpired(time.Now().UnixNano())
+}
+
+// RemoveExpired removes the records that are older than the maximum age.
+// Expired records are also removed when a new record is added, this catches up on an idle buffer.
+func (e *eventRingBuffer) RemoveExpired() {
+	e.Lock()
+	defer e.Unlock()
+	e.removeExpired(time.Now().UnixNano())
+}
+
+// removeExpired removes the records older than the maximum age from the start of the buffer.
+// Records are ordered by creation time, the first record that has not expired ends the removal.
+// Must be called holding the lock.
+func (e *eventRingBuffer) removeExpired(now int64) {
+	if e.maxAge == 0 {
+		return
+	}
+	cutoff := now - e.maxAge.Nanoseconds()
+	for ; e.lowestId < e.id; e.lowestId++ {
+		pos, _ := e.id2pos(e.lowestId)
+		event := e.events[pos]
+		if event == nil {
+			continue
+		}
+		if event.TimestampNano >= cutoff {
+			return
+		}
+		e.events[pos] = nil
+		e.removeOldestOfType(event.Type, evictionAge)
+	}
+}
+
+// enforceQuota removes the oldest records of the record type until it is within its quota.
+// Must be called holding the lock.
+func (e *eventRingBuffer) enforceQuota(eventType si.EventRecord_Type) {
+	limit, ok := e.typeLimits[eventType]
+	if !ok {
+		return
+	}
+	for uint64(len(e.typeIDs[eventType])) > limit {
+		if pos, found := e.id2pos(e.typeIDs[eventType][0]); found {
+			e.events[pos] = nil
+		}
+		e.removeOldestOfType(eventType, evictionQuota)
+	}
+}
+
+// removeOldestOfType drops the oldest ID of the record type from the type index and updates the eviction metrics.
+// Must be called holding the lock.
+func (e *eventRingBuffer) removeOldestOfType(eventType si.EventRecord_Type, reason string) {
+	if ids := e.typeIDs[eventType]; len(ids) > 0 {
+		e.typeIDs[eventType] = ids[1:]
+	}
+	metrics.GetEventMetrics().IncEventsEvicted(strings.ToLower(eventType.String()), reason)
+}
+
+// updateTypeLimits calculates the number of records per type from the quotas and the capacity, a record type
+// with a quota can always keep at least one record. Records over the new limits are removed.
+// Must be called holding the lock.
+func (e *eventRingBuffer) updateTypeLimits() {
+	e.typeLimits = make(map[si.EventRecord_Type]uint64, len(e.quotas))
+	for eventType, quota := range e.quotas {
+		e.typeLimits[eventType] = max(e.capacity*quota/100, 1)
+		e.enforceQuota(eventType)
+	}
 }
 
 // GetRecentEvents returns the most recent "count" elements from the ring buffer.
@@ -88,6 +197,49 @@ func (e *eventRingBuffer) GetRecentEvents(count uint64) []*si.EventRecord {
 	return history
 }
 
+// GetRecentStreamEvents returns the most recent "count" elements from the ring buffer that match the filter,
+// together with their IDs. The whole buffer is scanned if needed, the events are ordered from oldest to newest.
+func (e *eventRingBuffer) GetRecentStreamEvents(count uint64, filter *EventFilter) []*StreamEvent {
+	e.RLock()
+	defer e.RUnlock()
+
+	var history []*StreamEvent
+	for id := e.id; id > e.getLowestID() && uint64(len(history)) < count; id-- {
+		pos, _ := e.id2pos(id - 1)
+		if event := e.events[pos]; event != nil && filter.Matches(event) {
+			history = append(history, &StreamEvent{ID: id - 1, Event: event})
+		}
+	}
+	slices.Reverse(history)
+	return history
+}
+
+// GetStreamEventsFromID returns all elements from "id" onwards that match the filter, together with their IDs.
+// If "id" is no longer or not yet in the buffer, all events in the buffer are returned and the gap between
+// the requested and the lowest available ID is returned. The gap is nil if no events were missed.
+// Requesting the ID that the next event will get is valid and returns no events and no gap.
+func (e *eventRingBuffer) GetStreamEventsFromID(id uint64, filter *EventFilter) ([]*StreamEvent, *EventGap) {
+	e.RLock()
+	defer e.RUnlock()
+
+	var gap *EventGap
+	if id < e.getLowestID() || id > e.id {
+		gap = &EventGap{
+			RequestedID: id,
+			LowestID:    e.getLowestID(),
+		}
+		id = e.getLowestID()
+	}
+	var history []*StreamEvent
+	for ; id < e.id; id++ {
+		pos, _ := e.id2pos(id)
+		if event := e.events[pos]; event != nil && filter.Matches(event) {
+			history = append(history, &StreamEvent{ID: id, Event: event})
+		}
+	}
+	return history, gap
+}
+
 // GetEventsFromID returns "count" number of event records from id if possible. The id can be determined from
 // the first call of the method - if it returns nothing because the id is not in the buffer, the lowest valid
 // identifier is returned which can be used to get the first batch.
@@ -160,11 +312,12 @@ func (e *eventRingBuffer) getLastEventID() uint64 {
 // ranges if the buffer is full and the requested start position is behind the current head.
 // Example: a buffer of capacity 20 is wrapped, head is at 10, and we want events from position 15. This means
 // two ranges (15->20, 0->9).
+// Empty slots of records removed by age or quota are not returned.
 func (e *eventRingBuffer) getEntriesFromRanges(r1, r2 *eventRange) []*si.EventRecord {
 	if r2 == nil {
 		dst := make([]*si.EventRecord, r1.end-r1.start)
 		copy(dst, e.events[r1.start:])
-		return dst
+		return slices.DeleteFunc(dst, isRemoved)
 	}
 
 	total := (r1.end - r1.start) + (r2.end - r2.start)
@@ -172,7 +325,11 @@ func (e *eventRingBuffer) getEntriesFromRanges(r1, r2 *eventRange) []*si.EventRe
 	copy(dst, e.events[r1.start:])
 	nextIdx := r1.end - r1.start
 	copy(dst[nextIdx:], e.events[r2.start:])
-	return dst
+	return slices.DeleteFunc(dst, isRemoved)
+}
+
+func isRemoved(event *si.EventRecord) bool {
+	return event == nil
 }
 
 // id2pos translates the unique event ID to an index in the event slice.
@@ -199,6 +356,7 @@ func newEventRingBuffer(capacity uint64) *eventRingBuffer {
 	return &eventRingBuffer{
 		capacity: capacity,
 		events:   make([]*si.EventRecord, capacity),
+		typeIDs:  make(map[si.EventRecord_Type][]uint64),
 	}
 }
 
@@ -237,6 +395,14 @@ func (e *eventRingBuffer) Resize(newSize uint64) {
 	startIndex := (e.head + e.capacity - numEventsToCopy) % e.capacity
 	endIndex := (startIndex + numEventsToCopy - 1) % e.capacity
 
+	// records that do not fit in the new buffer are evicted
+	for id := e.getLowestID(); id < e.id-numEventsToCopy; id++ {
+		pos, _ := e.id2pos(id)
+		if event := e.events[pos]; event != nil {
+			e.removeOldestOfType(event.Type, evictionCapacity)
+		}
+	}
+
 	prevLowestId := e.getLowestID()
 	e.updateLowestID(initialSize, newSize)
 	newLowestId := e.getLowestID()
@@ -250,11 +416,14 @@ func (e *eventRingBuffer) Resize(newSize uint64) {
 	// Copy existing events to the new buffer
 	// We determine the range of elements to copy based on the relative positions of the head and tail in the circular buffer.
 	// If the tail is ahead of the head (wrap-around scenario), we copy in two steps to ensure the correct order.
-	if endIndex >= startIndex {
-		copy(newEvents, e.events[startIndex:endIndex+1])
-	} else {
-		copy(newEvents, e.events[startIndex:])
-		copy(newEvents[e.capacity-startIndex:], e.events[:endIndex+1])
+	// Nothing is copied if all records were removed by age.
+	if numEventsToCopy > 0 {
+		if endIndex >= startIndex {
+			copy(newEvents, e.events[startIndex:endIndex+1])
+		} else {
+			copy(newEvents, e.events[startIndex:])
+			copy(newEvents[e.capacity-startIndex:], e.events[:endIndex+1])
+		}
 	}
 
 	e.capacity = newSize
@@ -262,4 +431,5 @@ func (e *eventRingBuffer) Resize(newSize uint64) {
 	e.head = numEventsToCopy % newSize
 	e.resizeOffset = e.lowestId
 	e.full = numEventsToCopy == e.capacity
+	e.updateTypeLimits()
 }
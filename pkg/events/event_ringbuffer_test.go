@@ -21,9 +21,11 @@ package events
 import (
 	"math"
 	"testing"
+	"time"
 
 	"gotest.tools/v3/assert"
 
+	"github.com/G-Research/yunikorn-core/pkg/metrics"
 	"github.com/G-Research/yunikorn-scheduler-interface/lib/go/si"
 )
 
@@ -37,6 +39,10 @@ func TestRingBuffer_New(t *testing.T) {
 func TestRingBuffer_Add(t *testing.T) {
 	buffer := newEventRingBuffer(10)
 	populate(buffer, 4)
+	assert.Equal(t, uint64(4), buffer.Add(&si.EventRecord{}))
+	assert.Equal(t, uint64(5), buffer.head)
+	buffer = newEventRingBuffer(10)
+	populate(buffer, 4)
 
 	assert.Equal(t, uint64(4), buffer.head)
 	assert.Equal(t, false, buffer.full)
@@ -310,6 +316,232 @@ func TestGetRecentEvents(t *testing.T) {
 	assert.Equal(t, int64(4), records[4].TimestampNano)
 }
 
+func TestGetRecentStreamEvents(t *testing.T) {
+	buffer := newEventRingBuffer(10)
+	// wrap the buffer: ids 0-2 are overwritten, odd timestamps are node events
+	for i := 0; i < 13; i++ {
+		eventType := si.EventRecord_APP
+		if i%2 == 1 {
+			eventType = si.EventRecord_NODE
+		}
+		buffer.Add(&si.EventRecord{
+			Type:          eventType,
+			TimestampNano: int64(i),
+		})
+	}
+	filter := &EventFilter{Types: []si.EventRecord_Type{si.EventRecord_NODE}}
+
+	// count < matching elements
+	records := buffer.GetRecentStreamEvents(2, filter)
+	assert.Equal(t, 2, len(records))
+	assert.Equal(t, uint64(9), records[0].ID)
+	assert.Equal(t, int64(9), records[0].Event.TimestampNano)
+	assert.Equal(t, uint64(11), records[1].ID)
+
+	// count > matching elements: only the events still in the buffer
+	records = buffer.GetRecentStreamEvents(15, filter)
+	assert.Equal(t, 5, len(records))
+	assert.Equal(t, uint64(3), records[0].ID)
+	assert.Equal(t, uint64(11), records[4].ID)
+
+	// no match
+	records = buffer.GetRecentStreamEvents(15, &EventFilter{Types: []si.EventRecord_Type{si.EventRecord_QUEUE}})
+	assert.Equal(t, 0, len(records))
+
+	// nil filter
+	records = buffer.GetRecentStreamEvents(3, nil)
+	assert.Equal(t, 3, len(records))
+	assert.Equal(t, uint64(10), records[0].ID)
+	assert.Equal(t, int64(10), records[0].Event.TimestampNano)
+
+	// zero count
+	records = buffer.GetRecentStreamEvents(0, nil)
+	assert.Equal(t, 0, len(records))
+}
+
+func TestGetStreamEventsFromID(t *testing.T) {
+	// empty
+	buffer := newEventRingBuffer(10)
+	records, gap := buffer.GetStreamEventsFromID(0, nil)
+	assert.Equal(t, 0, len(records))
+	assert.Assert(t, gap == nil)
+
+	// wrap the buffer: ids 0-2 are overwritten
+	populate(buffer, 13)
+	records, gap = buffer.GetStreamEventsFromID(5, nil)
+	assert.Assert(t, gap == nil)
+	assert.Equal(t, 8, len(records))
+	assert.Equal(t, uint64(5), records[0].ID)
+	assert.Equal(t, int64(5), records[0].Event.TimestampNano)
+	assert.Equal(t, uint64(12), records[7].ID)
+
+	// filtered
+	records, gap = buffer.GetStreamEventsFromID(5, &EventFilter{ObjectIDs: []string{"unknown"}})
+	assert.Assert(t, gap == nil)
+	assert.Equal(t, 0, len(records))
+
+	// next id
+	records, gap = buffer.GetStreamEventsFromID(13, nil)
+	assert.Assert(t, gap == nil)
+	assert.Equal(t, 0, len(records))
+
+	// evicted id
+	records, gap = buffer.GetStreamEventsFromID(1, nil)
+	assert.DeepEqual(t, &EventGap{RequestedID: 1, LowestID: 3}, gap)
+	assert.Equal(t, 10, len(records))
+	assert.Equal(t, uint64(3), records[0].ID)
+
+	// id in the future
+	records, gap = buffer.GetStreamEventsFromID(20, nil)
+	assert.DeepEqual(t, &EventGap{RequestedID: 20, LowestID: 3}, gap)
+	assert.Equal(t, 10, len(records))
+}
+
+func TestRingBuffer_MaxAge(t *testing.T) {
+	metrics.GetEventMetrics().Reset()
+	buffer := newEventRingBuffer(10)
+	old := time.Now().Add(-time.Hour).UnixNano()
+	for i := 0; i < 3; i++ {
+		buffer.Add(&si.EventRecord{Type: si.EventRecord_NODE, TimestampNano: old})
+	}
+	buffer.Add(&si.EventRecord{Type: si.EventRecord_APP, TimestampNano: time.Now().UnixNano()})
+
+	// old records are removed when the retention is set
+	buffer.SetRetention(time.Minute, nil)
+	records, lowest, highest := buffer.GetEventsFromID(3, math.MaxUint64)
+	assert.Equal(t, 1, len(records))
+	assert.Equal(t, uint64(3), lowest)
+	assert.Equal(t, uint64(3), highest)
+	assert.Equal(t, si.EventRecord_APP, records[0].Type)
+	evicted, err := metrics.GetEventMetrics().GetEventsEvicted("node", evictionAge)
+	assert.NilError(t, err)
+	assert.Equal(t, 3, evicted)
+	_, gap := buffer.GetStreamEventsFromID(0, nil)
+	assert.DeepEqual(t, &EventGap{RequestedID: 0, LowestID: 3}, gap)
+
+	// an expired record behind a newer record stays until the newer record expires
+	assert.Equal(t, uint64(4), buffer.Add(&si.EventRecord{Type: si.EventRecord_APP, TimestampNano: old}))
+	records, lowest, _ = buffer.GetEventsFromID(3, math.MaxUint64)
+	assert.Equal(t, 2, len(records))
+	assert.Equal(t, uint64(3), lowest)
+
+	// expire everything: the buffer is empty but keeps the ids
+	buffer.maxAge = time.Nanosecond
+	buffer.RemoveExpired()
+	records, lowest, highest = buffer.GetEventsFromID(3, math.MaxUint64)
+	assert.Equal(t, 0, len(records))
+	assert.Equal(t, uint64(5), lowest)
+	assert.Equal(t, uint64(4), highest)
+	buffer.Resize(5)
+	assert.Equal(t, uint64(5), buffer.Add(&si.EventRecord{TimestampNano: time.Now().Add(time.Hour).UnixNano()}))
+	records, lowest, _ = buffer.GetEventsFromID(5, math.MaxUint64)
+	assert.Equal(t, 1, len(records))
+	assert.Equal(t, uint64(5), lowest)
+}
+
+func TestRingBuffer_MaxAge_Wrapped(t *testing.T) {
+	buffer := newEventRingBuffer(5)
+	buffer.SetRetention(time.Minute, nil)
+	old := time.Now().Add(-time.Hour).UnixNano()
+	for i := 0; i < 7; i++ {
+		buffer.Add(&si.EventRecord{TimestampNano: time.Now().UnixNano()})
+	}
+	assert.Equal(t, uint64(2), buffer.getLowestID())
+	// move the timestamps of the oldest records back
+	for id := uint64(2); id < 5; id++ {
+		pos, _ := buffer.id2pos(id)
+		buffer.events[pos].TimestampNano = old
+	}
+	buffer.RemoveExpired()
+	records, gap := buffer.GetStreamEventsFromID(2, nil)
+	assert.DeepEqual(t, &EventGap{RequestedID: 2, LowestID: 5}, gap)
+	assert.Equal(t, 2, len(records))
+	assert.Equal(t, uint64(5), records[0].ID)
+	// the empty slots are reused without counting an eviction
+	for i := 0; i < 3; i++ {
+		buffer.Add(&si.EventRecord{TimestampNano: time.Now().UnixNano()})
+	}
+	assert.Equal(t, uint64(5), buffer.getLowestID())
+	history, lowest, highest := buffer.GetEventsFromID(5, math.MaxUint64)
+	assert.Equal(t, 5, len(history))
+	assert.Equal(t, uint64(5), lowest)
+	assert.Equal(t, uint64(9), highest)
+}
+
+func TestRingBuffer_Quota(t *testing.T) {
+	metrics.GetEventMetrics().Reset()
+	buffer := newEventRingBuffer(10)
+	buffer.SetRetention(0, map[si.EventRecord_Type]uint64{si.EventRecord_NODE: 30})
+	addTyped(buffer, si.EventRecord_APP, 2)
+	addTyped(buffer, si.EventRecord_NODE, 5)
+	addTyped(buffer, si.EventRecord_APP, 1)
+
+	// the two oldest node events are removed, the ids are not changed
+	records, gap := buffer.GetStreamEventsFromID(0, nil)
+	assert.Assert(t, gap == nil)
+	ids := make([]uint64, 0)
+	for _, record := range records {
+		ids = append(ids, record.ID)
+	}
+	assert.DeepEqual(t, []uint64{0, 1, 4, 5, 6, 7}, ids)
+	history, lowest, highest := buffer.GetEventsFromID(0, math.MaxUint64)
+	assert.Equal(t, 6, len(history))
+	assert.Equal(t, uint64(0), lowest)
+	assert.Equal(t, uint64(7), highest)
+	assert.Equal(t, 2, len(buffer.GetRecentStreamEvents(2, &EventFilter{Types: []si.EventRecord_Type{si.EventRecord_NODE}})))
+	evicted, err := metrics.GetEventMetrics().GetEventsEvicted("node", evictionQuota)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, evicted)
+
+	// wrap the buffer: only the records still in the buffer count as capacity evictions
+	addTyped(buffer, si.EventRecord_APP, 5)
+	evicted, err = metrics.GetEventMetrics().GetEventsEvicted("app", evictionCapacity)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, evicted)
+	evicted, err = metrics.GetEventMetrics().GetEventsEvicted("node", evictionCapacity)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, evicted)
+	history, lowest, _ = buffer.GetEventsFromID(3, math.MaxUint64)
+	assert.Equal(t, 9, len(history))
+	assert.Equal(t, uint64(3), lowest)
+
+	// removing the quota keeps all records of the type
+	buffer.SetRetention(0, nil)
+	addTyped(buffer, si.EventRecord_NODE, 4)
+	assert.Equal(t, 4, len(buffer.typeIDs[si.EventRecord_NODE]))
+}
+
+func TestRingBuffer_Quota_Resize(t *testing.T) {
+	metrics.GetEventMetrics().Reset()
+	buffer := newEventRingBuffer(10)
+	buffer.SetRetention(0, map[si.EventRecord_Type]uint64{si.EventRecord_NODE: 50})
+	addTyped(buffer, si.EventRecord_NODE, 5)
+	assert.Equal(t, 5, len(buffer.typeIDs[si.EventRecord_NODE]))
+
+	// shrinking the buffer drops one record by capacity, the smaller quota drops two more
+	buffer.Resize(4)
+	assert.DeepEqual(t, []uint64{3, 4}, buffer.typeIDs[si.EventRecord_NODE])
+	evicted, err := metrics.GetEventMetrics().GetEventsEvicted("node", evictionCapacity)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, evicted)
+	evicted, err = metrics.GetEventMetrics().GetEventsEvicted("node", evictionQuota)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, evicted)
+	history, lowest, highest := buffer.GetEventsFromID(1, math.MaxUint64)
+	assert.Equal(t, 2, len(history))
+	assert.Equal(t, uint64(1), lowest)
+	assert.Equal(t, uint64(4), highest)
+}
+
+func addTyped(buffer *eventRingBuffer, eventType si.EventRecord_Type, count int) {
+	for i := 0; i < count; i++ {
+		buffer.Add(&si.EventRecord{
+			Type:          eventType,
+			TimestampNano: time.Now().UnixNano(),
+		})
+	}
+}
+
 func populate(buffer *eventRingBuffer, count int) {
 	for i := 0; i < count; i++ {
 		buffer.Add(&si.EventRecord{
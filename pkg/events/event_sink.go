@@ -0,0 +1,293 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package events
+
+import (
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/G-Research/yunikorn-core/pkg/locking"
+	"github.com/G-Research/yunikorn-core/pkg/log"
+	"github.com/G-Research/yunikorn-scheduler-interface/lib/go/si"
+)
+
+const (
+	defaultSinkBatchSize      = 100
+	defaultSinkFlushInterval  = time.Second
+	defaultSinkQueueSize      = 10000
+	defaultSinkInitialBackoff = 100 * time.Millisecond
+	defaultSinkMaxBackoff     = 10 * time.Second
+)
+
+// EventSink delivers batches of event records to a destination outside the scheduler.
+// Send is called from a single goroutine per registered sink, a returned error triggers a retry of the batch.
+type EventSink interface {
+	Send(records []*si.EventRecord) error
+	Close() error
+}
+
+// SinkOptions the delivery settings of a registered sink. Zero values use the defaults, except for MaxRetries:
+// a batch that fails is not retried if it is 0.
+type SinkOptions struct {
+	Filter         *EventFilter  // records passed to the sink, nil passes all records
+	BatchSize      int           // maximum number of records per Send
+	FlushInterval  time.Duration // a partial batch is sent after this interval
+	QueueSize      int           // records queued for the sink, new records are dropped when the queue is full
+	MaxRetries     int           // retries of a failed batch before it is dead-lettered
+	InitialBackoff time.Duration // wait before the first retry, doubled for each following retry
+	MaxBackoff     time.Duration // maximum wait between retries
+}
+
+// EventSinkStatus the delivery counters of a registered sink.
+type EventSinkStatus struct {
+	Name         string
+	Sent         uint64 // records delivered
+	Dropped      uint64 // records dropped because the queue was full
+	Failed       uint64 // failed Send calls, including the ones that were retried
+	DeadLettered uint64 // records not delivered after all retries
+}
+
+var eventSinks = &sinkRegistry{
+	sinks: make(map[string]*registeredSink),
+}
+
+// sinkRegistry the named sinks that receive the records collected by the shim publisher.
+type sinkRegistry struct {
+	sinks map[string]*registeredSink
+	locking.RWMutex
+}
+
+// registeredSink queues the records for one sink and delivers them in batches from its own goroutine, a slow or
+// failing sink does not affect the scheduler or the other sinks.
+type registeredSink struct {
+	name    string
+	sink    EventSink
+	options SinkOptions
+	queue   chan *si.EventRecord
+	stop    chan struct{}
+	stopped chan struct{}
+
+	sent         atomic.Uint64
+	dropped      atomic.Uint64
+	failed       atomic.Uint64
+	deadLettered atomic.Uint64
+}
+
+// RegisterEventSink registers and starts a named sink. The sink receives the same records as the shim publisher,
+// after applying the filter of the options. Returns an error if a sink with the name is already registered.
+func RegisterEventSink(name string, sink EventSink, options SinkOptions) error {
+	if name == "" || sink == nil {
+		return fmt.Errorf("event sink must have a name and an implementation")
+	}
+	eventSinks.Lock()
+	defer eventSinks.Unlock()
+	if _, ok := eventSinks.sinks[name]; ok {
+		return fmt.Errorf("event sink %s is already registered", name)
+	}
+	rs := newRegisteredSink(name, sink, options)
+	eventSinks.sinks[name] = rs
+	go rs.run()
+	log.Log(log.Events).Info("Registered event sink", zap.String("name", name))
+	return nil
+}
+
+// UnregisterEventSink stops and closes the named sink. Queued records are sent once, without retries.
+// Returns false if no sink with the name is registered.
+func UnregisterEventSink(name string) bool {
+	eventSinks.Lock()
+	rs, ok := eventSinks.sinks[name]
+	delete(eventSinks.sinks, name)
+	eventSinks.Unlock()
+	if !ok {
+		return false
+	}
+	rs.close()
+	log.Log(log.Events).Info("Unregistered event sink", zap.String("name", name))
+	return true
+}
+
+// UnregisterAllEventSinks stops and closes all registered sinks.
+func UnregisterAllEventSinks() {
+	eventSinks.RLock()
+	names := make([]string, 0, len(eventSinks.sinks))
+	for name := range eventSinks.sinks {
+		names = append(names, name)
+	}
+	eventSinks.RUnlock()
+	for _, name := range names {
+		UnregisterEventSink(name)
+	}
+}
+
+// GetEventSinkStatus returns the delivery counters of the registered sinks, sorted by name.
+func GetEventSinkStatus() []EventSinkStatus {
+	eventSinks.RLock()
+	defer eventSinks.RUnlock()
+	status := make([]EventSinkStatus, 0, len(eventSinks.sinks))
+	for _, rs := range eventSinks.sinks {
+		status = append(status, rs.status())
+	}
+	sort.Slice(status, func(i, j int) bool {
+		return status[i].Name < status[j].Name
+	})
+	return status
+}
+
+// publishToSinks queues the records for all registered sinks. Never blocks.
+func publishToSinks(records []*si.EventRecord) {
+	eventSinks.RLock()
+	defer eventSinks.RUnlock()
+	for _, rs := range eventSinks.sinks {
+		rs.add(records)
+	}
+}
+
+func newRegisteredSink(name string, sink EventSink, options SinkOptions) *registeredSink {
+	if options.BatchSize <= 0 {
+		options.BatchSize = defaultSinkBatchSize
+	}
+	if options.FlushInterval <= 0 {
+		options.FlushInterval = defaultSinkFlushInterval
+	}
+	if options.QueueSize <= 0 {
+		options.QueueSize = defaultSinkQueueSize
+	}
+	if options.MaxRetries < 0 {
+		options.MaxRetries = 0
+	}
+	if options.InitialBackoff <= 0 {
+		options.InitialBackoff = defaultSinkInitialBackoff
+	}
+	if options.MaxBackoff < options.InitialBackoff {
+		options.MaxBackoff = max(defaultSinkMaxBackoff, options.InitialBackoff)
+	}
+	return &registeredSink{
+		name:    name,
+		sink:    sink,
+		options: options,
+		queue:   make(chan *si.EventRecord, options.QueueSize),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+}
+
+func (rs *registeredSink) add(records []*si.EventRecord) {
+	for _, record := range records {
+		if !rs.options.Filter.Matches(record) {
+			continue
+		}
+		select {
+		case rs.queue <- record:
+		default:
+			rs.dropped.Add(1)
+		}
+	}
+}
+
+func (rs *registeredSink) status() EventSinkStatus {
+	return EventSinkStatus{
+		Name:         rs.name,
+		Sent:         rs.sent.Load(),
+		Dropped:      rs.dropped.Load(),
+		Failed:       rs.failed.Load(),
+		DeadLettered: rs.deadLettered.Load(),
+	}
+}
+
+// close stops the delivery goroutine and closes the sink.
+func (rs *registeredSink) close() {
+	close(rs.stop)
+	<-rs.stopped
+	if err := rs.sink.Close(); err != nil {
+		log.Log(log.Events).Warn("Cannot close event sink",
+			zap.String("name", rs.name),
+			zap.Error(err))
+	}
+}
+
+func (rs *registeredSink) run() {
+	defer close(rs.stopped)
+	ticker := time.NewTicker(rs.options.FlushInterval)
+	defer ticker.Stop()
+	batch := make([]*si.EventRecord, 0, rs.options.BatchSize)
+	for {
+		select {
+		case record := <-rs.queue:
+			batch = append(batch, record)
+			if len(batch) >= rs.options.BatchSize {
+				rs.deliver(batch, true)
+				batch = make([]*si.EventRecord, 0, rs.options.BatchSize)
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				rs.deliver(batch, true)
+				batch = make([]*si.EventRecord, 0, rs.options.BatchSize)
+			}
+		case <-rs.stop:
+			for {
+				select {
+				case record := <-rs.queue:
+					batch = append(batch, record)
+					if len(batch) >= rs.options.BatchSize {
+						rs.deliver(batch, false)
+						batch = make([]*si.EventRecord, 0, rs.options.BatchSize)
+					}
+				default:
+					if len(batch) > 0 {
+						rs.deliver(batch, false)
+					}
+					return
+				}
+			}
+		}
+	}
+}
+
+// deliver sends the batch, retrying with exponential backoff. The records are dead-lettered if all attempts fail
+// or the sink is stopped while waiting for a retry.
+func (rs *registeredSink) deliver(batch []*si.EventRecord, retry bool) {
+	backoff := rs.options.InitialBackoff
+	for attempt := 0; ; attempt++ {
+		err := rs.sink.Send(batch)
+		if err == nil {
+			rs.sent.Add(uint64(len(batch)))
+			return
+		}
+		rs.failed.Add(1)
+		if !retry || attempt >= rs.options.MaxRetries {
+			rs.deadLettered.Add(uint64(len(batch)))
+			log.Log(log.Events).Warn("Event sink failed, records dead-lettered",
+				zap.String("name", rs.name),
+				zap.Int("records", len(batch)),
+				zap.Int("attempts", attempt+1),
+				zap.Error(err))
+			return
+		}
+		select {
+		case <-time.After(backoff):
+		case <-rs.stop:
+			retry = false
+		}
+		backoff = min(backoff*2, rs.options.MaxBackoff)
+	}
+}
@@ -0,0 +1,216 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package events
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/G-Research/yunikorn-core/pkg/common/configs"
+	"github.com/G-Research/yunikorn-core/pkg/log"
+	"github.com/G-Research/yunikorn-scheduler-interface/lib/go/si"
+)
+
+// properties of a sink defined in the configuration, relative to event.sink.<name>.
+const (
+	sinkPropertyType           = "type"
+	sinkPropertyURL            = "url"
+	sinkPropertyPath           = "path"
+	sinkPropertyHeaderPrefix   = "header."
+	sinkPropertyTimeout        = "timeout"
+	sinkPropertyTypes          = "types"
+	sinkPropertyBatchSize      = "batchSize"
+	sinkPropertyFlushInterval  = "flushInterval"
+	sinkPropertyQueueSize      = "queueSize"
+	sinkPropertyMaxRetries     = "maxRetries"
+	sinkPropertyInitialBackoff = "initialBackoff"
+	sinkPropertyMaxBackoff     = "maxBackoff"
+
+	sinkTypeWebhook = "webhook"
+	sinkTypeFile    = "file"
+	sinkTypeSocket  = "socket"
+)
+
+// getConfiguredSinks returns the properties of the sinks defined in the configuration by sink name.
+func getConfiguredSinks() map[string]map[string]string {
+	sinks := make(map[string]map[string]string)
+	for key, value := range configs.GetConfigMap() {
+		rest, ok := strings.CutPrefix(key, configs.CMEventSinkPrefix)
+		if !ok {
+			continue
+		}
+		name, property, ok := strings.Cut(rest, ".")
+		if !ok || name == "" || property == "" {
+			log.Log(log.Events).Warn("Event sink property must be set as event.sink.<name>.<property>",
+				zap.String("key", key))
+			continue
+		}
+		if sinks[name] == nil {
+			sinks[name] = make(map[string]string)
+		}
+		sinks[name][property] = value
+	}
+	return sinks
+}
+
+// newConfiguredSink creates the sink and its delivery options from the properties of a sink in the configuration.
+func newConfiguredSink(properties map[string]string) (EventSink, SinkOptions, error) {
+	options := SinkOptions{
+		MaxRetries: int(configs.DefaultEventSinkMaxRetries),
+	}
+	var err error
+	var timeout time.Duration
+	if timeout, err = parseSinkDuration(properties, sinkPropertyTimeout); err != nil {
+		return nil, options, err
+	}
+	if options.FlushInterval, err = parseSinkDuration(properties, sinkPropertyFlushInterval); err != nil {
+		return nil, options, err
+	}
+	if options.InitialBackoff, err = parseSinkDuration(properties, sinkPropertyInitialBackoff); err != nil {
+		return nil, options, err
+	}
+	if options.MaxBackoff, err = parseSinkDuration(properties, sinkPropertyMaxBackoff); err != nil {
+		return nil, options, err
+	}
+	if options.BatchSize, err = parseSinkInt(properties, sinkPropertyBatchSize, 0); err != nil {
+		return nil, options, err
+	}
+	if options.QueueSize, err = parseSinkInt(properties, sinkPropertyQueueSize, 0); err != nil {
+		return nil, options, err
+	}
+	if options.MaxRetries, err = parseSinkInt(properties, sinkPropertyMaxRetries, options.MaxRetries); err != nil {
+		return nil, options, err
+	}
+	if value := properties[sinkPropertyTypes]; value != "" {
+		options.Filter = &EventFilter{}
+		for _, name := range strings.Split(value, ",") {
+			eventType, ok := si.EventRecord_Type_value[strings.ToUpper(strings.TrimSpace(name))]
+			if !ok {
+				return nil, options, fmt.Errorf("unknown event record type %q", name)
+			}
+			options.Filter.Types = append(options.Filter.Types, si.EventRecord_Type(eventType))
+		}
+	}
+
+	var sink EventSink
+	switch sinkType := strings.ToLower(properties[sinkPropertyType]); sinkType {
+	case sinkTypeWebhook:
+		url := properties[sinkPropertyURL]
+		if url == "" {
+			return nil, options, fmt.Errorf("webhook event sink requires the %s property", sinkPropertyURL)
+		}
+		headers := make(map[string]string)
+		for property, value := range properties {
+			if header, ok := strings.CutPrefix(property, sinkPropertyHeaderPrefix); ok && header != "" {
+				headers[header] = value
+			}
+		}
+		sink = NewWebhookEventSink(url, headers, timeout)
+	case sinkTypeFile:
+		path := properties[sinkPropertyPath]
+		if path == "" {
+			return nil, options, fmt.Errorf("file event sink requires the %s property", sinkPropertyPath)
+		}
+		if sink, err = NewLocalFileEventSink(path); err != nil {
+			return nil, options, err
+		}
+	case sinkTypeSocket:
+		path := properties[sinkPropertyPath]
+		if path == "" {
+			return nil, options, fmt.Errorf("socket event sink requires the %s property", sinkPropertyPath)
+		}
+		sink = NewUnixSocketEventSink(path, timeout)
+	default:
+		return nil, options, fmt.Errorf("unknown event sink type %q", sinkType)
+	}
+	return sink, options, nil
+}
+
+func parseSinkDuration(properties map[string]string, property string) (time.Duration, error) {
+	value, ok := properties[property]
+	if !ok {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("invalid duration %q for %s", value, property)
+	}
+	return duration, nil
+}
+
+func parseSinkInt(properties map[string]string, property string, defaultValue int) (int, error) {
+	value, ok := properties[property]
+	if !ok {
+		return defaultValue, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("invalid number %q for %s", value, property)
+	}
+	return number, nil
+}
+
+// updateConfiguredSinks registers the sinks defined in the configuration. A sink whose properties changed is
+// replaced, a sink that is no longer defined is unregistered. Sinks registered by code are not touched.
+// Must be called holding the event system lock.
+func (ec *EventSystemImpl) updateConfiguredSinks() {
+	configured := getConfiguredSinks()
+	for name, properties := range ec.configuredSinks {
+		if current, ok := configured[name]; ok && maps.Equal(properties, current) {
+			continue
+		}
+		UnregisterEventSink(name)
+		delete(ec.configuredSinks, name)
+	}
+	if ec.configuredSinks == nil {
+		ec.configuredSinks = make(map[string]map[string]string)
+	}
+	for name, properties := range configured {
+		if _, ok := ec.configuredSinks[name]; ok {
+			continue
+		}
+		sink, options, err := newConfiguredSink(properties)
+		if err == nil {
+			if err = RegisterEventSink(name, sink, options); err != nil {
+				_ = sink.Close() //nolint:errcheck
+			}
+		}
+		if err != nil {
+			log.Log(log.Events).Error("Cannot register event sink from the configuration",
+				zap.String("name", name),
+				zap.Error(err))
+			continue
+		}
+		ec.configuredSinks[name] = properties
+	}
+}
+
+// removeConfiguredSinks unregisters all sinks defined in the configuration.
+// Must be called holding the event system lock.
+func (ec *EventSystemImpl) removeConfiguredSinks() {
+	for name := range ec.configuredSinks {
+		UnregisterEventSink(name)
+	}
+	ec.configuredSinks = nil
+}
@@ -0,0 +1,200 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package events
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/G-Research/yunikorn-core/pkg/common/configs"
+	"github.com/G-Research/yunikorn-scheduler-interface/lib/go/si"
+)
+
+func TestGetConfiguredSinks(t *testing.T) {
+	original := configs.GetConfigMap()
+	defer configs.SetConfigMap(original)
+	configs.SetConfigMap(map[string]string{
+		configs.CMEventTrackingEnabled:         "true",
+		"event.sink.hook.type":                 "webhook",
+		"event.sink.hook.url":                  "http://localhost/events",
+		"event.sink.hook.header.Authorization": "Bearer token",
+		"event.sink.local.type":                "file",
+		"event.sink.invalid":                   "webhook",
+		"event.sink..type":                     "webhook",
+	})
+	expected := map[string]map[string]string{
+		"hook": {
+			"type":                 "webhook",
+			"url":                  "http://localhost/events",
+			"header.Authorization": "Bearer token",
+		},
+		"local": {"type": "file"},
+	}
+	assert.DeepEqual(t, expected, getConfiguredSinks())
+}
+
+func TestNewConfiguredSink(t *testing.T) {
+	sink, options, err := newConfiguredSink(map[string]string{
+		"type":                 "Webhook",
+		"url":                  "http://localhost/events",
+		"header.Authorization": "Bearer token",
+		"timeout":              "5s",
+		"types":                "app, node",
+		"batchSize":            "10",
+		"flushInterval":        "2s",
+		"queueSize":            "50",
+		"maxRetries":           "0",
+		"initialBackoff":       "1s",
+		"maxBackoff":           "4s",
+	})
+	assert.NilError(t, err)
+	webhook, ok := sink.(*webhookSink)
+	assert.Assert(t, ok, "expected a webhook sink")
+	assert.Equal(t, "http://localhost/events", webhook.url)
+	assert.DeepEqual(t, map[string]string{"Authorization": "Bearer token"}, webhook.headers)
+	assert.Equal(t, 5*time.Second, webhook.client.Timeout)
+	assert.DeepEqual(t, []si.EventRecord_Type{si.EventRecord_APP, si.EventRecord_NODE}, options.Filter.Types)
+	options.Filter = nil
+	assert.DeepEqual(t, SinkOptions{
+		BatchSize:      10,
+		FlushInterval:  2 * time.Second,
+		QueueSize:      50,
+		MaxRetries:     0,
+		InitialBackoff: time.Second,
+		MaxBackoff:     4 * time.Second,
+	}, options)
+
+	sink, options, err = newConfiguredSink(map[string]string{"type": "socket", "path": "/tmp/events.sock"})
+	assert.NilError(t, err)
+	socket, ok := sink.(*unixSocketSink)
+	assert.Assert(t, ok, "expected a socket sink")
+	assert.Equal(t, "/tmp/events.sock", socket.path)
+	assert.Equal(t, defaultSinkTimeout, socket.timeout)
+	assert.Assert(t, options.Filter == nil)
+	assert.Equal(t, int(configs.DefaultEventSinkMaxRetries), options.MaxRetries)
+
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	sink, _, err = newConfiguredSink(map[string]string{"type": "file", "path": path})
+	assert.NilError(t, err)
+	_, ok = sink.(*localFileSink)
+	assert.Assert(t, ok, "expected a file sink")
+	assert.NilError(t, sink.Close())
+
+	invalid := map[string]map[string]string{
+		"no type":          {"url": "http://localhost/events"},
+		"unknown type":     {"type": "kafka"},
+		"webhook no url":   {"type": "webhook"},
+		"file no path":     {"type": "file"},
+		"socket no path":   {"type": "socket"},
+		"file not created": {"type": "file", "path": filepath.Join(t.TempDir(), "missing", "events.jsonl")},
+		"invalid duration": {"type": "socket", "path": "/tmp/events.sock", "timeout": "5"},
+		"negative number":  {"type": "socket", "path": "/tmp/events.sock", "batchSize": "-1"},
+		"invalid number":   {"type": "socket", "path": "/tmp/events.sock", "maxRetries": "x"},
+		"unknown types":    {"type": "socket", "path": "/tmp/events.sock", "types": "APP,POD"},
+	}
+	for name, properties := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := newConfiguredSink(properties)
+			assert.Assert(t, err != nil, "expected an error for %v", properties)
+		})
+	}
+}
+
+func TestUpdateConfiguredSinks(t *testing.T) {
+	original := configs.GetConfigMap()
+	defer configs.SetConfigMap(original)
+	dir := t.TempDir()
+	ec := &EventSystemImpl{}
+	defer ec.removeConfiguredSinks()
+
+	// code registered sinks are not touched
+	assert.NilError(t, RegisterEventSink("code", &testSink{}, SinkOptions{}))
+	defer UnregisterEventSink("code")
+
+	configs.SetConfigMap(map[string]string{
+		"event.sink.first.type":  "file",
+		"event.sink.first.path":  filepath.Join(dir, "first.jsonl"),
+		"event.sink.second.type": "socket",
+		"event.sink.second.path": filepath.Join(dir, "second.sock"),
+		"event.sink.broken.type": "kafka",
+		"event.sink.code.type":   "socket",
+		"event.sink.code.path":   filepath.Join(dir, "code.sock"),
+	})
+	ec.updateConfiguredSinks()
+	assert.DeepEqual(t, []string{"code", "first", "second"}, getSinkNames())
+	assert.Equal(t, 2, len(ec.configuredSinks))
+	first := getRegisteredSink(t, "first")
+
+	// unchanged sinks are kept, changed sinks replaced and removed sinks unregistered
+	configs.SetConfigMap(map[string]string{
+		"event.sink.first.type": "file",
+		"event.sink.first.path": filepath.Join(dir, "first.jsonl"),
+		"event.sink.third.type": "socket",
+		"event.sink.third.path": filepath.Join(dir, "third.sock"),
+	})
+	ec.updateConfiguredSinks()
+	assert.DeepEqual(t, []string{"code", "first", "third"}, getSinkNames())
+	assert.Equal(t, first, getRegisteredSink(t, "first"))
+	configs.SetConfigMap(map[string]string{
+		"event.sink.first.type":      "file",
+		"event.sink.first.path":      filepath.Join(dir, "first.jsonl"),
+		"event.sink.first.batchSize": "5",
+	})
+	ec.updateConfiguredSinks()
+	assert.DeepEqual(t, []string{"code", "first"}, getSinkNames())
+	assert.Assert(t, first != getRegisteredSink(t, "first"), "changed sink must be replaced")
+	assert.Equal(t, 5, getRegisteredSink(t, "first").options.BatchSize)
+
+	ec.removeConfiguredSinks()
+	assert.DeepEqual(t, []string{"code"}, getSinkNames())
+}
+
+func TestEventSystemConfiguredSinks(t *testing.T) {
+	original := configs.GetConfigMap()
+	defer configs.SetConfigMap(original)
+	configs.SetConfigMap(map[string]string{
+		"event.sink.local.type": "file",
+		"event.sink.local.path": filepath.Join(t.TempDir(), "events.jsonl"),
+	})
+	Init()
+	eventSystem := GetEventSystem().(*EventSystemImpl) //nolint:errcheck
+	eventSystem.StartServiceWithPublisher(false)
+	assert.DeepEqual(t, []string{"local"}, getSinkNames())
+	eventSystem.Stop()
+	assert.Equal(t, 0, len(getSinkNames()))
+}
+
+func getSinkNames() []string {
+	names := make([]string, 0)
+	for _, status := range GetEventSinkStatus() {
+		names = append(names, status.Name)
+	}
+	return names
+}
+
+func getRegisteredSink(t *testing.T, name string) *registeredSink {
+	eventSinks.RLock()
+	defer eventSinks.RUnlock()
+	rs, ok := eventSinks.sinks[name]
+	assert.Assert(t, ok, "event sink %s is not registered", name)
+	return rs
+}
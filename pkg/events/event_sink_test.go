@@ -0,0 +1,252 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package events
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/G-Research/yunikorn-core/pkg/common"
+	"github.com/G-Research/yunikorn-scheduler-interface/lib/go/si"
+)
+
+// testSink records the batches it receives and fails the first failures calls to Send.
+type testSink struct {
+	batches  [][]*si.EventRecord
+	failures int
+	calls    int
+	closed   bool
+	sync.Mutex
+}
+
+func (s *testSink) Send(records []*si.EventRecord) error {
+	s.Lock()
+	defer s.Unlock()
+	s.calls++
+	if s.calls <= s.failures {
+		return fmt.Errorf("send failure %d", s.calls)
+	}
+	s.batches = append(s.batches, records)
+	return nil
+}
+
+func (s *testSink) Close() error {
+	s.Lock()
+	defer s.Unlock()
+	s.closed = true
+	return nil
+}
+
+func (s *testSink) getBatches() [][]*si.EventRecord {
+	s.Lock()
+	defer s.Unlock()
+	return s.batches
+}
+
+func (s *testSink) isClosed() bool {
+	s.Lock()
+	defer s.Unlock()
+	return s.closed
+}
+
+func getSinkStatus(t *testing.T, name string) EventSinkStatus {
+	for _, status := range GetEventSinkStatus() {
+		if status.Name == name {
+			return status
+		}
+	}
+	t.Fatalf("event sink %s is not registered", name)
+	return EventSinkStatus{}
+}
+
+func waitForSinkStatus(t *testing.T, name string, condition func(status EventSinkStatus) bool) {
+	err := common.WaitForCondition(time.Millisecond, time.Second, func() bool {
+		return condition(getSinkStatus(t, name))
+	})
+	assert.NilError(t, err, "sink status not reached: %+v", getSinkStatus(t, name))
+}
+
+func createRecords(count int) []*si.EventRecord {
+	records := make([]*si.EventRecord, count)
+	for i := range records {
+		records[i] = &si.EventRecord{
+			Type:          si.EventRecord_APP,
+			ObjectID:      fmt.Sprintf("app-%d", i),
+			TimestampNano: int64(i),
+		}
+	}
+	return records
+}
+
+func TestRegisterEventSink(t *testing.T) {
+	defer UnregisterAllEventSinks()
+	assert.ErrorContains(t, RegisterEventSink("", &testSink{}, SinkOptions{}), "must have a name")
+	assert.ErrorContains(t, RegisterEventSink("test", nil, SinkOptions{}), "must have a name")
+
+	sink1 := &testSink{}
+	assert.NilError(t, RegisterEventSink("sink1", sink1, SinkOptions{}))
+	assert.ErrorContains(t, RegisterEventSink("sink1", &testSink{}, SinkOptions{}), "already registered")
+	sink2 := &testSink{}
+	assert.NilError(t, RegisterEventSink("sink2", sink2, SinkOptions{}))
+	status := GetEventSinkStatus()
+	assert.Equal(t, 2, len(status))
+	assert.Equal(t, "sink1", status[0].Name)
+	assert.Equal(t, "sink2", status[1].Name)
+
+	assert.Assert(t, UnregisterEventSink("sink1"))
+	assert.Assert(t, sink1.isClosed(), "sink should have been closed")
+	assert.Assert(t, !UnregisterEventSink("sink1"))
+	UnregisterAllEventSinks()
+	assert.Assert(t, sink2.isClosed(), "sink should have been closed")
+	assert.Equal(t, 0, len(GetEventSinkStatus()))
+}
+
+func TestEventSink_Batching(t *testing.T) {
+	defer UnregisterAllEventSinks()
+	sink := &testSink{}
+	assert.NilError(t, RegisterEventSink("batch", sink, SinkOptions{
+		BatchSize:     3,
+		FlushInterval: 50 * time.Millisecond,
+	}))
+	publishToSinks(createRecords(7))
+	waitForSinkStatus(t, "batch", func(status EventSinkStatus) bool {
+		return status.Sent == 7
+	})
+	batches := sink.getBatches()
+	assert.Equal(t, 3, len(batches))
+	assert.Equal(t, 3, len(batches[0]))
+	assert.Equal(t, 3, len(batches[1]))
+	// the last partial batch is sent on the flush interval
+	assert.Equal(t, 1, len(batches[2]))
+	assert.Equal(t, "app-0", batches[0][0].ObjectID)
+	assert.Equal(t, "app-6", batches[2][0].ObjectID)
+}
+
+func TestEventSink_Filter(t *testing.T) {
+	defer UnregisterAllEventSinks()
+	sink := &testSink{}
+	assert.NilError(t, RegisterEventSink("filter", sink, SinkOptions{
+		Filter:        &EventFilter{ObjectIDs: []string{"app-1", "app-3"}},
+		FlushInterval: 10 * time.Millisecond,
+	}))
+	publishToSinks(createRecords(5))
+	waitForSinkStatus(t, "filter", func(status EventSinkStatus) bool {
+		return status.Sent == 2
+	})
+	batches := sink.getBatches()
+	assert.Equal(t, 1, len(batches))
+	assert.Equal(t, "app-1", batches[0][0].ObjectID)
+	assert.Equal(t, "app-3", batches[0][1].ObjectID)
+}
+
+func TestEventSink_Retry(t *testing.T) {
+	defer UnregisterAllEventSinks()
+	sink := &testSink{failures: 2}
+	assert.NilError(t, RegisterEventSink("retry", sink, SinkOptions{
+		FlushInterval:  10 * time.Millisecond,
+		MaxRetries:     2,
+		InitialBackoff: time.Millisecond,
+	}))
+	publishToSinks(createRecords(2))
+	waitForSinkStatus(t, "retry", func(status EventSinkStatus) bool {
+		return status.Sent == 2
+	})
+	status := getSinkStatus(t, "retry")
+	assert.Equal(t, uint64(2), status.Failed)
+	assert.Equal(t, uint64(0), status.DeadLettered)
+	assert.Equal(t, 1, len(sink.getBatches()))
+}
+
+func TestEventSink_DeadLetter(t *testing.T) {
+	defer UnregisterAllEventSinks()
+	sink := &testSink{failures: 3}
+	assert.NilError(t, RegisterEventSink("dead", sink, SinkOptions{
+		FlushInterval:  10 * time.Millisecond,
+		MaxRetries:     2,
+		InitialBackoff: time.Millisecond,
+	}))
+	publishToSinks(createRecords(2))
+	waitForSinkStatus(t, "dead", func(status EventSinkStatus) bool {
+		return status.DeadLettered == 2
+	})
+	assert.Equal(t, uint64(3), getSinkStatus(t, "dead").Failed)
+
+	// later batches are not affected
+	publishToSinks(createRecords(1))
+	waitForSinkStatus(t, "dead", func(status EventSinkStatus) bool {
+		return status.Sent == 1
+	})
+}
+
+func TestEventSink_Dropped(t *testing.T) {
+	defer UnregisterAllEventSinks()
+	sink := &testSink{}
+	rs := newRegisteredSink("drop", sink, SinkOptions{QueueSize: 2})
+	// not started, the queue fills up
+	rs.add(createRecords(5))
+	assert.Equal(t, uint64(3), rs.status().Dropped)
+	assert.Equal(t, 2, len(rs.queue))
+}
+
+func TestEventSink_Close(t *testing.T) {
+	sink := &testSink{failures: 1}
+	rs := newRegisteredSink("close", sink, SinkOptions{
+		FlushInterval: time.Hour,
+		MaxRetries:    5,
+	})
+	rs.add(createRecords(2))
+	go rs.run()
+	// queued records are sent once on close, without retries
+	rs.close()
+	assert.Assert(t, sink.isClosed())
+	assert.Equal(t, uint64(2), rs.status().DeadLettered)
+	assert.Equal(t, 1, sink.calls)
+}
+
+func TestEventSink_Defaults(t *testing.T) {
+	rs := newRegisteredSink("defaults", &testSink{}, SinkOptions{MaxRetries: -1})
+	assert.Equal(t, defaultSinkBatchSize, rs.options.BatchSize)
+	assert.Equal(t, defaultSinkFlushInterval, rs.options.FlushInterval)
+	assert.Equal(t, defaultSinkQueueSize, cap(rs.queue))
+	assert.Equal(t, 0, rs.options.MaxRetries)
+	assert.Equal(t, defaultSinkInitialBackoff, rs.options.InitialBackoff)
+	assert.Equal(t, defaultSinkMaxBackoff, rs.options.MaxBackoff)
+}
+
+func TestPublisherSendsToSinks(t *testing.T) {
+	defer UnregisterAllEventSinks()
+	sink := &testSink{}
+	assert.NilError(t, RegisterEventSink("publisher", sink, SinkOptions{FlushInterval: 10 * time.Millisecond}))
+	store := newEventStore(1000)
+	publisher := CreateShimPublisher(store)
+	publisher.pushEventInterval = time.Millisecond
+	publisher.StartService()
+	defer publisher.Stop()
+
+	// no shim plugin is needed for the sinks
+	store.Store(&si.EventRecord{Type: si.EventRecord_NODE, ObjectID: "node-1"})
+	waitForSinkStatus(t, "publisher", func(status EventSinkStatus) bool {
+		return status.Sent == 1
+	})
+	assert.Equal(t, "node-1", sink.getBatches()[0][0].ObjectID)
+}
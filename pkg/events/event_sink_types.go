@@ -0,0 +1,176 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/G-Research/yunikorn-scheduler-interface/lib/go/si"
+)
+
+const defaultSinkTimeout = 10 * time.Second
+
+// webhookSink POSTs each batch as a JSON array of event records.
+type webhookSink struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// NewWebhookEventSink creates a sink that POSTs batches as a JSON array to the URL.
+// The headers are added to each request, for instance for authentication. Any response status other than 2xx
+// fails the batch. A timeout of 0 uses the default of 10 seconds.
+func NewWebhookEventSink(url string, headers map[string]string, timeout time.Duration) EventSink {
+	if timeout <= 0 {
+		timeout = defaultSinkTimeout
+	}
+	return &webhookSink{
+		url:     url,
+		headers: headers,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *webhookSink) Send(records []*si.EventRecord) error {
+	body, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range s.headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// drain the body so that the connection can be reused
+	_, _ = io.Copy(io.Discard, resp.Body) //nolint:errcheck
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook %s returned status %d", s.url, resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *webhookSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// localFileSink appends each batch as JSON lines to a single file.
+type localFileSink struct {
+	file *os.File
+}
+
+// NewLocalFileEventSink creates a sink that appends the records as JSON lines to the file, the file is created
+// if it does not exist. Unlike the event file sink the file is not rotated.
+func NewLocalFileEventSink(path string) (EventSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return nil, err
+	}
+	return &localFileSink{file: file}, nil
+}
+
+func (s *localFileSink) Send(records []*si.EventRecord) error {
+	data, err := encodeJSONLines(records)
+	if err != nil {
+		return err
+	}
+	_, err = s.file.Write(data)
+	return err
+}
+
+func (s *localFileSink) Close() error {
+	return s.file.Close()
+}
+
+// unixSocketSink writes each batch as JSON lines to a Unix domain socket.
+type unixSocketSink struct {
+	path    string
+	timeout time.Duration
+	conn    net.Conn
+}
+
+// NewUnixSocketEventSink creates a sink that writes the records as JSON lines to the stream socket at the path.
+// The connection is opened on the first batch and re-opened after a failed write. A timeout of 0 uses the default
+// of 10 seconds for connecting and writing.
+func NewUnixSocketEventSink(path string, timeout time.Duration) EventSink {
+	if timeout <= 0 {
+		timeout = defaultSinkTimeout
+	}
+	return &unixSocketSink{
+		path:    path,
+		timeout: timeout,
+	}
+}
+
+func (s *unixSocketSink) Send(records []*si.EventRecord) error {
+	data, err := encodeJSONLines(records)
+	if err != nil {
+		return err
+	}
+	if s.conn == nil {
+		if s.conn, err = net.DialTimeout("unix", s.path, s.timeout); err != nil {
+			return err
+		}
+	}
+	if err = s.conn.SetWriteDeadline(time.Now().Add(s.timeout)); err == nil {
+		_, err = s.conn.Write(data)
+	}
+	if err != nil {
+		// a partial write leaves the stream in an unknown state, start over on a new connection
+		_ = s.conn.Close() //nolint:errcheck
+		s.conn = nil
+	}
+	return err
+}
+
+func (s *unixSocketSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// encodeJSONLines encodes the records as one JSON object per line.
+func encodeJSONLines(records []*si.EventRecord) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,123 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/G-Research/yunikorn-scheduler-interface/lib/go/si"
+)
+
+func TestWebhookEventSink(t *testing.T) {
+	var received []*si.EventRecord
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		body, err := io.ReadAll(r.Body)
+		assert.NilError(t, err)
+		received = nil
+		assert.NilError(t, json.Unmarshal(body, &received))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sink := NewWebhookEventSink(server.URL, map[string]string{"Authorization": "Bearer token"}, 0)
+	defer sink.Close()
+	assert.NilError(t, sink.Send(createRecords(2)))
+	assert.Equal(t, 2, len(received))
+	assert.Equal(t, "app-1", received[1].ObjectID)
+
+	status = http.StatusServiceUnavailable
+	assert.ErrorContains(t, sink.Send(createRecords(1)), "returned status 503")
+
+	unreachable := NewWebhookEventSink("http://127.0.0.1:0", nil, 0)
+	assert.Assert(t, unreachable.Send(createRecords(1)) != nil, "send to unreachable webhook should fail")
+}
+
+func TestLocalFileEventSink(t *testing.T) {
+	_, err := NewLocalFileEventSink(filepath.Join(t.TempDir(), "missing", "events.jsonl"))
+	assert.Assert(t, err != nil, "sink in missing directory should fail")
+
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	sink, err := NewLocalFileEventSink(path)
+	assert.NilError(t, err)
+	assert.NilError(t, sink.Send(createRecords(2)))
+	assert.NilError(t, sink.Send(createRecords(1)))
+	assert.NilError(t, sink.Close())
+
+	file, err := os.Open(path)
+	assert.NilError(t, err)
+	defer file.Close()
+	reader, err := NewEventRecordReader(file, FileSinkFormatJSONL)
+	assert.NilError(t, err)
+	ids := make([]string, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		assert.NilError(t, err)
+		ids = append(ids, record.ObjectID)
+	}
+	assert.DeepEqual(t, []string{"app-0", "app-1", "app-0"}, ids)
+}
+
+func TestUnixSocketEventSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.sock")
+	sink := NewUnixSocketEventSink(path, 0)
+	defer sink.Close()
+	assert.Assert(t, sink.Send(createRecords(1)) != nil, "send without listener should fail")
+
+	listener, err := net.Listen("unix", path)
+	assert.NilError(t, err)
+	defer listener.Close()
+	lines := make(chan string, 10)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	assert.NilError(t, sink.Send(createRecords(2)))
+	for i := 0; i < 2; i++ {
+		var record si.EventRecord
+		assert.NilError(t, json.Unmarshal([]byte(<-lines), &record))
+		assert.Equal(t, createRecords(2)[i].ObjectID, record.ObjectID)
+	}
+	assert.NilError(t, sink.Close())
+	assert.NilError(t, sink.Close())
+}
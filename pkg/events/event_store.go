@@ -19,6 +19,8 @@
 package events
 
 import (
+	"strings"
+
 	"go.uber.org/zap"
 
 	"github.com/G-Research/yunikorn-core/pkg/locking"
@@ -55,6 +57,7 @@ func (es *EventStore) Store(event *si.EventRecord) {
 
 	if es.idx == uint64(len(es.events)) {
 		metrics.GetEventMetrics().IncEventsNotStored()
+		metrics.GetEventMetrics().IncEventsDropped(strings.ToLower(event.GetType().String()), metrics.EventDropStore)
 		return
 	}
 	es.events[es.idx] = event
@@ -26,6 +26,7 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"gotest.tools/v3/assert"
 
+	"github.com/G-Research/yunikorn-core/pkg/metrics"
 	"github.com/G-Research/yunikorn-scheduler-interface/lib/go/si"
 )
 
@@ -73,6 +74,7 @@ func TestStoreAndRetrieve(t *testing.T) {
 // if we push more events to the EventStore than its
 // allowed maximum, those that couldn't fit will be omitted
 func TestStoreWithLimitedSize(t *testing.T) {
+	metrics.GetEventMetrics().Reset()
 	store := newEventStore(3)
 
 	for i := 0; i < 5; i++ {
@@ -86,6 +88,9 @@ func TestStoreWithLimitedSize(t *testing.T) {
 	}
 	records := store.CollectEvents()
 	assert.Equal(t, len(records), 3)
+	dropped, err := metrics.GetEventMetrics().GetEventsDropped("request", metrics.EventDropStore)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, dropped)
 }
 
 func TestSetStoreSize(t *testing.T) {
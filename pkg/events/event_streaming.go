@@ -19,12 +19,14 @@
 package events
 
 import (
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/G-Research/yunikorn-core/pkg/locking"
 	"github.com/G-Research/yunikorn-core/pkg/log"
+	"github.com/G-Research/yunikorn-core/pkg/metrics"
 	"github.com/G-Research/yunikorn-scheduler-interface/lib/go/si"
 )
 
@@ -40,43 +42,71 @@ type EventStreaming struct {
 }
 
 type eventConsumerDetails struct {
-	local     chan *si.EventRecord
-	consumer  chan<- *si.EventRecord
+	local     chan *StreamEvent
+	consumer  chan<- *StreamEvent
 	stopCh    chan struct{}
 	name      string
 	createdAt time.Time
+	filter    *EventFilter
+}
+
+// lag returns the number of events waiting to be read by the consumer.
+func (d eventConsumerDetails) lag() int {
+	return len(d.local) + len(d.consumer)
 }
 
 // EventStreamData contains data about an event stream.
 type EventStreamData struct {
 	Name      string
 	CreatedAt time.Time
+	Lag       int // events waiting to be read by the consumer
+}
+
+// StreamEvent an event record sent on an event stream with the unique ID of the event in the ring buffer.
+// The ID can be used to resume a stream after a reconnect.
+type StreamEvent struct {
+	ID    uint64
+	Event *si.EventRecord
+}
+
+// EventGap describes the events missed by a stream that was requested from an ID no longer in the ring buffer.
+// All events from RequestedID up to, but not including, LowestID are lost.
+type EventGap struct {
+	RequestedID uint64
+	LowestID    uint64
 }
 
 // EventStream handle type returned to the client that wants to capture the stream of events.
 type EventStream struct {
-	Events <-chan *si.EventRecord
+	Events <-chan *StreamEvent
+	// Gap is set if the stream was created from an event ID that is no longer available
+	Gap *EventGap
 }
 
 // PublishEvent publishes an event to all event stream consumers.
+// The id is the unique ID assigned to the event by the ring buffer.
 //
 // The streaming logic uses bridging to ensure proper ordering of existing and new events.
 // Events are sent to the "local" channel from where it is forwarded to the "consumer" channel.
 //
 // If "local" is full, it means that the consumer side has not processed the events at an appropriate pace.
 // Such a consumer is removed and the related channels are closed.
-func (e *EventStreaming) PublishEvent(event *si.EventRecord) {
+func (e *EventStreaming) PublishEvent(id uint64, event *si.EventRecord) {
 	e.Lock()
 	defer e.Unlock()
 
 	for consumer, details := range e.eventStreams {
+		if !details.filter.Matches(event) {
+			continue
+		}
 		if len(details.local) == defaultChannelBufSize {
 			log.Log(log.Events).Warn("Listener buffer full due to potentially slow consumer, removing it")
+			metrics.GetEventMetrics().IncEventsDropped(strings.ToLower(event.GetType().String()), metrics.EventDropStream)
 			e.removeEventStream(consumer)
 			continue
 		}
 
-		details.local <- event
+		details.local <- &StreamEvent{ID: id, Event: event}
 	}
 }
 
@@ -87,27 +117,49 @@ func (e *EventStreaming) PublishEvent(event *si.EventRecord) {
 //
 // Consumers have an arbitrary name for logging purposes. The "count" parameter defines the number
 // of maximum historical events from the ring buffer. "0" is a valid value and means no past events.
-func (e *EventStreaming) CreateEventStream(name string, count uint64) *EventStream {
-	consumer := make(chan *si.EventRecord, defaultChannelBufSize)
+// The "filter" restricts both the historical and the new events sent to the consumer, nil means no filtering.
+// Filtering happens before events are queued for the consumer: filtered out events do not count
+// towards the buffer limit of the consumer.
+func (e *EventStreaming) CreateEventStream(name string, count uint64, filter *EventFilter) *EventStream {
+	return e.createEventStream(name, filter, func() ([]*StreamEvent, *EventGap) {
+		return e.buffer.GetRecentStreamEvents(count, filter), nil
+	})
+}
+
+// CreateEventStreamFromID sets up event streaming for a consumer that resumes a previous stream.
+// All events from the ring buffer starting at "id" are sent before the new events. If "id" is no longer
+// available in the ring buffer, the stream starts at the oldest available event and the Gap of the
+// returned EventStream is set.
+// See CreateEventStream for details on the name and filter.
+func (e *EventStreaming) CreateEventStreamFromID(name string, id uint64, filter *EventFilter) *EventStream {
+	return e.createEventStream(name, filter, func() ([]*StreamEvent, *EventGap) {
+		return e.buffer.GetStreamEventsFromID(id, filter)
+	})
+}
+
+func (e *EventStreaming) createEventStream(name string, filter *EventFilter, getHistory func() ([]*StreamEvent, *EventGap)) *EventStream {
+	consumer := make(chan *StreamEvent, defaultChannelBufSize)
 	stream := &EventStream{
 		Events: consumer,
 	}
-	local := make(chan *si.EventRecord, defaultChannelBufSize)
+	local := make(chan *StreamEvent, defaultChannelBufSize)
 	stop := make(chan struct{})
-	e.createEventStreamInternal(stream, local, consumer, stop, name)
-	history := e.buffer.GetRecentEvents(count)
+	e.createEventStreamInternal(stream, local, consumer, stop, name, filter)
+	history, gap := getHistory()
+	stream.Gap = gap
 
-	go func(consumer chan<- *si.EventRecord, local <-chan *si.EventRecord, stop <-chan struct{}) {
-		// Store the refs of historical events; it's possible that some events are added to the
+	go func(consumer chan<- *StreamEvent, local <-chan *StreamEvent, stop <-chan struct{}) {
+		// Track the last historical event; it's possible that some events are added to the
 		// ring buffer and also to "local" channel.
 		// It is because we use two separate locks, so event updates are not atomic.
 		// Example: an event has been just added to the ring buffer (before createEventStreamInternal()),
 		// and execution is about to enter PublishEvent(); at this point we have an updated "eventStreams"
 		// map, so "local" will also contain the new event.
-		seen := make(map[*si.EventRecord]bool)
+		// Event IDs are increasing: everything up to the last historical event has been sent.
+		var lastSent *StreamEvent
 		for _, event := range history {
 			consumer <- event
-			seen[event] = true
+			lastSent = event
 		}
 		for {
 			select {
@@ -118,12 +170,15 @@ func (e *EventStreaming) CreateEventStream(name string, count uint64) *EventStre
 				close(consumer)
 				return
 			case event := <-local:
-				if seen[event] {
+				if event == nil {
+					continue
+				}
+				if lastSent != nil && event.ID <= lastSent.ID {
 					continue
 				}
 				// since events are processed in a single goroutine, doubling is no longer
 				// possible at this point
-				seen = make(map[*si.EventRecord]bool)
+				lastSent = nil
 				consumer <- event
 			}
 		}
@@ -134,10 +189,11 @@ func (e *EventStreaming) CreateEventStream(name string, count uint64) *EventStre
 }
 
 func (e *EventStreaming) createEventStreamInternal(stream *EventStream,
-	local chan *si.EventRecord,
-	consumer chan *si.EventRecord,
+	local chan *StreamEvent,
+	consumer chan *StreamEvent,
 	stop chan struct{},
-	name string) {
+	name string,
+	filter *EventFilter) {
 	// stuff that needs locking
 	e.Lock()
 	defer e.Unlock()
@@ -148,6 +204,7 @@ func (e *EventStreaming) createEventStreamInternal(stream *EventStream,
 		stopCh:    stop,
 		name:      name,
 		createdAt: time.Now(),
+		filter:    filter,
 	}
 }
 
@@ -183,12 +240,24 @@ func (e *EventStreaming) GetEventStreams() []EventStreamData {
 		streams = append(streams, EventStreamData{
 			Name:      s.name,
 			CreatedAt: s.createdAt,
+			Lag:       s.lag(),
 		})
 	}
 
 	return streams
 }
 
+// updateLagMetrics updates the lag metrics of the consumers, the lag of consumers with the same name is summed.
+func (e *EventStreaming) updateLagMetrics() {
+	e.RLock()
+	defer e.RUnlock()
+	lag := make(map[string]int)
+	for _, s := range e.eventStreams {
+		lag[s.name] += s.lag()
+	}
+	metrics.GetEventMetrics().SetStreamLag(lag)
+}
+
 // NewEventStreaming creates a new event streaming infrastructure.
 func NewEventStreaming(eventBuffer *eventRingBuffer) *EventStreaming {
 	return &EventStreaming{
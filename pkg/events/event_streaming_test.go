@@ -24,6 +24,8 @@ import (
 
 	"gotest.tools/v3/assert"
 
+	"github.com/G-Research/yunikorn-core/pkg/common"
+	"github.com/G-Research/yunikorn-core/pkg/metrics"
 	"github.com/G-Research/yunikorn-scheduler-interface/lib/go/si"
 )
 
@@ -32,13 +34,13 @@ var defaultCount = uint64(10000)
 func TestEventStreaming_WithoutHistory(t *testing.T) {
 	buffer := newEventRingBuffer(10)
 	streaming := NewEventStreaming(buffer)
-	es := streaming.CreateEventStream("test", defaultCount)
+	es := streaming.CreateEventStream("test", defaultCount, nil)
 	defer streaming.Close()
 
 	sent := &si.EventRecord{
 		Message: "testMessage",
 	}
-	streaming.PublishEvent(sent)
+	streaming.PublishEvent(0, sent)
 	received := receive(t, es.Events)
 	streaming.RemoveEventStream(es)
 	assert.Equal(t, 0, len(streaming.eventStreams[es].local))
@@ -56,9 +58,9 @@ func TestEventStreaming_WithHistory(t *testing.T) {
 	buffer.Add(&si.EventRecord{TimestampNano: 5})
 	buffer.Add(&si.EventRecord{TimestampNano: 6})
 	buffer.Add(&si.EventRecord{TimestampNano: 9})
-	es := streaming.CreateEventStream("test", defaultCount)
+	es := streaming.CreateEventStream("test", defaultCount, nil)
 
-	streaming.PublishEvent(&si.EventRecord{TimestampNano: 10})
+	streaming.PublishEvent(4, &si.EventRecord{TimestampNano: 10})
 
 	received1 := receive(t, es.Events)
 	received2 := receive(t, es.Events)
@@ -85,9 +87,9 @@ func TestEventStreaming_WithHistoryCount(t *testing.T) {
 	buffer.Add(&si.EventRecord{TimestampNano: 5})
 	buffer.Add(&si.EventRecord{TimestampNano: 6})
 	buffer.Add(&si.EventRecord{TimestampNano: 9})
-	es := streaming.CreateEventStream("test", 2)
+	es := streaming.CreateEventStream("test", 2, nil)
 
-	streaming.PublishEvent(&si.EventRecord{TimestampNano: 10})
+	streaming.PublishEvent(4, &si.EventRecord{TimestampNano: 10})
 
 	received1 := receive(t, es.Events)
 	received2 := receive(t, es.Events)
@@ -104,10 +106,10 @@ func TestEventStreaming_TwoConsumers(t *testing.T) {
 	streaming := NewEventStreaming(buffer)
 	defer streaming.Close()
 
-	es1 := streaming.CreateEventStream("stream1", defaultCount)
-	es2 := streaming.CreateEventStream("stream2", defaultCount)
+	es1 := streaming.CreateEventStream("stream1", defaultCount, nil)
+	es2 := streaming.CreateEventStream("stream2", defaultCount, nil)
 	for i := 0; i < 5; i++ {
-		streaming.PublishEvent(&si.EventRecord{TimestampNano: int64(i)})
+		streaming.PublishEvent(uint64(i), &si.EventRecord{TimestampNano: int64(i)})
 	}
 
 	for i := 0; i < 5; i++ {
@@ -122,16 +124,138 @@ func TestEventStreaming_TwoConsumers(t *testing.T) {
 
 func TestEventStreaming_SlowConsumer(t *testing.T) {
 	// simulating a slow event consumer by ignoring events
+	metrics.GetEventMetrics().Reset()
 	buffer := newEventRingBuffer(10)
 	streaming := NewEventStreaming(buffer)
 	defer streaming.Close()
-	streaming.CreateEventStream("test", 10000)
+	streaming.CreateEventStream("test", 10000, nil)
 
 	for i := 0; i < 2500; i++ {
-		streaming.PublishEvent(&si.EventRecord{TimestampNano: int64(i)})
+		streaming.PublishEvent(uint64(i), &si.EventRecord{Type: si.EventRecord_NODE, TimestampNano: int64(i)})
 	}
 
 	assert.Equal(t, 0, len(streaming.eventStreams))
+	dropped, err := metrics.GetEventMetrics().GetEventsDropped("node", metrics.EventDropStream)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, dropped)
+}
+
+func TestEventStreaming_Lag(t *testing.T) {
+	metrics.GetEventMetrics().Reset()
+	buffer := newEventRingBuffer(10)
+	streaming := NewEventStreaming(buffer)
+	defer streaming.Close()
+	es := streaming.CreateEventStream("test", 0, nil)
+	streaming.CreateEventStream("test", 0, nil)
+	streaming.CreateEventStream("other", 0, &EventFilter{ObjectIDs: []string{"unknown"}})
+
+	for i := 0; i < 10; i++ {
+		streaming.PublishEvent(uint64(i), &si.EventRecord{TimestampNano: int64(i)})
+	}
+	receive(t, es.Events)
+	// one event is read by the first consumer, wait for the events in transit between the stream channels
+	var lag map[string]int
+	err := common.WaitForCondition(time.Millisecond, time.Second, func() bool {
+		lag = make(map[string]int)
+		for _, stream := range streaming.GetEventStreams() {
+			lag[stream.Name] += stream.Lag
+		}
+		return lag["test"] == 19
+	})
+	assert.NilError(t, err, "unexpected lag %v", lag)
+	assert.Equal(t, 0, lag["other"])
+
+	streaming.updateLagMetrics()
+	value, err := metrics.GetEventMetrics().GetStreamLag("test")
+	assert.NilError(t, err)
+	assert.Equal(t, 19, value)
+	value, err = metrics.GetEventMetrics().GetStreamLag("other")
+	assert.NilError(t, err)
+	assert.Equal(t, 0, value)
+}
+
+func TestEventStreaming_Filtered(t *testing.T) {
+	buffer := newEventRingBuffer(10)
+	streaming := NewEventStreaming(buffer)
+	defer streaming.Close()
+
+	buffer.Add(&si.EventRecord{ObjectID: "app-1", TimestampNano: 1})
+	buffer.Add(&si.EventRecord{ObjectID: "app-2", TimestampNano: 2})
+	buffer.Add(&si.EventRecord{ObjectID: "app-1", TimestampNano: 3})
+	es := streaming.CreateEventStream("test", 1, &EventFilter{ObjectIDs: []string{"app-1"}})
+
+	streaming.PublishEvent(3, &si.EventRecord{ObjectID: "app-2", TimestampNano: 4})
+	streaming.PublishEvent(4, &si.EventRecord{ObjectID: "app-1", TimestampNano: 5})
+
+	assert.Equal(t, int64(3), receive(t, es.Events).TimestampNano)
+	assert.Equal(t, int64(5), receive(t, es.Events).TimestampNano)
+	assert.Equal(t, 0, len(streaming.eventStreams[es].local))
+	assert.Equal(t, 0, len(streaming.eventStreams[es].consumer))
+}
+
+func TestEventStreaming_FilteredSlowConsumer(t *testing.T) {
+	// filtered out events must not fill up the buffer of a consumer that is not reading
+	buffer := newEventRingBuffer(10)
+	streaming := NewEventStreaming(buffer)
+	defer streaming.Close()
+	streaming.CreateEventStream("test", 0, &EventFilter{ObjectIDs: []string{"app-1"}})
+
+	for i := 0; i < 2500; i++ {
+		streaming.PublishEvent(uint64(i), &si.EventRecord{ObjectID: "app-2", TimestampNano: int64(i)})
+	}
+
+	assert.Equal(t, 1, len(streaming.eventStreams))
+}
+
+func TestEventStreaming_FromID(t *testing.T) {
+	buffer := newEventRingBuffer(10)
+	streaming := NewEventStreaming(buffer)
+	defer streaming.Close()
+
+	for i := 0; i < 4; i++ {
+		buffer.Add(&si.EventRecord{TimestampNano: int64(i)})
+	}
+	es := streaming.CreateEventStreamFromID("test", 2, nil)
+	assert.Assert(t, es.Gap == nil, "no events were missed")
+	// published after registration but already in the buffer: must not be sent twice
+	streaming.PublishEvent(3, &si.EventRecord{TimestampNano: 3})
+	event := &si.EventRecord{TimestampNano: 4}
+	streaming.PublishEvent(buffer.Add(event), event)
+
+	for i := uint64(2); i < 5; i++ {
+		event := receiveWithID(t, es.Events)
+		assert.Equal(t, i, event.ID)
+		assert.Equal(t, int64(i), event.Event.TimestampNano)
+	}
+	assert.Equal(t, 0, len(streaming.eventStreams[es].local))
+	assert.Equal(t, 0, len(streaming.eventStreams[es].consumer))
+
+	// next ID: live events only
+	es = streaming.CreateEventStreamFromID("test-next", 5, nil)
+	assert.Assert(t, es.Gap == nil, "no events were missed")
+	event = &si.EventRecord{TimestampNano: 5}
+	streaming.PublishEvent(buffer.Add(event), event)
+	assert.Equal(t, uint64(5), receiveWithID(t, es.Events).ID)
+}
+
+func TestEventStreaming_FromIDGap(t *testing.T) {
+	buffer := newEventRingBuffer(3)
+	streaming := NewEventStreaming(buffer)
+	defer streaming.Close()
+
+	for i := 0; i < 5; i++ {
+		buffer.Add(&si.EventRecord{TimestampNano: int64(i)})
+	}
+	es := streaming.CreateEventStreamFromID("test", 1, nil)
+	assert.DeepEqual(t, &EventGap{RequestedID: 1, LowestID: 2}, es.Gap)
+	for i := uint64(2); i < 5; i++ {
+		assert.Equal(t, i, receiveWithID(t, es.Events).ID)
+	}
+
+	// ID beyond the newest event, e.g. from before a restart
+	es = streaming.CreateEventStreamFromID("test-restart", 100, &EventFilter{ObjectIDs: []string{""}})
+	assert.DeepEqual(t, &EventGap{RequestedID: 100, LowestID: 2}, es.Gap)
+	assert.Equal(t, uint64(2), receiveWithID(t, es.Events).ID)
 }
 
 func TestGetEventStreams(t *testing.T) {
@@ -139,12 +263,12 @@ func TestGetEventStreams(t *testing.T) {
 	streaming := NewEventStreaming(buffer)
 	defer streaming.Close()
 
-	streaming.CreateEventStream("test-1", 0)
+	streaming.CreateEventStream("test-1", 0, nil)
 	streams := streaming.GetEventStreams()
 	assert.Equal(t, 1, len(streams))
 	assert.Equal(t, "test-1", streams[0].Name)
 
-	streaming.CreateEventStream("test-2", 0)
+	streaming.CreateEventStream("test-2", 0, nil)
 	streams = streaming.GetEventStreams()
 	assert.Equal(t, 2, len(streams))
 	names := make(map[string]bool)
@@ -154,7 +278,11 @@ func TestGetEventStreams(t *testing.T) {
 	assert.Assert(t, names["test-1"])
 }
 
-func receive(t *testing.T, input <-chan *si.EventRecord) *si.EventRecord {
+func receive(t *testing.T, input <-chan *StreamEvent) *si.EventRecord {
+	return receiveWithID(t, input).Event
+}
+
+func receiveWithID(t *testing.T, input <-chan *StreamEvent) *StreamEvent {
 	select {
 	case event := <-input:
 		return event
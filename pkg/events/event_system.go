@@ -19,8 +19,12 @@
 package events
 
 import (
+	"context"
 	"fmt"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
@@ -36,6 +40,10 @@ import (
 var once sync.Once
 var ev EventSystem
 
+// maintenanceInterval how often expired events are removed from the ring buffer when no new events arrive,
+// the channel and stream backlog metrics are updated, and the state encoders of idle objects are released
+var maintenanceInterval = time.Second
+
 type EventSystem interface {
 	// AddEvent adds an event record to the event system for processing:
 	// 1. It is added to a slice from where it is periodically read by the shim publisher.
@@ -62,11 +70,19 @@ type EventSystem interface {
 	// CreateEventStream creates an event stream (channel) for a consumer.
 	// The "name" argument is an arbitrary string for a consumer, which is used for logging. It does not need to be unique.
 	// The "count" argument defines how many historical elements should be returned on the stream. Zero is a valid value for "count".
+	// The "filter" argument restricts the historical and new events sent on the stream, nil means all events.
 	// The returned type contains a read-only channel which is updated as soon as there is a new event record.
 	// It is also used as a handle to stop the streaming.
 	// Consumers must read the channel and process the event objects as soon as they can to avoid
 	// events piling up inside the channel buffers.
-	CreateEventStream(name string, count uint64) *EventStream
+	CreateEventStream(name string, count uint64, filter *EventFilter) *EventStream
+
+	// CreateEventStreamFromID creates an event stream (channel) for a consumer that resumes a previous stream.
+	// All events from the history buffer starting at "id" are returned on the stream before the new events.
+	// If "id" is no longer in the buffer, the stream starts at the lowest available event and the Gap of
+	// the returned type describes the events that were missed.
+	// See CreateEventStream for the other arguments.
+	CreateEventStreamFromID(name string, id uint64, filter *EventFilter) *EventStream
 
 	// RemoveStream stops streaming for a given consumer.
 	// Consumers that no longer wish to be updated (e.g., a remote client
@@ -84,6 +100,9 @@ type EventSystemImpl struct {
 	publisher     *EventPublisher
 	eventBuffer   *eventRingBuffer
 	streaming     *EventStreaming
+	fileSink      atomic.Pointer[eventFileSink] // optional, nil if disabled
+
+	configuredSinks map[string]map[string]string // properties of the registered sinks from the configuration
 
 	channel chan *si.EventRecord // channelling input eventChannel
 	stop    chan bool            // whether the service is stopped
@@ -97,8 +116,13 @@ type EventSystemImpl struct {
 }
 
 // CreateEventStream creates an event stream. See the interface for details.
-func (ec *EventSystemImpl) CreateEventStream(name string, count uint64) *EventStream {
-	return ec.streaming.CreateEventStream(name, count)
+func (ec *EventSystemImpl) CreateEventStream(name string, count uint64, filter *EventFilter) *EventStream {
+	return ec.streaming.CreateEventStream(name, count, filter)
+}
+
+// CreateEventStreamFromID creates an event stream resuming from an event ID. See the interface for details.
+func (ec *EventSystemImpl) CreateEventStreamFromID(name string, id uint64, filter *EventFilter) *EventStream {
+	return ec.streaming.CreateEventStreamFromID(name, id, filter)
 }
 
 // RemoveStream graceful termination of an event streaming for a consumer. See the interface for details.
@@ -175,21 +199,34 @@ func (ec *EventSystemImpl) StartServiceWithPublisher(withPublisher bool) {
 	ec.trackingEnabled = isTrackingEnabled()
 	ec.ringBufferCapacity = getRingBufferCapacity()
 	ec.requestCapacity = getRequestCapacity()
+	ec.updateFileSink()
+	ec.updateConfiguredSinks()
+	updateStateEncoding()
+	ec.eventBuffer.SetRetention(getRingBufferRetention())
 
+	ticker := time.NewTicker(maintenanceInterval)
+	encoders := newStateEncoders()
 	go func() {
 		log.Log(log.Events).Info("Starting event system handler")
+		defer ticker.Stop()
 		for {
 			select {
 			case <-ec.stop:
 				return
+			case <-ticker.C:
+				ec.eventBuffer.RemoveExpired()
+				encoders.removeIdle(time.Now().UnixNano())
+				metrics.GetEventMetrics().SetChannelSize(len(ec.channel))
+				ec.streaming.updateLagMetrics()
 			case event, ok := <-ec.channel:
 				if !ok {
 					return
 				}
 				if event != nil {
+					event = encoders.encode(event)
 					ec.Store.Store(event)
-					ec.eventBuffer.Add(event)
-					ec.streaming.PublishEvent(event)
+					id := ec.eventBuffer.Add(event)
+					ec.streaming.PublishEvent(id, event)
 					metrics.GetEventMetrics().IncEventsProcessed()
 				}
 			}
@@ -217,18 +254,45 @@ func (ec *EventSystemImpl) Stop() {
 		ec.channel = nil
 	}
 	ec.publisher.Stop()
+	if sink := ec.fileSink.Swap(nil); sink != nil {
+		sink.close()
+	}
+	ec.removeConfiguredSinks()
 	ec.stopped = true
 }
 
 // AddEvent adds an event record to the event system. See the interface for details.
 func (ec *EventSystemImpl) AddEvent(event *si.EventRecord) {
 	metrics.GetEventMetrics().IncEventsCreated()
+	if sink := ec.fileSink.Load(); sink != nil {
+		sink.add(event)
+	}
 	select {
 	case ec.channel <- event:
 		metrics.GetEventMetrics().IncEventsChanneled()
 	default:
 		log.Log(log.Events).Debug("could not add Event to channel")
 		metrics.GetEventMetrics().IncEventsNotChanneled()
+		metrics.GetEventMetrics().IncEventsDropped(strings.ToLower(event.GetType().String()), metrics.EventDropChannel)
+	}
+}
+
+// AddEventWait adds an event record to the event system like AddEvent, but waits for room in the event channel
+// instead of dropping the event. Returns the context error if the context is done before the event is added.
+// Meant for replaying events: the scheduler must not block on adding events and uses AddEvent.
+func (ec *EventSystemImpl) AddEventWait(ctx context.Context, event *si.EventRecord) error {
+	metrics.GetEventMetrics().IncEventsCreated()
+	if sink := ec.fileSink.Load(); sink != nil {
+		sink.add(event)
+	}
+	select {
+	case ec.channel <- event:
+		metrics.GetEventMetrics().IncEventsChanneled()
+		return nil
+	case <-ctx.Done():
+		metrics.GetEventMetrics().IncEventsNotChanneled()
+		metrics.GetEventMetrics().IncEventsDropped(strings.ToLower(event.GetType().String()), metrics.EventDropChannel)
+		return ctx.Err()
 	}
 }
 
@@ -258,6 +322,46 @@ func getRingBufferCapacity() uint64 {
 	return capacity
 }
 
+// getRingBufferRetention returns the maximum age of the events in the ring buffer and the quotas of the record
+// types as a percentage of the capacity. Invalid values are ignored.
+func getRingBufferRetention() (time.Duration, map[si.EventRecord_Type]uint64) {
+	configMap := configs.GetConfigMap()
+	var maxAge time.Duration
+	if value, ok := configMap[configs.CMEventRingBufferMaxAge]; ok {
+		var err error
+		if maxAge, err = time.ParseDuration(value); err != nil || maxAge < 0 {
+			log.Log(log.Events).Warn("Failed to parse configuration value",
+				zap.String("key", configs.CMEventRingBufferMaxAge),
+				zap.String("value", value),
+				zap.Error(err))
+			maxAge = 0
+		}
+	}
+	quotas := make(map[si.EventRecord_Type]uint64)
+	for key, value := range configMap {
+		typeName, ok := strings.CutPrefix(key, configs.CMEventRingBufferQuotaPrefix)
+		if !ok {
+			continue
+		}
+		eventType, ok := si.EventRecord_Type_value[strings.ToUpper(typeName)]
+		if !ok {
+			log.Log(log.Events).Warn("Unknown event record type for ring buffer quota",
+				zap.String("key", key))
+			continue
+		}
+		quota, err := strconv.ParseUint(value, 10, 64)
+		if err != nil || quota == 0 || quota > 100 {
+			log.Log(log.Events).Warn("Ring buffer quota must be a percentage between 1 and 100",
+				zap.String("key", key),
+				zap.String("value", value),
+				zap.Error(err))
+			continue
+		}
+		quotas[si.EventRecord_Type(eventType)] = quota
+	}
+	return maxAge, quotas
+}
+
 func (ec *EventSystemImpl) isRestartNeeded() bool {
 	ec.RLock()
 	defer ec.RUnlock()
@@ -293,8 +397,37 @@ func (ec *EventSystemImpl) reloadConfig() {
 	// resize the ring buffer & event store with new capacity
 	ec.Store.SetStoreSize(ec.requestCapacity)
 	ec.eventBuffer.Resize(ec.ringBufferCapacity)
+	ec.eventBuffer.SetRetention(getRingBufferRetention())
+	ec.Lock()
+	ec.updateFileSink()
+	ec.updateConfiguredSinks()
+	ec.Unlock()
+	updateStateEncoding()
 
 	if ec.isRestartNeeded() {
 		ec.Restart()
 	}
 }
+
+// updateFileSink starts, replaces or stops the file sink based on the current configuration.
+// A running sink is left untouched if its configuration did not change.
+// Must be called holding the event system lock.
+func (ec *EventSystemImpl) updateFileSink() {
+	config, enabled := getFileSinkConfig()
+	current := ec.fileSink.Load()
+	if enabled && current != nil && current.config == config {
+		return
+	}
+	var sink *eventFileSink
+	if enabled {
+		var err error
+		if sink, err = newEventFileSink(config); err != nil {
+			log.Log(log.Events).Error("Cannot create event file sink, events are not written to file", zap.Error(err))
+		} else {
+			sink.start()
+		}
+	}
+	if old := ec.fileSink.Swap(sink); old != nil {
+		old.close()
+	}
+}
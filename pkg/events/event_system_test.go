@@ -27,6 +27,7 @@ import (
 
 	"github.com/G-Research/yunikorn-core/pkg/common"
 	"github.com/G-Research/yunikorn-core/pkg/common/configs"
+	"github.com/G-Research/yunikorn-core/pkg/metrics"
 	"github.com/G-Research/yunikorn-scheduler-interface/lib/go/si"
 )
 
@@ -145,13 +146,47 @@ func TestConfigUpdate(t *testing.T) {
 	assert.Equal(t, eventSystem.eventBuffer.capacity, newRingBufferCapacity)
 }
 
+func TestFileSink(t *testing.T) {
+	original := configs.GetConfigMap()
+	defer configs.SetConfigMap(original)
+	dir := t.TempDir()
+	configs.SetConfigMap(map[string]string{
+		configs.CMEventFileSinkEnabled:   "true",
+		configs.CMEventFileSinkDirectory: dir,
+	})
+	Init()
+	eventSystem := GetEventSystem().(*EventSystemImpl) //nolint:errcheck
+	eventSystem.StartServiceWithPublisher(false)
+	assert.Assert(t, eventSystem.fileSink.Load() != nil, "file sink should have been started")
+	eventSystem.AddEvent(&si.EventRecord{ObjectID: "app-1"})
+
+	// changing the config replaces the sink, the old sink writes its queued events
+	// reload synchronously: an asynchronous reload could restart the sink after the stop below
+	configs.RemoveConfigMapCallback(eventSystem.eventSystemId)
+	configs.SetConfigMap(map[string]string{
+		configs.CMEventFileSinkEnabled:   "true",
+		configs.CMEventFileSinkDirectory: dir,
+		configs.CMEventFileSinkFormat:    FileSinkFormatProtobuf,
+	})
+	eventSystem.reloadConfig()
+	sink := eventSystem.fileSink.Load()
+	assert.Assert(t, sink != nil)
+	assert.Equal(t, FileSinkFormatProtobuf, sink.config.format)
+	files, err := listEventFiles(dir, FileSinkFormatJSONL)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(files))
+
+	eventSystem.Stop()
+	assert.Assert(t, eventSystem.fileSink.Load() == nil, "file sink should have been stopped")
+}
+
 func TestEventStreaming(t *testing.T) {
 	Init()
 	eventSystem := GetEventSystem()
 	eventSystem.StartService()
 	defer eventSystem.Stop()
 
-	eventSystem.CreateEventStream("test", 10)
+	eventSystem.CreateEventStream("test", 10, nil)
 	streams := eventSystem.GetEventStreams()
 
 	assert.Equal(t, 1, len(streams))
@@ -205,3 +240,92 @@ func TestRingBufferCapacity(t *testing.T) {
 	capacity = getRingBufferCapacity()
 	assert.Equal(t, uint64(configs.DefaultEventRingBufferCapacity), capacity)
 }
+
+func TestRingBufferRetention(t *testing.T) {
+	original := configs.GetConfigMap()
+	defer configs.SetConfigMap(original)
+
+	configs.SetConfigMap(map[string]string{})
+	maxAge, quotas := getRingBufferRetention()
+	assert.Equal(t, time.Duration(0), maxAge)
+	assert.Equal(t, 0, len(quotas))
+
+	configs.SetConfigMap(map[string]string{
+		configs.CMEventRingBufferMaxAge:                  "10m",
+		configs.CMEventRingBufferQuotaPrefix + "node":    "30",
+		configs.CMEventRingBufferQuotaPrefix + "APP":     "100",
+		configs.CMEventRingBufferQuotaPrefix + "queue":   "0",
+		configs.CMEventRingBufferQuotaPrefix + "user":    "10",
+		configs.CMEventRingBufferQuotaPrefix + "REQUEST": "101",
+	})
+	maxAge, quotas = getRingBufferRetention()
+	assert.Equal(t, 10*time.Minute, maxAge)
+	assert.DeepEqual(t, map[si.EventRecord_Type]uint64{si.EventRecord_NODE: 30, si.EventRecord_APP: 100}, quotas)
+
+	configs.SetConfigMap(map[string]string{configs.CMEventRingBufferMaxAge: "x"})
+	maxAge, _ = getRingBufferRetention()
+	assert.Equal(t, time.Duration(0), maxAge)
+}
+
+func TestRingBufferRetention_Expired(t *testing.T) {
+	original := configs.GetConfigMap()
+	defer configs.SetConfigMap(original)
+	interval := maintenanceInterval
+	maintenanceInterval = 5 * time.Millisecond
+	defer func() { maintenanceInterval = interval }()
+	configs.SetConfigMap(map[string]string{configs.CMEventRingBufferMaxAge: "50ms"})
+
+	Init()
+	eventSystem := GetEventSystem().(*EventSystemImpl) //nolint:errcheck
+	eventSystem.StartServiceWithPublisher(false)
+	defer eventSystem.Stop()
+	eventSystem.AddEvent(&si.EventRecord{Type: si.EventRecord_NODE, TimestampNano: time.Now().UnixNano()})
+	err := common.WaitForCondition(time.Millisecond, time.Second, func() bool {
+		records, _, _ := eventSystem.GetEventsFromID(0, 10)
+		return len(records) == 1
+	})
+	assert.NilError(t, err, "event not added to the ring buffer")
+
+	// no new events: the expired event is removed by the periodic check
+	err = common.WaitForCondition(time.Millisecond, time.Second, func() bool {
+		records, _, _ := eventSystem.GetEventsFromID(0, 10)
+		return len(records) == 0
+	})
+	assert.NilError(t, err, "expired event not removed from the ring buffer")
+}
+
+func TestAddEvent_ChannelFull(t *testing.T) {
+	metrics.GetEventMetrics().Reset()
+	Init()
+	// the event system is not started: nothing reads from the channel
+	eventSystem := GetEventSystem().(*EventSystemImpl) //nolint:errcheck
+	eventSystem.channel = make(chan *si.EventRecord, 2)
+	for i := 0; i < 5; i++ {
+		eventSystem.AddEvent(&si.EventRecord{Type: si.EventRecord_APP})
+	}
+	eventSystem.AddEvent(&si.EventRecord{Type: si.EventRecord_QUEUE})
+	dropped, err := metrics.GetEventMetrics().GetEventsDropped("app", metrics.EventDropChannel)
+	assert.NilError(t, err)
+	assert.Equal(t, 3, dropped)
+	dropped, err = metrics.GetEventMetrics().GetTotalEventsDropped()
+	assert.NilError(t, err)
+	assert.Equal(t, 4, dropped)
+}
+
+func TestChannelSizeMetric(t *testing.T) {
+	interval := maintenanceInterval
+	maintenanceInterval = 5 * time.Millisecond
+	defer func() { maintenanceInterval = interval }()
+	metrics.GetEventMetrics().Reset()
+	metrics.GetEventMetrics().SetChannelSize(10)
+
+	Init()
+	eventSystem := GetEventSystem().(*EventSystemImpl) //nolint:errcheck
+	eventSystem.StartServiceWithPublisher(false)
+	defer eventSystem.Stop()
+	err := common.WaitForCondition(time.Millisecond, time.Second, func() bool {
+		size, err := metrics.GetEventMetrics().GetChannelSize()
+		return err == nil && size == 0
+	})
+	assert.NilError(t, err, "channel size metric not updated")
+}
@@ -28,7 +28,11 @@ type EventSystem struct {
 	enabled bool
 }
 
-func (m *EventSystem) CreateEventStream(_ string, _ uint64) *events.EventStream {
+func (m *EventSystem) CreateEventStream(_ string, _ uint64, _ *events.EventFilter) *events.EventStream {
+	return nil
+}
+
+func (m *EventSystem) CreateEventStreamFromID(_ string, _ uint64, _ *events.EventFilter) *events.EventStream {
 	return nil
 }
 
@@ -0,0 +1,325 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/G-Research/yunikorn-core/pkg/common"
+	"github.com/G-Research/yunikorn-core/pkg/common/configs"
+	"github.com/G-Research/yunikorn-scheduler-interface/lib/go/si"
+)
+
+// ErrStateKeyframeMissing is returned by the StateDecoder if a patch cannot be applied because the previous
+// state of the object is not known. Decoding of the object resumes with its next keyframe.
+var ErrStateKeyframeMissing = errors.New("state patch without preceding keyframe")
+
+// stateEncoderIdleTimeout the time after the last event of an object after which its encoder is released.
+// Not every object sends an event when it is removed, the next event of a released object starts with a keyframe.
+const stateEncoderIdleTimeout = 15 * time.Minute
+
+// stateEncoderPruneInterval the minimum time between two checks for idle encoders.
+const stateEncoderPruneInterval = time.Minute
+
+// stateKeyframeInterval the number of state snapshots per keyframe, 0 if delta encoding is disabled.
+// Updated by the event system from the configuration.
+var stateKeyframeInterval atomic.Uint64
+
+// StateDelta is the envelope of a delta encoded state snapshot, stored as the State of an event record.
+// Exactly one of Keyframe and Patch is set: Keyframe holds the full snapshot, Patch an RFC 7386 JSON merge
+// patch against the snapshot of the previous sequence number of the same object.
+type StateDelta struct {
+	Object   string          `json:"object"`
+	Sequence uint64          `json:"seq"`
+	Keyframe json.RawMessage `json:"keyframe,omitempty"`
+	Patch    json.RawMessage `json:"patch,omitempty"`
+}
+
+// stateEncoder delta encodes the state snapshots of a single object.
+// It keeps the previous snapshot of the object, the caller must serialise calls to encode.
+type stateEncoder struct {
+	sequence      uint64
+	sinceKeyframe uint64
+	previous      interface{}
+	lastUsed      int64 // time of the last encoded snapshot in nanoseconds
+}
+
+// encode returns the StateDelta envelope for the snapshot and its decoded value.
+// The envelope carries the full snapshot every keyframe interval and a merge patch against the previous snapshot
+// in between. The object ID must be unique across all objects that send events.
+func (e *stateEncoder) encode(objectID string, snapshot []byte, current interface{}, interval uint64) string {
+	var err error
+	delta := StateDelta{
+		Object:   objectID,
+		Sequence: e.sequence,
+	}
+	if e.previous == nil || e.sinceKeyframe >= interval-1 {
+		delta.Keyframe = bytes.TrimSpace(snapshot)
+		e.sinceKeyframe = 0
+	} else {
+		if delta.Patch, err = json.Marshal(createMergePatch(e.previous, current)); err != nil {
+			e.previous = nil
+			return string(snapshot)
+		}
+		e.sinceKeyframe++
+	}
+	e.previous = current
+	e.sequence++
+	var encoded []byte
+	if encoded, err = json.Marshal(delta); err != nil {
+		e.previous = nil
+		return string(snapshot)
+	}
+	return string(encoded)
+}
+
+// stateEncoders delta encodes the states of the events of all objects.
+// Objects only take the snapshot of their state while locked, the encoding is done by the event system handler
+// routine in the order the events are processed. Not safe for concurrent use.
+type stateEncoders struct {
+	objects    map[string]*stateEncoder
+	lastPruned int64
+}
+
+func newStateEncoders() *stateEncoders {
+	return &stateEncoders{
+		objects: make(map[string]*stateEncoder),
+	}
+}
+
+// encode returns the event with a delta encoded state. The object is identified by the "id" of its snapshot.
+// The event passed in is not modified as it could be shared with the file sink, a copy is returned if the state
+// is encoded.
+func (s *stateEncoders) encode(event *si.EventRecord) *si.EventRecord {
+	interval := stateKeyframeInterval.Load()
+	if interval == 0 {
+		if len(s.objects) != 0 {
+			s.objects = make(map[string]*stateEncoder)
+		}
+		return event
+	}
+	if event.GetState() == "" {
+		return event
+	}
+	snapshot := []byte(event.GetState())
+	current, err := decodeJSON(snapshot)
+	if err != nil {
+		return event
+	}
+	object, ok := current.(map[string]interface{})
+	if !ok {
+		return event
+	}
+	objectID, ok := object["id"].(string)
+	if !ok || objectID == "" {
+		return event
+	}
+	encoder, ok := s.objects[objectID]
+	if !ok {
+		encoder = &stateEncoder{}
+		s.objects[objectID] = encoder
+	}
+	encoder.lastUsed = time.Now().UnixNano()
+	state := encoder.encode(objectID, snapshot, current, interval)
+	if isObjectRemoved(event) {
+		delete(s.objects, objectID)
+	}
+	encoded, ok := proto.Clone(event).(*si.EventRecord)
+	if !ok {
+		return event
+	}
+	encoded.State = state
+	return encoded
+}
+
+// removeIdle releases the encoders of the objects without events for longer than the idle timeout.
+// The check runs at most once per prune interval, now is the current time in nanoseconds.
+func (s *stateEncoders) removeIdle(now int64) {
+	if now-s.lastPruned < stateEncoderPruneInterval.Nanoseconds() {
+		return
+	}
+	s.lastPruned = now
+	for objectID, encoder := range s.objects {
+		if now-encoder.lastUsed > stateEncoderIdleTimeout.Nanoseconds() {
+			delete(s.objects, objectID)
+		}
+	}
+}
+
+// isObjectRemoved returns true if the event is the last event of the object whose state it carries.
+func isObjectRemoved(event *si.EventRecord) bool {
+	if event.GetEventChangeType() != si.EventRecord_REMOVE {
+		return false
+	}
+	switch event.GetType() {
+	case si.EventRecord_APP:
+		return event.GetEventChangeDetail() == si.EventRecord_DETAILS_NONE && event.GetReferenceID() == ""
+	case si.EventRecord_NODE:
+		return event.GetEventChangeDetail() == si.EventRecord_NODE_DECOMISSION
+	case si.EventRecord_QUEUE:
+		return event.GetReferenceID() == ""
+	default:
+		return false
+	}
+}
+
+// StateDecoder rebuilds the full state snapshots from delta encoded event states.
+// Event states must be decoded in the order of the events. Not safe for concurrent use.
+type StateDecoder struct {
+	objects map[string]*decodedState
+}
+
+type decodedState struct {
+	sequence uint64
+	state    interface{}
+}
+
+// NewStateDecoder creates a decoder without any known object state.
+func NewStateDecoder() *StateDecoder {
+	return &StateDecoder{
+		objects: make(map[string]*decodedState),
+	}
+}
+
+// Decode returns the full JSON snapshot for the State of an event record.
+// A state that is not delta encoded is returned as is. ErrStateKeyframeMissing is returned for a patch if the
+// previous snapshot of the object was not decoded, for instance when events were dropped or a stream was
+// joined between keyframes.
+func (d *StateDecoder) Decode(state string) (string, error) {
+	if state == "" {
+		return state, nil
+	}
+	var delta StateDelta
+	if json.Unmarshal([]byte(state), &delta) != nil || (delta.Keyframe == nil && delta.Patch == nil) {
+		// not a delta encoded state
+		return state, nil
+	}
+	if delta.Keyframe != nil {
+		current, err := decodeJSON(delta.Keyframe)
+		if err != nil {
+			return "", fmt.Errorf("invalid keyframe for object %s: %w", delta.Object, err)
+		}
+		d.objects[delta.Object] = &decodedState{sequence: delta.Sequence, state: current}
+		return string(delta.Keyframe), nil
+	}
+	previous, ok := d.objects[delta.Object]
+	if !ok || previous.sequence+1 != delta.Sequence {
+		delete(d.objects, delta.Object)
+		return "", ErrStateKeyframeMissing
+	}
+	patch, err := decodeJSON(delta.Patch)
+	if err != nil {
+		delete(d.objects, delta.Object)
+		return "", fmt.Errorf("invalid patch for object %s: %w", delta.Object, err)
+	}
+	previous.state = applyMergePatch(previous.state, patch)
+	previous.sequence = delta.Sequence
+	var decoded []byte
+	if decoded, err = json.Marshal(previous.state); err != nil {
+		return "", err
+	}
+	return string(decoded), nil
+}
+
+// Remove drops the known state of the object.
+func (d *StateDecoder) Remove(objectID string) {
+	delete(d.objects, objectID)
+}
+
+// decodeJSON decodes keeping numbers as json.Number: resource values and timestamps do not fit a float64.
+func decodeJSON(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// createMergePatch returns the RFC 7386 merge patch that turns previous into current.
+// Objects are compared member by member, any other changed value is replaced as a whole.
+// A member with a null value in current is removed by the patch, as null cannot be set by a merge patch.
+func createMergePatch(previous, current interface{}) interface{} {
+	prevObject, prevOK := previous.(map[string]interface{})
+	currObject, currOK := current.(map[string]interface{})
+	if !prevOK || !currOK {
+		return current
+	}
+	patch := make(map[string]interface{})
+	for key := range prevObject {
+		if _, ok := currObject[key]; !ok {
+			patch[key] = nil
+		}
+	}
+	for key, value := range currObject {
+		prevValue, ok := prevObject[key]
+		if ok && reflect.DeepEqual(prevValue, value) {
+			continue
+		}
+		if _, isObject := value.(map[string]interface{}); isObject && ok {
+			patch[key] = createMergePatch(prevValue, value)
+		} else {
+			patch[key] = value
+		}
+	}
+	return patch
+}
+
+// applyMergePatch applies an RFC 7386 merge patch to the target and returns the result.
+// The target is modified in place if it is an object.
+func applyMergePatch(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = make(map[string]interface{})
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+		} else {
+			targetObject[key] = applyMergePatch(targetObject[key], value)
+		}
+	}
+	return targetObject
+}
+
+// updateStateEncoding sets the keyframe interval from the configuration.
+func updateStateEncoding() {
+	configMap := configs.GetConfigMap()
+	var interval uint64
+	if common.GetConfigurationBool(configMap, configs.CMEventStateDeltaEnabled, configs.DefaultEventStateDeltaEnabled) {
+		interval = common.GetConfigurationUint(configMap, configs.CMEventStateKeyframeInterval, configs.DefaultEventStateKeyframeInterval)
+		if interval == 0 {
+			interval = configs.DefaultEventStateKeyframeInterval
+		}
+	}
+	stateKeyframeInterval.Store(interval)
+}
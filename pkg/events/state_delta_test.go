@@ -0,0 +1,306 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/G-Research/yunikorn-core/pkg/common/configs"
+	"github.com/G-Research/yunikorn-scheduler-interface/lib/go/si"
+)
+
+func setKeyframeInterval(t *testing.T, interval uint64) {
+	previous := stateKeyframeInterval.Swap(interval)
+	t.Cleanup(func() {
+		stateKeyframeInterval.Store(previous)
+	})
+}
+
+func getStateDelta(t *testing.T, state string) StateDelta {
+	var delta StateDelta
+	assert.NilError(t, json.Unmarshal([]byte(state), &delta))
+	return delta
+}
+
+// encodeState returns the encoded state of an event carrying the snapshot.
+func encodeState(encoders *stateEncoders, snapshot string) string {
+	return encoders.encode(&si.EventRecord{Type: si.EventRecord_APP, EventChangeType: si.EventRecord_SET, State: snapshot}).State
+}
+
+func TestStateEncoder_Disabled(t *testing.T) {
+	setKeyframeInterval(t, 0)
+	encoders := newStateEncoders()
+	snapshot := `{"id":"app-1"}` + "\n"
+	assert.Equal(t, snapshot, encodeState(encoders, snapshot))
+	assert.Equal(t, snapshot, encodeState(encoders, snapshot))
+}
+
+func TestStateEncoder_Keyframes(t *testing.T) {
+	setKeyframeInterval(t, 3)
+	encoders := newStateEncoders()
+	snapshots := []string{
+		`{"id":"app-1","state":"New","used":{"memory":1}}`,
+		`{"id":"app-1","state":"Accepted","used":{"memory":1}}`,
+		`{"id":"app-1","state":"Running","used":{"memory":2,"vcore":1}}`,
+		`{"id":"app-1","state":"Running","used":{"vcore":1}}`,
+		`{"id":"app-1","state":"Running"}`,
+	}
+	expected := []StateDelta{
+		{Object: "app-1", Sequence: 0, Keyframe: json.RawMessage(snapshots[0])},
+		{Object: "app-1", Sequence: 1, Patch: json.RawMessage(`{"state":"Accepted"}`)},
+		{Object: "app-1", Sequence: 2, Patch: json.RawMessage(`{"state":"Running","used":{"memory":2,"vcore":1}}`)},
+		{Object: "app-1", Sequence: 3, Keyframe: json.RawMessage(snapshots[3])},
+		{Object: "app-1", Sequence: 4, Patch: json.RawMessage(`{"used":null}`)},
+	}
+	for i, snapshot := range snapshots {
+		delta := getStateDelta(t, encodeState(encoders, snapshot+"\n"))
+		assert.DeepEqual(t, expected[i], delta)
+	}
+}
+
+func TestStateEncoder_LargeNumbers(t *testing.T) {
+	setKeyframeInterval(t, 10)
+	encoders := newStateEncoders()
+	encodeState(encoders, `{"id":"obj-1","time":1700000000000000001}`)
+	delta := getStateDelta(t, encodeState(encoders, `{"id":"obj-1","time":1700000000000000002}`))
+	assert.Equal(t, `{"time":1700000000000000002}`, string(delta.Patch))
+}
+
+func TestStateEncoder_InvalidSnapshot(t *testing.T) {
+	setKeyframeInterval(t, 10)
+	encoders := newStateEncoders()
+	encodeState(encoders, `{"id":"app-1"}`)
+	assert.Equal(t, "not json", encodeState(encoders, "not json"))
+	// the invalid snapshot cannot belong to the object, its sequence continues
+	delta := getStateDelta(t, encodeState(encoders, `{"id":"app-1","state":"New"}`))
+	assert.DeepEqual(t, StateDelta{Object: "app-1", Sequence: 1, Patch: json.RawMessage(`{"state":"New"}`)}, delta)
+}
+
+func TestStateDecoder(t *testing.T) {
+	setKeyframeInterval(t, 4)
+	encoders := newStateEncoders()
+	decoder := NewStateDecoder()
+	snapshots := []string{
+		`{"allocations":[{"key":"a1"}],"id":"app-1","used":{"memory":1}}`,
+		`{"allocations":[{"key":"a1"},{"key":"a2"}],"id":"app-1","used":{"memory":2}}`,
+		`{"id":"app-1","resources":{"memory":2},"used":{"memory":2,"vcore":1}}`,
+		`{"id":"app-1","used":{"vcore":1}}`,
+		`{"id":"app-1"}`,
+		`{"id":"app-1","used":{"memory":5}}`,
+	}
+	for i, snapshot := range snapshots {
+		decoded, err := decoder.Decode(encodeState(encoders, snapshot))
+		assert.NilError(t, err, "decode of snapshot %d failed", i)
+		assert.Equal(t, snapshot, decoded, "decoded snapshot %d", i)
+		// other objects do not interfere
+		decoded, err = decoder.Decode(encodeState(encoders, `{"id":"queue"}`))
+		assert.NilError(t, err)
+		assert.Equal(t, `{"id":"queue"}`, decoded)
+	}
+}
+
+func TestStateDecoder_MissingKeyframe(t *testing.T) {
+	setKeyframeInterval(t, 3)
+	encoders := newStateEncoders()
+	decoder := NewStateDecoder()
+	states := make([]string, 0)
+	for _, snapshot := range []string{`{"id":"obj-1","v":1}`, `{"id":"obj-1","v":2}`, `{"id":"obj-1","v":3}`, `{"id":"obj-1","v":4}`, `{"id":"obj-1","v":5}`} {
+		states = append(states, encodeState(encoders, snapshot))
+	}
+	// joined after the keyframe
+	_, err := decoder.Decode(states[1])
+	assert.Assert(t, errors.Is(err, ErrStateKeyframeMissing))
+	// a dropped patch breaks the sequence until the next keyframe
+	decoded, err := decoder.Decode(states[0])
+	assert.NilError(t, err)
+	assert.Equal(t, `{"id":"obj-1","v":1}`, decoded)
+	_, err = decoder.Decode(states[2])
+	assert.Assert(t, errors.Is(err, ErrStateKeyframeMissing))
+	decoded, err = decoder.Decode(states[3])
+	assert.NilError(t, err)
+	assert.Equal(t, `{"id":"obj-1","v":4}`, decoded)
+	decoded, err = decoder.Decode(states[4])
+	assert.NilError(t, err)
+	assert.Equal(t, `{"id":"obj-1","v":5}`, decoded)
+
+	decoder.Remove("obj-1")
+	_, err = decoder.Decode(encodeState(encoders, `{"id":"obj-1","v":6}`))
+	assert.Assert(t, errors.Is(err, ErrStateKeyframeMissing))
+}
+
+func TestStateDecoder_PlainState(t *testing.T) {
+	decoder := NewStateDecoder()
+	for _, state := range []string{"", "not json", `{"id":"app-1"}`, `["a"]`} {
+		decoded, err := decoder.Decode(state)
+		assert.NilError(t, err)
+		assert.Equal(t, state, decoded)
+	}
+}
+
+func TestStateEncoders(t *testing.T) {
+	setKeyframeInterval(t, 10)
+	encoders := newStateEncoders()
+	newEvent := func(state string) *si.EventRecord {
+		return &si.EventRecord{Type: si.EventRecord_APP, ObjectID: "app-1", EventChangeType: si.EventRecord_SET, State: state}
+	}
+
+	event := newEvent(`{"id":"ulid-1","state":"New"}`)
+	encoded := encoders.encode(event)
+	assert.Equal(t, `{"id":"ulid-1","state":"New"}`, event.State, "event passed in must not be modified")
+	assert.Equal(t, "app-1", encoded.ObjectID)
+	assert.DeepEqual(t, StateDelta{Object: "ulid-1", Sequence: 0, Keyframe: json.RawMessage(event.State)}, getStateDelta(t, encoded.State))
+	// events of another type share the encoder of the object
+	event = newEvent(`{"id":"ulid-1","state":"Running"}`)
+	event.Type = si.EventRecord_REQUEST
+	assert.DeepEqual(t, StateDelta{Object: "ulid-1", Sequence: 1, Patch: json.RawMessage(`{"state":"Running"}`)}, getStateDelta(t, encoders.encode(event).State))
+	assert.DeepEqual(t, StateDelta{Object: "ulid-2", Sequence: 0, Keyframe: json.RawMessage(`{"id":"ulid-2"}`)}, getStateDelta(t, encoders.encode(newEvent(`{"id":"ulid-2"}`)).State))
+	assert.Equal(t, 2, len(encoders.objects))
+
+	// states without an object ID are not encoded
+	for _, state := range []string{"", "not json", `["ulid-1"]`, `{"state":"New"}`} {
+		event = newEvent(state)
+		assert.Equal(t, event, encoders.encode(event), "state %q should not be encoded", state)
+	}
+
+	// removal of the object drops its encoder
+	event = newEvent(`{"id":"ulid-1","state":"Completed"}`)
+	event.EventChangeType = si.EventRecord_REMOVE
+	assert.DeepEqual(t, StateDelta{Object: "ulid-1", Sequence: 2, Patch: json.RawMessage(`{"state":"Completed"}`)}, getStateDelta(t, encoders.encode(event).State))
+	assert.Equal(t, 1, len(encoders.objects))
+
+	// disabling the encoding drops all encoders
+	setKeyframeInterval(t, 0)
+	event = newEvent(`{"id":"ulid-2"}`)
+	assert.Equal(t, event, encoders.encode(event))
+	assert.Equal(t, 0, len(encoders.objects))
+}
+
+func TestStateEncoders_RemoveIdle(t *testing.T) {
+	setKeyframeInterval(t, 10)
+	encoders := newStateEncoders()
+	encodeState(encoders, `{"id":"app-1"}`)
+	encodeState(encoders, `{"id":"node-1"}`)
+	assert.Equal(t, 2, len(encoders.objects))
+
+	now := time.Now().UnixNano()
+	encoders.removeIdle(now)
+	assert.Equal(t, 2, len(encoders.objects), "active encoders should be kept")
+
+	// only the encoder of the object without events is released
+	encoders.objects["app-1"].lastUsed = now - (stateEncoderIdleTimeout + time.Second).Nanoseconds()
+	encoders.removeIdle(now + time.Second.Nanoseconds())
+	assert.Equal(t, 2, len(encoders.objects), "idle encoders should not be checked within the prune interval")
+	encoders.removeIdle(now + stateEncoderPruneInterval.Nanoseconds())
+	assert.Equal(t, 1, len(encoders.objects))
+	_, ok := encoders.objects["node-1"]
+	assert.Assert(t, ok, "encoder of the active object should be kept")
+
+	// the next event of a released object starts with a keyframe
+	delta := getStateDelta(t, encodeState(encoders, `{"id":"app-1","state":"Running"}`))
+	assert.DeepEqual(t, StateDelta{Object: "app-1", Sequence: 0, Keyframe: json.RawMessage(`{"id":"app-1","state":"Running"}`)}, delta)
+}
+
+func TestIsObjectRemoved(t *testing.T) {
+	tests := map[string]struct {
+		event   *si.EventRecord
+		removed bool
+	}{
+		"app set":         {&si.EventRecord{Type: si.EventRecord_APP, EventChangeType: si.EventRecord_SET}, false},
+		"app removed":     {&si.EventRecord{Type: si.EventRecord_APP, EventChangeType: si.EventRecord_REMOVE}, true},
+		"app alloc":       {&si.EventRecord{Type: si.EventRecord_APP, EventChangeType: si.EventRecord_REMOVE, ReferenceID: "alloc-1", EventChangeDetail: si.EventRecord_ALLOC_CANCEL}, false},
+		"node removed":    {&si.EventRecord{Type: si.EventRecord_NODE, EventChangeType: si.EventRecord_REMOVE, EventChangeDetail: si.EventRecord_NODE_DECOMISSION}, true},
+		"node alloc":      {&si.EventRecord{Type: si.EventRecord_NODE, EventChangeType: si.EventRecord_REMOVE, ReferenceID: "alloc-1", EventChangeDetail: si.EventRecord_NODE_ALLOC}, false},
+		"queue removed":   {&si.EventRecord{Type: si.EventRecord_QUEUE, EventChangeType: si.EventRecord_REMOVE, EventChangeDetail: si.EventRecord_QUEUE_DYNAMIC}, true},
+		"queue app":       {&si.EventRecord{Type: si.EventRecord_QUEUE, EventChangeType: si.EventRecord_REMOVE, ReferenceID: "app-1", EventChangeDetail: si.EventRecord_QUEUE_APP}, false},
+		"request removed": {&si.EventRecord{Type: si.EventRecord_REQUEST, EventChangeType: si.EventRecord_REMOVE}, false},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.removed, isObjectRemoved(tc.event))
+		})
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	// examples from RFC 7386 appendix A, except for null values in the result
+	tests := map[string]struct {
+		previous string
+		current  string
+		patch    string
+	}{
+		"replace member":  {`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		"add member":      {`{"a":"b"}`, `{"a":"b","b":"c"}`, `{"b":"c"}`},
+		"remove member":   {`{"a":"b","b":"c"}`, `{"b":"c"}`, `{"a":null}`},
+		"replace array":   {`{"a":["b"]}`, `{"a":["c"]}`, `{"a":["c"]}`},
+		"nested object":   {`{"a":{"b":"c","d":"e"}}`, `{"a":{"b":"d"}}`, `{"a":{"b":"d","d":null}}`},
+		"object to value": {`{"a":{"b":"c"}}`, `{"a":"b"}`, `{"a":"b"}`},
+		"value to object": {`{"a":"b"}`, `{"a":{"b":"c"}}`, `{"a":{"b":"c"}}`},
+		"no change":       {`{"a":{"b":[1,2]}}`, `{"a":{"b":[1,2]}}`, `{}`},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			previous, err := decodeJSON([]byte(tc.previous))
+			assert.NilError(t, err)
+			current, err := decodeJSON([]byte(tc.current))
+			assert.NilError(t, err)
+			patch, err := json.Marshal(createMergePatch(previous, current))
+			assert.NilError(t, err)
+			assert.Equal(t, tc.patch, string(patch))
+			result, err := json.Marshal(applyMergePatch(previous, createMergePatch(previous, current)))
+			assert.NilError(t, err)
+			assert.Equal(t, tc.current, string(result))
+		})
+	}
+}
+
+func TestUpdateStateEncoding(t *testing.T) {
+	original := configs.GetConfigMap()
+	defer configs.SetConfigMap(original)
+	setKeyframeInterval(t, 0)
+
+	configs.SetConfigMap(map[string]string{})
+	updateStateEncoding()
+	assert.Equal(t, uint64(0), stateKeyframeInterval.Load())
+	configs.SetConfigMap(map[string]string{configs.CMEventStateDeltaEnabled: "true"})
+	updateStateEncoding()
+	assert.Equal(t, configs.DefaultEventStateKeyframeInterval, stateKeyframeInterval.Load())
+	configs.SetConfigMap(map[string]string{
+		configs.CMEventStateDeltaEnabled:     "true",
+		configs.CMEventStateKeyframeInterval: "0",
+	})
+	updateStateEncoding()
+	assert.Equal(t, configs.DefaultEventStateKeyframeInterval, stateKeyframeInterval.Load())
+	configs.SetConfigMap(map[string]string{
+		configs.CMEventStateDeltaEnabled:     "true",
+		configs.CMEventStateKeyframeInterval: "20",
+	})
+	updateStateEncoding()
+	assert.Equal(t, uint64(20), stateKeyframeInterval.Load())
+	configs.SetConfigMap(map[string]string{
+		configs.CMEventStateDeltaEnabled:     "false",
+		configs.CMEventStateKeyframeInterval: "20",
+	})
+	updateStateEncoding()
+	assert.Equal(t, uint64(0), stateKeyframeInterval.Load())
+}
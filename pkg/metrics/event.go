@@ -18,7 +18,20 @@
 
 package metrics
 
-import "github.com/prometheus/client_golang/prometheus"
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"go.uber.org/zap"
+
+	"github.com/G-Research/yunikorn-core/pkg/log"
+)
+
+// reasons for dropping an event, used as metric label
+const (
+	EventDropChannel = "channel" // the event system channel was full
+	EventDropStore   = "store"   // the store of the shim publisher was full
+	EventDropStream  = "stream"  // the buffer of a slow stream consumer was full
+)
 
 type EventMetrics struct {
 	totalEventsCreated      prometheus.Gauge
@@ -28,6 +41,13 @@ type EventMetrics struct {
 	totalEventsStored       prometheus.Gauge
 	totalEventsNotStored    prometheus.Gauge
 	totalEventsCollected    prometheus.Gauge
+	totalEventsSinkWritten  prometheus.Gauge
+	totalEventsSinkDropped  prometheus.Gauge
+	totalEventsSinkFailed   prometheus.Gauge
+	totalEventsEvicted      *prometheus.CounterVec
+	totalEventsDropped      *prometheus.CounterVec
+	channelSize             prometheus.Gauge
+	streamLag               *prometheus.GaugeVec
 }
 
 func initEventMetrics() *EventMetrics {
@@ -82,7 +102,71 @@ func initEventMetrics() *EventMetrics {
 			Name:      "total_collected",
 			Help:      "total events collected",
 		})
+	metrics.totalEventsSinkWritten = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: EventSubsystem,
+			Name:      "total_sink_written",
+			Help:      "total events written to the file sink",
+		})
+	metrics.totalEventsSinkDropped = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: EventSubsystem,
+			Name:      "total_sink_dropped",
+			Help:      "total events dropped because the file sink queue was full",
+		})
+	metrics.totalEventsSinkFailed = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: EventSubsystem,
+			Name:      "total_sink_failed",
+			Help:      "total events that could not be written to the file sink",
+		})
+	metrics.totalEventsEvicted = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: EventSubsystem,
+			Name:      "total_evicted",
+			Help:      "total events evicted from the ring buffer by event type and reason: capacity, age or quota",
+		}, []string{"event_type", "reason"})
+	metrics.totalEventsDropped = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: EventSubsystem,
+			Name:      "total_dropped",
+			Help:      "total events dropped by event type and reason: channel, store or stream",
+		}, []string{"event_type", "reason"})
+	metrics.channelSize = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: EventSubsystem,
+			Name:      "channel_size",
+			Help:      "number of events waiting in the event system channel",
+		})
+	metrics.streamLag = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: EventSubsystem,
+			Name:      "stream_lag",
+			Help:      "number of events waiting to be read by the event stream consumers with the same name",
+		}, []string{"consumer"})
 
+	// Register the metrics
+	var metricsList = []prometheus.Collector{
+		metrics.totalEventsSinkWritten,
+		metrics.totalEventsSinkDropped,
+		metrics.totalEventsSinkFailed,
+		metrics.totalEventsEvicted,
+		metrics.totalEventsDropped,
+		metrics.channelSize,
+		metrics.streamLag,
+	}
+	for _, metric := range metricsList {
+		if err := prometheus.Register(metric); err != nil {
+			log.Log(log.Metrics).Warn("failed to register metrics collector", zap.Error(err))
+		}
+	}
 	return metrics
 }
 
@@ -96,6 +180,13 @@ func (em *EventMetrics) Reset() {
 	em.totalEventsStored.Set(0)
 	em.totalEventsNotStored.Set(0)
 	em.totalEventsProcessed.Set(0)
+	em.totalEventsSinkWritten.Set(0)
+	em.totalEventsSinkDropped.Set(0)
+	em.totalEventsSinkFailed.Set(0)
+	em.totalEventsEvicted.Reset()
+	em.totalEventsDropped.Reset()
+	em.channelSize.Set(0)
+	em.streamLag.Reset()
 }
 
 func (em *EventMetrics) IncEventsCreated() {
@@ -125,3 +216,116 @@ func (em *EventMetrics) IncEventsNotStored() {
 func (em *EventMetrics) AddEventsCollected(collectedEvents int) {
 	em.totalEventsCollected.Add(float64(collectedEvents))
 }
+
+func (em *EventMetrics) IncEventsSinkWritten() {
+	em.totalEventsSinkWritten.Inc()
+}
+
+func (em *EventMetrics) GetEventsSinkWritten() (int, error) {
+	return getGaugeValue(em.totalEventsSinkWritten)
+}
+
+func (em *EventMetrics) IncEventsSinkDropped() {
+	em.totalEventsSinkDropped.Inc()
+}
+
+func (em *EventMetrics) GetEventsSinkDropped() (int, error) {
+	return getGaugeValue(em.totalEventsSinkDropped)
+}
+
+func (em *EventMetrics) IncEventsSinkFailed() {
+	em.totalEventsSinkFailed.Inc()
+}
+
+func (em *EventMetrics) GetEventsSinkFailed() (int, error) {
+	return getGaugeValue(em.totalEventsSinkFailed)
+}
+
+func (em *EventMetrics) IncEventsEvicted(eventType, reason string) {
+	em.totalEventsEvicted.WithLabelValues(eventType, reason).Inc()
+}
+
+func (em *EventMetrics) GetEventsEvicted(eventType, reason string) (int, error) {
+	metricDto := &dto.Metric{}
+	err := em.totalEventsEvicted.WithLabelValues(eventType, reason).Write(metricDto)
+	if err == nil {
+		return int(*metricDto.Counter.Value), nil
+	}
+	return -1, err
+}
+
+func (em *EventMetrics) IncEventsDropped(eventType, reason string) {
+	em.totalEventsDropped.WithLabelValues(eventType, reason).Inc()
+}
+
+func (em *EventMetrics) GetEventsDropped(eventType, reason string) (int, error) {
+	metricDto := &dto.Metric{}
+	err := em.totalEventsDropped.WithLabelValues(eventType, reason).Write(metricDto)
+	if err == nil {
+		return int(*metricDto.Counter.Value), nil
+	}
+	return -1, err
+}
+
+// GetTotalEventsDropped returns the number of dropped events over all types and reasons.
+func (em *EventMetrics) GetTotalEventsDropped() (int, error) {
+	return getCounterVecTotal(em.totalEventsDropped)
+}
+
+func (em *EventMetrics) SetChannelSize(size int) {
+	em.channelSize.Set(float64(size))
+}
+
+func (em *EventMetrics) GetChannelSize() (int, error) {
+	return getGaugeValue(em.channelSize)
+}
+
+// SetStreamLag replaces the lag of all stream consumers, consumers that are not in the map are removed.
+func (em *EventMetrics) SetStreamLag(lag map[string]int) {
+	em.streamLag.Reset()
+	for consumer, value := range lag {
+		em.streamLag.WithLabelValues(consumer).Set(float64(value))
+	}
+}
+
+func (em *EventMetrics) GetStreamLag(consumer string) (int, error) {
+	metricDto := &dto.Metric{}
+	err := em.streamLag.WithLabelValues(consumer).Write(metricDto)
+	if err == nil {
+		return int(*metricDto.Gauge.Value), nil
+	}
+	return -1, err
+}
+
+// getCounterVecTotal returns the sum of the counters of all label values.
+func getCounterVecTotal(vec *prometheus.CounterVec) (int, error) {
+	ch := make(chan prometheus.Metric)
+	go func() {
+		vec.Collect(ch)
+		close(ch)
+	}()
+	var total float64
+	var err error
+	// always drain the channel to not block the collector
+	for metric := range ch {
+		metricDto := &dto.Metric{}
+		if writeErr := metric.Write(metricDto); writeErr != nil {
+			err = writeErr
+			continue
+		}
+		total += metricDto.GetCounter().GetValue()
+	}
+	if err != nil {
+		return -1, err
+	}
+	return int(total), nil
+}
+
+func getGaugeValue(gauge prometheus.Gauge) (int, error) {
+	metricDto := &dto.Metric{}
+	err := gauge.Write(metricDto)
+	if err == nil {
+		return int(*metricDto.Gauge.Value), nil
+	}
+	return -1, err
+}
@@ -39,6 +39,9 @@ const (
 	NodeActive         = "active"
 	NodeDraining       = "draining"
 	NodeDecommissioned = "decommissioned"
+
+	RMEventQueueRMProxy   = "rmproxy"
+	RMEventQueueScheduler = "scheduler"
 )
 
 var resourceUsageRangeBuckets = []string{
@@ -65,6 +68,8 @@ type SchedulerMetrics struct {
 	sortingLatency        *prometheus.HistogramVec
 	tryNodeLatency        prometheus.Histogram
 	tryPreemptionLatency  prometheus.Histogram
+	rmEventQueueDepth     *prometheus.GaugeVec
+	rmEventsDropped       *prometheus.CounterVec
 	lock                  locking.RWMutex
 }
 
@@ -146,6 +151,22 @@ func InitSchedulerMetrics() *SchedulerMetrics {
 		},
 	)
 
+	s.rmEventQueueDepth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: SchedulerSubsystem,
+			Name:      "rm_event_queue_depth",
+			Help:      "Number of RM events waiting to be processed. Queue includes `rmproxy` and `scheduler`.",
+		}, []string{"queue"})
+
+	s.rmEventsDropped = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: SchedulerSubsystem,
+			Name:      "rm_event_total_dropped",
+			Help:      "Total number of RM events dropped because the queue was full. Queue includes `rmproxy` and `scheduler`.",
+		}, []string{"queue"})
+
 	// Register the metrics
 	var metricsList = []prometheus.Collector{
 		s.containerAllocation,
@@ -156,6 +177,8 @@ func InitSchedulerMetrics() *SchedulerMetrics {
 		s.sortingLatency,
 		s.tryNodeLatency,
 		s.tryPreemptionLatency,
+		s.rmEventQueueDepth,
+		s.rmEventsDropped,
 	}
 	for _, metric := range metricsList {
 		if err := prometheus.Register(metric); err != nil {
@@ -172,6 +195,8 @@ func (m *SchedulerMetrics) Reset() {
 	m.application.Reset()
 	m.applicationSubmission.Reset()
 	m.containerAllocation.Reset()
+	m.rmEventQueueDepth.Reset()
+	m.rmEventsDropped.Reset()
 }
 
 func SinceInSeconds(start time.Time) float64 {
@@ -415,3 +440,25 @@ func (m *SchedulerMetrics) GetDrainingNodes() (int, error) {
 func (m *SchedulerMetrics) IncTotalDecommissionedNodes() {
 	m.node.WithLabelValues(NodeDecommissioned).Inc()
 }
+
+func (m *SchedulerMetrics) SetRMEventQueueDepth(queue string, depth int) {
+	m.rmEventQueueDepth.WithLabelValues(queue).Set(float64(depth))
+}
+
+func (m *SchedulerMetrics) GetRMEventQueueDepth(queue string) (int, error) {
+	metricDto := &dto.Metric{}
+	err := m.rmEventQueueDepth.WithLabelValues(queue).Write(metricDto)
+	if err == nil {
+		return int(*metricDto.Gauge.Value), nil
+	}
+	return -1, err
+}
+
+func (m *SchedulerMetrics) IncRMEventsDropped(queue string) {
+	m.rmEventsDropped.WithLabelValues(queue).Inc()
+}
+
+// GetTotalRMEventsDropped returns the number of dropped RM events over all queues.
+func (m *SchedulerMetrics) GetTotalRMEventsDropped() (int, error) {
+	return getCounterVecTotal(m.rmEventsDropped)
+}
@@ -60,6 +60,25 @@ func TestTryPreemptionLatency(t *testing.T) {
 	verifyHistogram(t, "trypreemption_latency_milliseconds", 60, 1)
 }
 
+func TestRMEventQueue(t *testing.T) {
+	sm = getSchedulerMetrics(t)
+	defer unregisterMetrics()
+
+	sm.SetRMEventQueueDepth(RMEventQueueRMProxy, 5)
+	verifyMetric(t, 5, RMEventQueueRMProxy, "yunikorn_scheduler_rm_event_queue_depth", dto.MetricType_GAUGE, "queue")
+	depth, err := sm.GetRMEventQueueDepth(RMEventQueueRMProxy)
+	assert.NilError(t, err)
+	assert.Equal(t, 5, depth)
+
+	sm.IncRMEventsDropped(RMEventQueueScheduler)
+	sm.IncRMEventsDropped(RMEventQueueScheduler)
+	verifyMetric(t, 2, RMEventQueueScheduler, "yunikorn_scheduler_rm_event_total_dropped", dto.MetricType_COUNTER, "queue")
+	sm.IncRMEventsDropped(RMEventQueueRMProxy)
+	dropped, err := sm.GetTotalRMEventsDropped()
+	assert.NilError(t, err)
+	assert.Equal(t, 3, dropped)
+}
+
 func TestSchedulerApplicationsNew(t *testing.T) {
 	sm = getSchedulerMetrics(t)
 	defer unregisterMetrics()
@@ -226,4 +245,6 @@ func unregisterMetrics() {
 	prometheus.Unregister(sm.sortingLatency)
 	prometheus.Unregister(sm.tryNodeLatency)
 	prometheus.Unregister(sm.tryPreemptionLatency)
+	prometheus.Unregister(sm.rmEventQueueDepth)
+	prometheus.Unregister(sm.rmEventsDropped)
 }
@@ -57,11 +57,13 @@ func (rmp *RMProxy) GetRMEventHandler() handler.EventHandler {
 func enqueueAndCheckFull(queue chan interface{}, ev interface{}) {
 	select {
 	case queue <- ev:
+		metrics.GetSchedulerMetrics().SetRMEventQueueDepth(metrics.RMEventQueueRMProxy, len(queue))
 		log.Log(log.RMProxy).Debug("enqueue event",
 			zap.Stringer("eventType", reflect.TypeOf(ev)),
 			zap.Any("event", ev),
 			zap.Int("currentQueueSize", len(queue)))
 	default:
+		metrics.GetSchedulerMetrics().IncRMEventsDropped(metrics.RMEventQueueRMProxy)
 		log.Log(log.RMProxy).DPanic("failed to enqueue event",
 			zap.Stringer("event", reflect.TypeOf(ev)))
 	}
@@ -188,6 +190,7 @@ func (rmp *RMProxy) handleRMEvents() {
 	for {
 		select {
 		case ev := <-rmp.pendingRMEvents:
+			metrics.GetSchedulerMetrics().SetRMEventQueueDepth(metrics.RMEventQueueRMProxy, len(rmp.pendingRMEvents))
 			switch v := ev.(type) {
 			case *rmevent.RMNewAllocationsEvent:
 				rmp.processAllocationUpdateEvent(v)
@@ -0,0 +1,113 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/G-Research/yunikorn-core/pkg/common/security"
+	"github.com/G-Research/yunikorn-core/pkg/log"
+	"github.com/G-Research/yunikorn-core/pkg/scheduler/objects"
+	"github.com/G-Research/yunikorn-scheduler-interface/lib/go/si"
+)
+
+// ErrQueueAdminAccess is returned when the user of an application action is not an administrator of the queue.
+var ErrQueueAdminAccess = errors.New("user does not have admin access to the queue")
+
+// FailApplication fails the application on request of an operator. The pending requests are removed and the RM is
+// asked to release all allocations, the application fails once the RM has released the last allocation.
+// The user must have admin access to the queue of the application, a nil user skips the access check.
+func (cc *ClusterContext) FailApplication(partition *PartitionContext, app *objects.Application, user *security.UserGroup, message string) error {
+	if err := checkApplicationAdmin(app, user); err != nil {
+		return err
+	}
+	released, err := partition.failApplication(app, message)
+	if err != nil {
+		return err
+	}
+	cc.releaseApplication(partition, app, released, message)
+	return nil
+}
+
+// KillApplication fails the application on request of an operator and removes all requests and allocations from
+// the scheduler immediately. The RM is asked to release the removed allocations.
+// The user must have admin access to the queue of the application, a nil user skips the access check.
+func (cc *ClusterContext) KillApplication(partition *PartitionContext, app *objects.Application, user *security.UserGroup, message string) error {
+	if err := checkApplicationAdmin(app, user); err != nil {
+		return err
+	}
+	released, err := partition.killApplication(app, message)
+	if err != nil {
+		return err
+	}
+	cc.releaseApplication(partition, app, released, message)
+	return nil
+}
+
+// MoveApplication moves the application to another leaf queue in the partition on request of an operator.
+// The user must have admin access to the current and the target queue, a nil user skips the access check.
+func (cc *ClusterContext) MoveApplication(partition *PartitionContext, app *objects.Application, queuePath string, user *security.UserGroup) error {
+	if err := checkApplicationAdmin(app, user); err != nil {
+		return err
+	}
+	queue := partition.GetQueue(queuePath)
+	if queue == nil {
+		return fmt.Errorf("queue %s does not exist in partition %s", queuePath, partition.Name)
+	}
+	if user != nil && !queue.CheckAdminAccess(*user) {
+		return fmt.Errorf("%w %s", ErrQueueAdminAccess, queue.QueuePath)
+	}
+	source := app.GetQueuePath()
+	if err := partition.moveApplication(app, queue); err != nil {
+		return err
+	}
+	log.Log(log.SchedContext).Info("application moved",
+		zap.String("applicationID", app.ApplicationID),
+		zap.String("partition", partition.Name),
+		zap.String("source", source),
+		zap.String("target", queue.QueuePath))
+	return nil
+}
+
+// checkApplicationAdmin checks that the user has admin access to the queue of the application.
+func checkApplicationAdmin(app *objects.Application, user *security.UserGroup) error {
+	queue := app.GetQueue()
+	if queue == nil {
+		return fmt.Errorf("application %s is not assigned to a queue", app.ApplicationID)
+	}
+	if user != nil && !queue.CheckAdminAccess(*user) {
+		return fmt.Errorf("%w %s", ErrQueueAdminAccess, queue.QueuePath)
+	}
+	return nil
+}
+
+// releaseApplication asks the RM to release the requests and allocations removed from a failed application.
+func (cc *ClusterContext) releaseApplication(partition *PartitionContext, app *objects.Application, released []*objects.Allocation, message string) {
+	log.Log(log.SchedContext).Info("application failed by operator",
+		zap.String("applicationID", app.ApplicationID),
+		zap.String("partition", partition.Name),
+		zap.String("message", message),
+		zap.Int("released", len(released)))
+	if len(released) != 0 {
+		cc.notifyRMAllocationReleased(partition.RmID, partition.Name, released, si.TerminationType_PREEMPTED_BY_SCHEDULER, message)
+	}
+}
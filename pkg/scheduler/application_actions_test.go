@@ -0,0 +1,238 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/G-Research/yunikorn-core/pkg/common"
+	"github.com/G-Research/yunikorn-core/pkg/common/configs"
+	"github.com/G-Research/yunikorn-core/pkg/common/resources"
+	"github.com/G-Research/yunikorn-core/pkg/common/security"
+	"github.com/G-Research/yunikorn-core/pkg/scheduler/objects"
+	"github.com/G-Research/yunikorn-core/pkg/scheduler/ugm"
+	"github.com/G-Research/yunikorn-scheduler-interface/lib/go/si"
+)
+
+func createActionTestContext(t *testing.T) (*ClusterContext, *PartitionContext) {
+	context := &ClusterContext{
+		partitions:     map[string]*PartitionContext{},
+		rmEventHandler: newMockEventHandler(),
+	}
+	conf := configs.PartitionConfig{
+		Name: pName,
+		Queues: []configs.QueueConfig{
+			{
+				Name:      "root",
+				Parent:    true,
+				SubmitACL: "*",
+				AdminACL:  "admin",
+				Queues: []configs.QueueConfig{
+					{
+						Name:   "parent",
+						Parent: true,
+						Resources: configs.Resources{
+							Max: map[string]string{"first": "5"},
+						},
+						Queues: []configs.QueueConfig{
+							{Name: "a"},
+							{Name: "b", AdminACL: "bob"},
+						},
+					},
+					{
+						Name:      "small",
+						Resources: configs.Resources{Max: map[string]string{"first": "1"}},
+					},
+					{Name: "single", MaxApplications: 1},
+					{
+						Name: "limited",
+						Limits: []configs.Limit{
+							{Limit: "user", Users: []string{"testuser"}, MaxResources: map[string]string{"first": "1"}},
+						},
+					},
+				},
+			},
+		},
+	}
+	partition, err := newPartitionContext(conf, "test", context)
+	assert.NilError(t, err, "partition create should not have failed with error")
+	context.partitions[partition.Name] = partition
+	_, _ = addTestNode(t, context, "node-1")
+	return context, partition
+}
+
+// addRunningApp adds an application with an allocation of 4 and a pending request of 1 to the queue.
+func addRunningApp(t *testing.T, partition *PartitionContext, appID, queue string) *objects.Application {
+	app := newApplication(appID, pName, queue)
+	assert.NilError(t, partition.AddApplication(app), "application should have been added")
+	alloc := newAllocation("alloc-"+appID, appID, "node-1", resources.NewResourceFromMap(map[string]resources.Quantity{"first": 4}))
+	_, allocCreated, err := partition.UpdateAllocation(alloc)
+	assert.NilError(t, err, "allocation should have been added")
+	assert.Assert(t, allocCreated, "allocation should have been created")
+	ask := newAllocationAsk("ask-"+appID, appID, resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1}))
+	assert.NilError(t, app.AddAllocationAsk(ask), "ask should have been added")
+	assert.Assert(t, app.IsRunning(), "application should be running")
+	return app
+}
+
+func TestMoveApplication(t *testing.T) {
+	setupUGM()
+	defer setupUGM()
+	context, partition := createActionTestContext(t)
+	app := addRunningApp(t, partition, "app-1", "root.parent.a")
+	source := partition.GetQueue("root.parent.a")
+	target := partition.GetQueue("root.parent.b")
+	allocated := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 4})
+	pending := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1})
+
+	// checks that fail without changes
+	bob := &security.UserGroup{User: "bob"}
+	err := context.MoveApplication(partition, app, "root.parent.b", bob)
+	assert.Assert(t, errors.Is(err, ErrQueueAdminAccess), "unexpected error: %v", err)
+	err = context.MoveApplication(partition, app, "root.unknown", nil)
+	assert.Error(t, err, "queue root.unknown does not exist in partition default")
+	err = context.MoveApplication(partition, app, "root.parent", nil)
+	assert.Error(t, err, "queue root.parent is not a leaf queue that can run applications")
+	err = context.MoveApplication(partition, app, "root.parent.a", nil)
+	assert.Error(t, err, "application app-1 is already in queue root.parent.a")
+	err = context.MoveApplication(partition, app, "root.small", nil)
+	assert.ErrorContains(t, err, "put queue 'root.small' over maximum allocation")
+	addRunningApp(t, partition, "app-2", "root.single")
+	err = context.MoveApplication(partition, app, "root.single", nil)
+	assert.Error(t, err, "application app-1 puts queue 'root.single' over maximum running applications (1)")
+	err = context.MoveApplication(partition, app, "root.limited", nil)
+	assert.Error(t, err, "application app-1 cannot be moved to queue root.limited: usage of user testuser would exceed the maximum resources of queue root.limited for first")
+	assert.Equal(t, "root.parent.a", app.GetQueuePath())
+	assert.Assert(t, resources.Equals(allocated, source.GetAllocatedResource()), "source allocation changed")
+	assert.Equal(t, uint64(1), source.GetRunningApps())
+	userManager := ugm.GetUserManager()
+	assert.Assert(t, resources.Equals(allocated, userManager.GetUserQueueUsage("testuser", "root.parent.a")), "source user usage changed")
+	assert.Assert(t, userManager.GetUserQueueUsage("testuser", "root.limited") == nil, "target should not have user usage")
+	assert.Assert(t, resources.Equals(allocated, userManager.GetUserQueueUsage("testuser", "root.single")), "user usage of app-2 changed")
+
+	// move within the parent: the parent does not change
+	generation := partition.GetGeneration()
+	err = context.MoveApplication(partition, app, "root.parent.b", &security.UserGroup{User: "admin"})
+	assert.NilError(t, err, "move should not have failed")
+	assert.Equal(t, "root.parent.b", app.GetQueuePath())
+	assert.Equal(t, target, app.GetQueue())
+	assert.Assert(t, partition.GetGeneration() > generation, "partition should have changed")
+	assert.Assert(t, source.GetApplication("app-1") == nil, "application should have been removed from the source")
+	assert.Equal(t, app, target.GetApplication("app-1"))
+	assert.Assert(t, resources.IsZero(source.GetAllocatedResource()), "source allocation should have been removed")
+	assert.Assert(t, resources.IsZero(source.GetPendingResource()), "source pending should have been removed")
+	assert.Equal(t, uint64(0), source.GetRunningApps())
+	assert.Assert(t, resources.Equals(allocated, target.GetAllocatedResource()), "target allocation not moved")
+	assert.Assert(t, resources.Equals(pending, target.GetPendingResource()), "target pending not moved")
+	assert.Equal(t, uint64(1), target.GetRunningApps())
+	parent := partition.GetQueue("root.parent")
+	assert.Assert(t, resources.Equals(allocated, parent.GetAllocatedResource()), "parent allocation changed")
+	assert.Equal(t, uint64(1), parent.GetRunningApps())
+	assert.Assert(t, userManager.GetUserQueueUsage("testuser", "root.parent.a") == nil, "source should not have user usage")
+	assert.Assert(t, resources.Equals(allocated, userManager.GetUserQueueUsage("testuser", "root.parent.b")), "target user usage not moved")
+	assert.Assert(t, resources.Equals(allocated, userManager.GetUserQueueUsage("testuser", "root.parent")), "parent user usage changed")
+
+	// move to another parent
+	err = context.MoveApplication(partition, app, "root.single", nil)
+	assert.ErrorContains(t, err, "over maximum running applications")
+	partition.GetQueue("root.single").SetMaxRunningApps(0)
+	err = context.MoveApplication(partition, app, "root.single", nil)
+	assert.NilError(t, err, "move should not have failed")
+	assert.Assert(t, resources.IsZero(parent.GetAllocatedResource()), "parent allocation should have been removed")
+	assert.Equal(t, uint64(0), parent.GetRunningApps())
+	root := partition.GetQueue("root")
+	assert.Equal(t, uint64(2), root.GetRunningApps())
+	assert.Assert(t, resources.Equals(resources.Multiply(allocated, 2), root.GetAllocatedResource()), "root allocation changed")
+	assert.Assert(t, userManager.GetUserQueueUsage("testuser", "root.parent") == nil, "parent should not have user usage")
+	assert.Assert(t, resources.Equals(resources.Multiply(allocated, 2), userManager.GetUserQueueUsage("testuser", "root.single")), "target user usage not moved")
+	assert.Assert(t, resources.Equals(resources.Multiply(allocated, 2), userManager.GetUserQueueUsage("testuser", "root")), "root user usage changed")
+}
+
+func TestFailApplication(t *testing.T) {
+	context, partition := createActionTestContext(t)
+	handler := context.rmEventHandler.(*mockEventHandler) //nolint:errcheck
+	app := addRunningApp(t, partition, "app-1", "root.parent.a")
+
+	err := context.FailApplication(partition, app, &security.UserGroup{User: "bob"}, "failed")
+	assert.Assert(t, errors.Is(err, ErrQueueAdminAccess), "unexpected error: %v", err)
+	assert.Assert(t, app.IsRunning(), "application should be running")
+
+	// the allocations stay until the RM has released them
+	err = context.FailApplication(partition, app, &security.UserGroup{User: "admin"}, "failed by admin")
+	assert.NilError(t, err, "fail should not have failed")
+	assert.Assert(t, app.IsFailing(), "application should be failing")
+	assert.Assert(t, resources.IsZero(app.GetPendingResource()), "pending requests should have been removed")
+	released := handler.getReleased()
+	assert.Equal(t, 2, len(released), "request and allocation should have been released")
+	assert.Equal(t, "ask-app-1", released[0].AllocationKey)
+	assert.Equal(t, "alloc-app-1", released[1].AllocationKey)
+	assert.Equal(t, "failed by admin", released[1].Message)
+	err = context.FailApplication(partition, app, nil, "failed again")
+	assert.Error(t, err, "application app-1 is already failing")
+	assert.Assert(t, app.IsFailing(), "application should be failing")
+
+	partition.removeAllocation(&si.AllocationRelease{
+		PartitionName:   pName,
+		ApplicationID:   "app-1",
+		AllocationKey:   "alloc-app-1",
+		TerminationType: si.TerminationType_STOPPED_BY_RM,
+	})
+	assert.Assert(t, app.IsFailed(), "application should have failed after the release")
+
+	// without allocations the application fails immediately
+	app = newApplication("app-2", pName, "root.parent.b")
+	assert.NilError(t, partition.AddApplication(app), "application should have been added")
+	err = context.FailApplication(partition, app, nil, "failed")
+	assert.NilError(t, err, "fail should not have failed")
+	assert.Assert(t, app.IsFailed(), "application should have failed")
+}
+
+func TestKillApplication(t *testing.T) {
+	context, partition := createActionTestContext(t)
+	handler := context.rmEventHandler.(*mockEventHandler) //nolint:errcheck
+	app := addRunningApp(t, partition, "app-1", "root.parent.a")
+	node := partition.GetNode("node-1")
+
+	err := context.KillApplication(partition, app, &security.UserGroup{User: "bob"}, "killed")
+	assert.Assert(t, errors.Is(err, ErrQueueAdminAccess), "unexpected error: %v", err)
+
+	// a failing application is killed without waiting for the RM
+	err = context.FailApplication(partition, app, nil, "failed")
+	assert.NilError(t, err, "fail should not have failed")
+	err = context.KillApplication(partition, app, nil, "killed")
+	assert.NilError(t, err, "kill should not have failed")
+	assert.Assert(t, app.IsFailed(), "application should have failed")
+	assert.Equal(t, 3, len(handler.getReleased()), "allocation should have been released again")
+	assert.Equal(t, 0, partition.GetTotalAllocationCount())
+	assert.Equal(t, 0, len(node.GetYunikornAllocations()), "allocation should have been removed from the node")
+	for _, path := range []string{"root", "root.parent", "root.parent.a"} {
+		queue := partition.GetQueue(path)
+		assert.Assert(t, resources.IsZero(queue.GetAllocatedResource()), "queue %s allocation not removed", path)
+		assert.Assert(t, resources.IsZero(queue.GetPendingResource()), "queue %s pending not removed", path)
+		assert.Equal(t, uint64(0), queue.GetRunningApps(), "queue %s running apps", path)
+	}
+	err = common.WaitForCondition(time.Millisecond, time.Second, func() bool {
+		return len(partition.GetCompletedApplications()) == 1
+	})
+	assert.NilError(t, err, "application should have been moved to the completed applications")
+}
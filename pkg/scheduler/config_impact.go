@@ -0,0 +1,310 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/G-Research/yunikorn-core/pkg/common"
+	"github.com/G-Research/yunikorn-core/pkg/common/configs"
+	"github.com/G-Research/yunikorn-core/pkg/common/resources"
+	"github.com/G-Research/yunikorn-core/pkg/scheduler/objects"
+	"github.com/G-Research/yunikorn-core/pkg/scheduler/ugm"
+	"github.com/G-Research/yunikorn-core/pkg/webservice/dao"
+)
+
+// GetConfigImpact compares the configuration with the active configuration and returns, per partition, the queues
+// that would be added, removed, drained or updated. It also lists the live applications, users and groups that would
+// violate the limits set in the configuration. Nothing is changed: the configuration is not applied.
+// Returns nil if there is no active configuration.
+func (cc *ClusterContext) GetConfigImpact(conf *configs.SchedulerConfig) []*dao.PartitionConfigImpactDAOInfo {
+	current := configs.ConfigContext.Get(cc.policyGroup)
+	if current == nil || conf == nil {
+		return nil
+	}
+	currentPartitions := make(map[string]configs.PartitionConfig, len(current.Partitions))
+	for _, p := range current.Partitions {
+		currentPartitions[p.Name] = p
+	}
+	var impact []*dao.PartitionConfigImpactDAOInfo
+	for _, p := range conf.Partitions {
+		old, ok := currentPartitions[p.Name]
+		if !ok {
+			impact = append(impact, &dao.PartitionConfigImpactDAOInfo{
+				Partition: p.Name,
+				Change:    dao.ConfigChangeAdded,
+			})
+			continue
+		}
+		delete(currentPartitions, p.Name)
+		partition := cc.GetPartitionWithoutClusterID(p.Name)
+		partitionImpact := &dao.PartitionConfigImpactDAOInfo{
+			Partition: p.Name,
+			Queues:    diffQueueConfigs(old.Queues, p.Queues, partition),
+		}
+		if partition != nil {
+			partitionImpact.Violations = getLimitViolations(p.Queues, partition)
+		}
+		if len(partitionImpact.Queues) > 0 || len(partitionImpact.Violations) > 0 {
+			partitionImpact.Change = dao.ConfigChangeUpdated
+		}
+		impact = append(impact, partitionImpact)
+	}
+	for _, p := range current.Partitions {
+		if _, ok := currentPartitions[p.Name]; ok {
+			impact = append(impact, &dao.PartitionConfigImpactDAOInfo{
+				Partition: p.Name,
+				Change:    dao.ConfigChangeRemoved,
+			})
+		}
+	}
+	return impact
+}
+
+// flattenQueueConfigs returns the queue configs by their fully qualified path and the paths in hierarchy order.
+func flattenQueueConfigs(queues []configs.QueueConfig, parentPath string, byPath map[string]configs.QueueConfig, paths []string) []string {
+	for _, queue := range queues {
+		path := queue.Name
+		if parentPath != common.Empty {
+			path = parentPath + configs.DOT + queue.Name
+		}
+		byPath[path] = queue
+		paths = append(paths, path)
+		paths = flattenQueueConfigs(queue.Queues, path, byPath, paths)
+	}
+	return paths
+}
+
+// diffQueueConfigs returns the queue changes between the old and new queue hierarchy.
+// A removed queue that still has applications or children in the live partition is reported as drained: it is only
+// removed after it is empty.
+func diffQueueConfigs(oldQueues, newQueues []configs.QueueConfig, partition *PartitionContext) []*dao.QueueConfigChangeDAOInfo {
+	oldByPath := make(map[string]configs.QueueConfig)
+	oldPaths := flattenQueueConfigs(oldQueues, common.Empty, oldByPath, nil)
+	newByPath := make(map[string]configs.QueueConfig)
+	newPaths := flattenQueueConfigs(newQueues, common.Empty, newByPath, nil)
+
+	var changes []*dao.QueueConfigChangeDAOInfo
+	for _, path := range newPaths {
+		newQueue := newByPath[path]
+		oldQueue, ok := oldByPath[path]
+		if !ok {
+			changes = append(changes, &dao.QueueConfigChangeDAOInfo{
+				QueuePath: path,
+				Change:    dao.ConfigChangeAdded,
+			})
+			continue
+		}
+		if fields := diffQueueConfig(oldQueue, newQueue); len(fields) > 0 {
+			changes = append(changes, &dao.QueueConfigChangeDAOInfo{
+				QueuePath: path,
+				Change:    dao.ConfigChangeUpdated,
+				Fields:    fields,
+			})
+		}
+	}
+	for _, path := range oldPaths {
+		if _, ok := newByPath[path]; ok {
+			continue
+		}
+		change := dao.ConfigChangeRemoved
+		if partition != nil {
+			if queue := partition.GetQueue(path); queue != nil && !queue.IsEmpty() {
+				change = dao.ConfigChangeDrained
+			}
+		}
+		changes = append(changes, &dao.QueueConfigChangeDAOInfo{
+			QueuePath: path,
+			Change:    change,
+		})
+	}
+	return changes
+}
+
+// diffQueueConfig returns the changed fields of a queue that exists in both configurations.
+func diffQueueConfig(oldQueue, newQueue configs.QueueConfig) []*dao.ConfigFieldChangeDAOInfo {
+	var fields []*dao.ConfigFieldChangeDAOInfo
+	addField := func(field string, oldValue, newValue interface{}) {
+		fields = append(fields, &dao.ConfigFieldChangeDAOInfo{
+			Field: field,
+			Old:   oldValue,
+			New:   newValue,
+		})
+	}
+	if !reflect.DeepEqual(oldQueue.Resources.Guaranteed, newQueue.Resources.Guaranteed) {
+		addField("guaranteed", oldQueue.Resources.Guaranteed, newQueue.Resources.Guaranteed)
+	}
+	if !reflect.DeepEqual(oldQueue.Resources.Max, newQueue.Resources.Max) {
+		addField("max", oldQueue.Resources.Max, newQueue.Resources.Max)
+	}
+	if oldQueue.MaxApplications != newQueue.MaxApplications {
+		addField("maxApplications", oldQueue.MaxApplications, newQueue.MaxApplications)
+	}
+	if oldQueue.SubmitACL != newQueue.SubmitACL {
+		addField("submitACL", oldQueue.SubmitACL, newQueue.SubmitACL)
+	}
+	if oldQueue.AdminACL != newQueue.AdminACL {
+		addField("adminACL", oldQueue.AdminACL, newQueue.AdminACL)
+	}
+	if !reflect.DeepEqual(oldQueue.Limits, newQueue.Limits) {
+		addField("limits", oldQueue.Limits, newQueue.Limits)
+	}
+	return fields
+}
+
+// getLimitViolations returns the live queues, users and groups that would violate the limits in the new queue
+// hierarchy of the partition.
+func getLimitViolations(queues []configs.QueueConfig, partition *PartitionContext) []*dao.ConfigViolationDAOInfo {
+	byPath := make(map[string]configs.QueueConfig)
+	paths := flattenQueueConfigs(queues, common.Empty, byPath, nil)
+
+	userManager := ugm.GetUserManager()
+	userUsage := make(map[string]*dao.ResourceUsageDAOInfo)
+	var userNames []string
+	for _, tracker := range userManager.GetUsersResources() {
+		info := tracker.GetUserResourceUsageDAOInfo()
+		userUsage[info.UserName] = info.Queues
+		userNames = append(userNames, info.UserName)
+	}
+	sort.Strings(userNames)
+	groupUsage := make(map[string]*dao.ResourceUsageDAOInfo)
+	for _, tracker := range userManager.GetGroupsResources() {
+		info := tracker.GetGroupResourceUsageDAOInfo()
+		groupUsage[info.GroupName] = info.Queues
+	}
+
+	var violations []*dao.ConfigViolationDAOInfo
+	for _, path := range paths {
+		conf := byPath[path]
+		if queue := partition.GetQueue(path); queue != nil {
+			violations = append(violations, getQueueViolations(conf, path, queue)...)
+		}
+		// users named in a limit are not covered by the wildcard limit on the same queue
+		named := make(map[string]bool)
+		for _, limit := range conf.Limits {
+			for _, user := range limit.Users {
+				named[user] = true
+			}
+		}
+		for _, limit := range conf.Limits {
+			maxResources, err := resources.NewResourceFromConf(limit.MaxResources)
+			if err != nil {
+				continue
+			}
+			for _, user := range limit.Users {
+				for _, name := range userNames {
+					if user == name || (user == common.Wildcard && !named[name]) {
+						for _, violation := range getTrackerViolations(findQueueUsage(userUsage[name], path), limit.MaxApplications, maxResources,
+							dao.ViolationUserMaxApplications, dao.ViolationUserMaxResources) {
+							violation.User = name
+							violations = append(violations, violation)
+						}
+					}
+				}
+			}
+			for _, group := range limit.Groups {
+				for _, violation := range getTrackerViolations(findQueueUsage(groupUsage[group], path), limit.MaxApplications, maxResources,
+					dao.ViolationGroupMaxApplications, dao.ViolationGroupMaxResources) {
+					violation.Group = group
+					violations = append(violations, violation)
+				}
+			}
+		}
+	}
+	return violations
+}
+
+// getQueueViolations checks the running applications and allocated resources of the live queue against the new
+// configuration of the queue.
+func getQueueViolations(conf configs.QueueConfig, path string, queue *objects.Queue) []*dao.ConfigViolationDAOInfo {
+	var violations []*dao.ConfigViolationDAOInfo
+	if conf.MaxApplications != 0 && queue.GetRunningApps() > conf.MaxApplications {
+		var running []string
+		for appID, app := range queue.GetCopyOfApps() {
+			if app.IsRunning() {
+				running = append(running, appID)
+			}
+		}
+		sort.Strings(running)
+		violations = append(violations, &dao.ConfigViolationDAOInfo{
+			Violation:           dao.ViolationQueueMaxApplications,
+			QueuePath:           path,
+			MaxApplications:     conf.MaxApplications,
+			RunningApplications: running,
+		})
+	}
+	if len(conf.Resources.Max) != 0 {
+		maxResources, err := resources.NewResourceFromConf(conf.Resources.Max)
+		if err == nil {
+			if notFit := maxResources.NotFitInMaxUndef(queue.GetAllocatedResource()); len(notFit) > 0 {
+				violations = append(violations, &dao.ConfigViolationDAOInfo{
+					Violation:     dao.ViolationQueueMaxResources,
+					QueuePath:     path,
+					ResourceTypes: notFit,
+				})
+			}
+		}
+	}
+	return violations
+}
+
+// getTrackerViolations checks the usage of a user or group in a queue against the limit.
+func getTrackerViolations(usage *dao.ResourceUsageDAOInfo, maxApps uint64, maxResources *resources.Resource, appsViolation, resourceViolation string) []*dao.ConfigViolationDAOInfo {
+	if usage == nil {
+		return nil
+	}
+	var violations []*dao.ConfigViolationDAOInfo
+	if maxApps != 0 && uint64(len(usage.RunningApplications)) > maxApps {
+		running := append([]string(nil), usage.RunningApplications...)
+		sort.Strings(running)
+		violations = append(violations, &dao.ConfigViolationDAOInfo{
+			Violation:           appsViolation,
+			QueuePath:           usage.QueuePath,
+			MaxApplications:     maxApps,
+			RunningApplications: running,
+		})
+	}
+	if !resources.IsZero(maxResources) {
+		if notFit := maxResources.NotFitInMaxUndef(usage.ResourceUsage); len(notFit) > 0 {
+			violations = append(violations, &dao.ConfigViolationDAOInfo{
+				Violation:     resourceViolation,
+				QueuePath:     usage.QueuePath,
+				ResourceTypes: notFit,
+			})
+		}
+	}
+	return violations
+}
+
+// findQueueUsage returns the usage tracked for the queue path in the usage tree, nil if the queue is not tracked.
+func findQueueUsage(usage *dao.ResourceUsageDAOInfo, path string) *dao.ResourceUsageDAOInfo {
+	if usage == nil {
+		return nil
+	}
+	if usage.QueuePath == path {
+		return usage
+	}
+	for _, child := range usage.Children {
+		if found := findQueueUsage(child, path); found != nil {
+			return found
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/G-Research/yunikorn-core/pkg/common/configs"
+	"github.com/G-Research/yunikorn-core/pkg/common/resources"
+	"github.com/G-Research/yunikorn-core/pkg/common/security"
+	"github.com/G-Research/yunikorn-core/pkg/webservice/dao"
+)
+
+func TestGetConfigImpact(t *testing.T) {
+	setupUGM()
+	const conf = `
+partitions:
+  - name: default
+    queues:
+      - name: root
+        submitacl: "*"
+        queues:
+          - name: a
+          - name: b
+          - name: c
+`
+	const newConf = `
+partitions:
+  - name: default
+    queues:
+      - name: root
+        submitacl: "*"
+        queues:
+          - name: a
+            maxapplications: 1
+            resources:
+              max:
+                memory: 15
+            limits:
+              - limit: user
+                users:
+                  - testuser
+                maxapplications: 1
+          - name: d
+  - name: other
+    queues:
+      - name: root
+`
+	context, err := NewClusterContext(rmID, "config-impact", []byte(conf))
+	assert.NilError(t, err, "context create should not have failed")
+	partition := context.GetPartition("[" + rmID + "]default")
+	assert.Assert(t, partition != nil, "partition not found")
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 10})
+	setupNode(t, nodeID1, partition, resources.Multiply(res, 10))
+	user := security.UserGroup{User: "testuser"}
+	for _, appID := range []string{appID1, appID2} {
+		err = partition.AddApplication(newApplicationWithUser(appID, partition.Name, "root.a", user))
+		assert.NilError(t, err, "failed to add app %s", appID)
+		_, allocCreated, err := partition.UpdateAllocation(newAllocation(appID, appID, nodeID1, res))
+		assert.NilError(t, err, "failed to add allocation for app %s", appID)
+		assert.Assert(t, allocCreated, "allocation should have been created")
+	}
+	err = partition.AddApplication(newApplicationWithUser(appID3, partition.Name, "root.b", user))
+	assert.NilError(t, err, "failed to add app")
+
+	parsed, err := configs.LoadSchedulerConfigFromByteArray([]byte(newConf))
+	assert.NilError(t, err, "new config should be valid")
+	impact := context.GetConfigImpact(parsed)
+	assert.Equal(t, len(impact), 2, "expected impact on two partitions")
+	assert.Equal(t, impact[1].Partition, "other")
+	assert.Equal(t, impact[1].Change, dao.ConfigChangeAdded)
+
+	defaultImpact := impact[0]
+	assert.Equal(t, defaultImpact.Change, dao.ConfigChangeUpdated)
+	changes := make(map[string]*dao.QueueConfigChangeDAOInfo)
+	for _, change := range defaultImpact.Queues {
+		changes[change.QueuePath] = change
+	}
+	assert.Equal(t, len(changes), 4, "unexpected queue changes: %v", defaultImpact.Queues)
+	assert.Equal(t, changes["root.a"].Change, dao.ConfigChangeUpdated)
+	var fields []string
+	for _, field := range changes["root.a"].Fields {
+		fields = append(fields, field.Field)
+	}
+	assert.DeepEqual(t, fields, []string{"max", "maxApplications", "limits"})
+	assert.Equal(t, changes["root.d"].Change, dao.ConfigChangeAdded)
+	assert.Equal(t, changes["root.b"].Change, dao.ConfigChangeDrained)
+	assert.Equal(t, changes["root.c"].Change, dao.ConfigChangeRemoved)
+
+	assert.DeepEqual(t, defaultImpact.Violations, []*dao.ConfigViolationDAOInfo{
+		{Violation: dao.ViolationQueueMaxApplications, QueuePath: "root.a", MaxApplications: 1, RunningApplications: []string{appID1, appID2}},
+		{Violation: dao.ViolationQueueMaxResources, QueuePath: "root.a", ResourceTypes: []string{"memory"}},
+		{Violation: dao.ViolationUserMaxApplications, QueuePath: "root.a", User: "testuser", MaxApplications: 1, RunningApplications: []string{appID1, appID2}},
+	})
+
+	// the active config is not changed
+	assert.Assert(t, partition.GetQueue("root.d") == nil, "queue should not have been added")
+	assert.Assert(t, partition.GetQueue("root.c") != nil, "queue should not have been removed")
+}
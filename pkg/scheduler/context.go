@@ -42,6 +42,9 @@ import (
 
 const disableReservation = "DISABLE_RESERVATION"
 
+// ErrConfigChecksumMismatch returned when the base checksum of a config update does not match the active config
+var ErrConfigChecksumMismatch = errors.New("configuration checksum does not match the active configuration")
+
 type ClusterContext struct {
 	partitions     map[string]*PartitionContext
 	policyGroup    string
@@ -55,9 +58,14 @@ type ClusterContext struct {
 	rmInfo    map[string]*RMInformation
 	startTime time.Time
 
+	drains    map[string]*nodeDrain // drains waiting for the grace period, keyed on node ID
+	drainLock locking.Mutex
+
 	locking.RWMutex
 
 	lastHealthCheckResult *dao.SchedulerHealthDAOInfo
+	lastDroppedEvents     int // dropped event count seen by the previous health check
+	lastDroppedRMEvents   int // dropped RM event count seen by the previous health check
 }
 
 type RMInformation struct {
@@ -333,6 +341,25 @@ func (cc *ClusterContext) removePartitionsByRMID(event *rmevent.RMPartitionsRemo
 func (cc *ClusterContext) UpdateRMSchedulerConfig(rmID string, config []byte) error {
 	cc.Lock()
 	defer cc.Unlock()
+	return cc.updateRMSchedulerConfig(rmID, config)
+}
+
+// CheckAndUpdateRMSchedulerConfig is the locked version of the configuration update used by the REST API.
+// The update is only applied if the checksum of the active configuration still matches the base checksum the
+// caller started from. The check and update are performed while holding the context lock, concurrent updates
+// cannot overwrite each other.
+func (cc *ClusterContext) CheckAndUpdateRMSchedulerConfig(rmID, baseChecksum string, config []byte) error {
+	cc.Lock()
+	defer cc.Unlock()
+	current := configs.ConfigContext.Get(cc.policyGroup)
+	if current == nil || current.Checksum != baseChecksum {
+		return ErrConfigChecksumMismatch
+	}
+	return cc.updateRMSchedulerConfig(rmID, config)
+}
+
+// unlocked call must only be called holding the ClusterContext lock
+func (cc *ClusterContext) updateRMSchedulerConfig(rmID string, config []byte) error {
 	if len(cc.partitions) == 0 {
 		return fmt.Errorf("RM %s has no active partitions, make sure it is registered", rmID)
 	}
@@ -482,6 +509,36 @@ func (cc *ClusterContext) GetQueue(queueName string, partitionName string) *obje
 	return nil
 }
 
+// GetObjectByID returns the partition, queue, application or node with the given ID.
+// The partition the object belongs to is returned with the object. Both are nil if no object has the ID.
+func (cc *ClusterContext) GetObjectByID(id string) (*PartitionContext, interface{}) {
+	for _, partition := range cc.GetPartitionMapClone() {
+		if partition.ID == id {
+			return partition, partition
+		}
+		if queue := partition.GetQueueByID(id); queue != nil {
+			return partition, queue
+		}
+		if app := partition.GetApplicationByID(id); app != nil {
+			return partition, app
+		}
+		if node := partition.GetNodeByID(id); node != nil {
+			return partition, node
+		}
+	}
+	return nil, nil
+}
+
+// IsEventInPartition returns true if the object the event record refers to belongs to the partition.
+// The partition name is the name without the cluster ID. Used as the events.PartitionMatcher for event filtering.
+func (cc *ClusterContext) IsEventInPartition(event *si.EventRecord, partitionName string) bool {
+	partition := cc.GetPartitionWithoutClusterID(partitionName)
+	if partition == nil {
+		return false
+	}
+	return partition.hasEventObject(event)
+}
+
 // Process the application update. Add and remove applications from the partitions.
 // Lock free call, all updates occur on the underlying partition which is locked, or via events.
 func (cc *ClusterContext) handleRMUpdateApplicationEvent(event *rmevent.RMUpdateApplicationEvent) {
@@ -670,16 +727,14 @@ func (cc *ClusterContext) updateNode(nodeInfo *si.NodeInfo) {
 			node.SetOccupiedResource(resources.NewResourceFromProto(or))
 		}
 	case si.NodeInfo_DRAIN_NODE:
-		if node.IsSchedulable() {
-			// set the state to not schedulable
-			node.SetSchedulable(false)
+		// set the state to not schedulable, a node cordoned by an operator is already counted
+		if node.SetSchedulable(false) {
 			metrics.GetSchedulerMetrics().IncDrainingNodes()
 		}
 	case si.NodeInfo_DRAIN_TO_SCHEDULABLE:
-		if !node.IsSchedulable() {
+		// set the state to schedulable, a node cordoned by an operator stays not schedulable
+		if node.SetSchedulable(true) {
 			metrics.GetSchedulerMetrics().DecDrainingNodes()
-			// set the state to schedulable
-			node.SetSchedulable(true)
 		}
 	case si.NodeInfo_DECOMISSION:
 		if !node.IsSchedulable() {
@@ -703,7 +758,9 @@ func (cc *ClusterContext) updateNode(nodeInfo *si.NodeInfo) {
 			zap.String("nodeID", nodeInfo.NodeID),
 			zap.String("partitionName", nodeInfo.Attributes[siCommon.NodePartition]),
 			zap.Stringer("nodeAction", nodeInfo.Action))
+		return
 	}
+	partition.markChanged()
 }
 
 // Process an ask and allocation update request.
@@ -887,6 +944,15 @@ func (cc *ClusterContext) SetLastHealthCheckResult(c *dao.SchedulerHealthDAOInfo
 	cc.lastHealthCheckResult = c
 }
 
+// swapDroppedEvents stores the dropped event counts for the next health check and returns the previous counts.
+func (cc *ClusterContext) swapDroppedEvents(events, rmEvents int) (int, int) {
+	cc.Lock()
+	defer cc.Unlock()
+	prevEvents, prevRMEvents := cc.lastDroppedEvents, cc.lastDroppedRMEvents
+	cc.lastDroppedEvents, cc.lastDroppedRMEvents = events, rmEvents
+	return prevEvents, prevRMEvents
+}
+
 func (cc *ClusterContext) GetUUID() string {
 	return cc.uuid
 }
@@ -28,6 +28,7 @@ import (
 
 	"github.com/G-Research/yunikorn-core/pkg/common/configs"
 	"github.com/G-Research/yunikorn-core/pkg/common/resources"
+	"github.com/G-Research/yunikorn-core/pkg/locking"
 	"github.com/G-Research/yunikorn-core/pkg/metrics"
 	"github.com/G-Research/yunikorn-core/pkg/rmproxy/rmevent"
 	siCommon "github.com/G-Research/yunikorn-scheduler-interface/lib/go/common"
@@ -41,6 +42,9 @@ type mockEventHandler struct {
 	rejectedNodes   []*si.RejectedNode
 	acceptedNodes   []*si.AcceptedNode
 	newAllocHandler func(*rmevent.RMNewAllocationsEvent)
+	released        []*si.AllocationRelease
+
+	locking.Mutex
 }
 
 func newMockEventHandler() *mockEventHandler {
@@ -61,6 +65,21 @@ func (m *mockEventHandler) HandleEvent(ev interface{}) {
 	if allocEvent, ok := ev.(*rmevent.RMNewAllocationsEvent); ok && m.newAllocHandler != nil {
 		m.newAllocHandler(allocEvent)
 	}
+
+	if releaseEvent, ok := ev.(*rmevent.RMReleaseAllocationEvent); ok {
+		m.Lock()
+		m.released = append(m.released, releaseEvent.ReleasedAllocations...)
+		m.Unlock()
+		go func() {
+			releaseEvent.Channel <- &rmevent.Result{Succeeded: true}
+		}()
+	}
+}
+
+func (m *mockEventHandler) getReleased() []*si.AllocationRelease {
+	m.Lock()
+	defer m.Unlock()
+	return m.released
 }
 
 func createTestContext(t *testing.T, partitionName string) *ClusterContext {
@@ -437,3 +456,68 @@ outer:
 
 	assert.Assert(t, checked, "Failed to find metric")
 }
+
+func TestContext_IsEventInPartition(t *testing.T) {
+	context := createTestContext(t, pName)
+	partition := context.GetPartition(pName)
+	assert.Assert(t, partition != nil, "partition should have been found")
+	err := partition.AddApplication(newApplication(appID1, pName, "root.default"))
+	assert.NilError(t, err, "application should have been added")
+	err = partition.AddNode(newNodeMaxResource(nodeID1, resources.NewResourceFromMap(map[string]resources.Quantity{"first": 10})))
+	assert.NilError(t, err, "node should have been added")
+
+	tests := map[string]struct {
+		event     *si.EventRecord
+		partition string
+		expected  bool
+	}{
+		"app":               {&si.EventRecord{Type: si.EventRecord_APP, ObjectID: appID1}, pName, true},
+		"unknown app":       {&si.EventRecord{Type: si.EventRecord_APP, ObjectID: "unknown"}, pName, false},
+		"request":           {&si.EventRecord{Type: si.EventRecord_REQUEST, ObjectID: "alloc-1", ReferenceID: appID1}, pName, true},
+		"node":              {&si.EventRecord{Type: si.EventRecord_NODE, ObjectID: nodeID1}, pName, true},
+		"queue":             {&si.EventRecord{Type: si.EventRecord_QUEUE, ObjectID: "root.default"}, pName, true},
+		"unknown queue":     {&si.EventRecord{Type: si.EventRecord_QUEUE, ObjectID: "root.unknown"}, pName, false},
+		"user group":        {&si.EventRecord{Type: si.EventRecord_USERGROUP, ObjectID: "testuser"}, pName, false},
+		"unknown partition": {&si.EventRecord{Type: si.EventRecord_APP, ObjectID: appID1}, "unknown", false},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, context.IsEventInPartition(tc.event, tc.partition))
+		})
+	}
+}
+
+func TestContext_CheckAndUpdateRMSchedulerConfig(t *testing.T) {
+	const conf = `
+partitions:
+  - name: default
+    queues:
+      - name: root
+        submitacl: "*"
+`
+	const newConf = `
+partitions:
+  - name: default
+    queues:
+      - name: root
+        submitacl: "*"
+        queues:
+          - name: added
+`
+	context, err := NewClusterContext("rm", "check-and-update", []byte(conf))
+	assert.NilError(t, err, "context create should not have failed")
+	base := configs.ConfigContext.Get("check-and-update").Checksum
+
+	err = context.CheckAndUpdateRMSchedulerConfig("rm", "unknown", []byte(newConf))
+	assert.ErrorIs(t, err, ErrConfigChecksumMismatch)
+	assert.Assert(t, context.GetQueue("root.added", "[rm]default") == nil, "queue should not have been added")
+
+	err = context.CheckAndUpdateRMSchedulerConfig("rm", base, []byte(newConf))
+	assert.NilError(t, err, "update with matching checksum should not have failed")
+	assert.Assert(t, context.GetQueue("root.added", "[rm]default") != nil, "queue should have been added")
+	assert.Assert(t, base != configs.ConfigContext.Get("check-and-update").Checksum, "checksum should have changed")
+
+	// the old base checksum is stale now
+	err = context.CheckAndUpdateRMSchedulerConfig("rm", base, []byte(conf))
+	assert.ErrorIs(t, err, ErrConfigChecksumMismatch)
+}
@@ -24,6 +24,7 @@ import (
 
 	"go.uber.org/zap"
 
+	"github.com/G-Research/yunikorn-core/pkg/common"
 	"github.com/G-Research/yunikorn-core/pkg/common/configs"
 	"github.com/G-Research/yunikorn-core/pkg/common/resources"
 	"github.com/G-Research/yunikorn-core/pkg/locking"
@@ -170,17 +171,20 @@ func (c *HealthChecker) runOnce() {
 	schedulerMetrics := metrics.GetSchedulerMetrics()
 	result := GetSchedulerHealthStatus(schedulerMetrics, c.context)
 	updateSchedulerLastHealthStatus(&result, c.context)
-	if !result.Healthy {
-		for _, v := range result.HealthChecks {
-			if v.Succeeded {
-				continue
-			}
-			log.Log(log.SchedHealth).Warn("Scheduler is not healthy",
-				zap.String("name", v.Name),
-				zap.String("description", v.Description),
-				zap.String("message", v.DiagnosisMessage))
+	for _, v := range result.HealthChecks {
+		if v.Succeeded {
+			continue
 		}
-	} else {
+		message := "Scheduler is not healthy"
+		if v.Warning {
+			message = "Scheduler health check warning"
+		}
+		log.Log(log.SchedHealth).Warn(message,
+			zap.String("name", v.Name),
+			zap.String("description", v.Description),
+			zap.String("message", v.DiagnosisMessage))
+	}
+	if result.Healthy {
 		log.Log(log.SchedHealth).Debug("Scheduler is healthy")
 	}
 }
@@ -194,9 +198,10 @@ func GetSchedulerHealthStatus(metrics *metrics.SchedulerMetrics, schedulerContex
 	healthInfo = append(healthInfo, checkSchedulingErrors(metrics))
 	healthInfo = append(healthInfo, checkFailedNodes(metrics))
 	healthInfo = append(healthInfo, checkSchedulingContext(schedulerContext)...)
+	healthInfo = append(healthInfo, checkDroppedEvents(metrics, schedulerContext))
 	healthy := true
 	for _, h := range healthInfo {
-		if !h.Succeeded {
+		if !h.Succeeded && !h.Warning {
 			healthy = false
 			break
 		}
@@ -232,6 +237,34 @@ func checkFailedNodes(metrics *metrics.SchedulerMetrics) dao.HealthCheckInfo {
 	return CreateCheckInfo(failedNodes == 0, "Failed nodes", "Check for failed nodes entries in metrics", diagnosisMsg)
 }
 
+// checkDroppedEvents warns when more events and RM events were dropped since the previous health check than the
+// configured threshold. Dropped events do not make the scheduler unhealthy.
+func checkDroppedEvents(schedulerMetrics *metrics.SchedulerMetrics, schedulerContext *ClusterContext) dao.HealthCheckInfo {
+	const name = "Dropped events"
+	const description = "Check for events and RM events dropped since the last health check because of full channels, stores or slow consumers"
+	threshold := common.GetConfigurationUint(configs.GetConfigMap(), configs.HealthEventDropThreshold, configs.DefaultHealthEventDropThreshold)
+	events, err := metrics.GetEventMetrics().GetTotalEventsDropped()
+	if err != nil {
+		return CreateCheckInfo(false, name, description, err.Error())
+	}
+	rmEvents, err := schedulerMetrics.GetTotalRMEventsDropped()
+	if err != nil {
+		return CreateCheckInfo(false, name, description, err.Error())
+	}
+	prevEvents, prevRMEvents := schedulerContext.swapDroppedEvents(events, rmEvents)
+	// counters only go down when the metrics are reset, count from zero in that case
+	if events >= prevEvents {
+		events -= prevEvents
+	}
+	if rmEvents >= prevRMEvents {
+		rmEvents -= prevRMEvents
+	}
+	diagnosisMsg := fmt.Sprintf("There were %v events and %v RM events dropped since the last check, the warning threshold is %v", events, rmEvents, threshold)
+	info := CreateCheckInfo(uint64(events+rmEvents) <= threshold, name, description, diagnosisMsg)
+	info.Warning = true
+	return info
+}
+
 func checkSchedulingContext(schedulerContext *ClusterContext) []dao.HealthCheckInfo {
 	// check for negative resources
 	var partitionsWithNegResources []string
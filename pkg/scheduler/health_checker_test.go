@@ -260,3 +260,45 @@ func TestGetSchedulerHealthStatusMetrics(t *testing.T) {
 	healthInfo = GetSchedulerHealthStatus(schedulerMetrics, schedulerContext)
 	assert.Assert(t, !healthInfo.Healthy, "Scheduler should not be healthy")
 }
+
+func TestCheckDroppedEvents(t *testing.T) {
+	original := configs.GetConfigMap()
+	defer configs.SetConfigMap(original)
+	configs.SetConfigMap(map[string]string{configs.HealthEventDropThreshold: "2"})
+	metrics.Reset()
+	schedulerMetrics := metrics.GetSchedulerMetrics()
+	schedulerContext, err := NewClusterContext("rmID", "policyGroup", []byte(configDefault))
+	assert.NilError(t, err, "Error when load schedulerContext from config")
+
+	info := checkDroppedEvents(schedulerMetrics, schedulerContext)
+	assert.Assert(t, info.Succeeded, "no dropped events should pass")
+	assert.Assert(t, info.Warning)
+
+	metrics.GetEventMetrics().IncEventsDropped("app", metrics.EventDropChannel)
+	schedulerMetrics.IncRMEventsDropped(metrics.RMEventQueueScheduler)
+	assert.Assert(t, checkDroppedEvents(schedulerMetrics, schedulerContext).Succeeded, "dropped events at the threshold should pass")
+
+	// over the threshold since the last check: the check fails but the scheduler stays healthy
+	metrics.GetEventMetrics().IncEventsDropped("node", metrics.EventDropStream)
+	metrics.GetEventMetrics().IncEventsDropped("node", metrics.EventDropStream)
+	schedulerMetrics.IncRMEventsDropped(metrics.RMEventQueueRMProxy)
+	healthInfo := GetSchedulerHealthStatus(schedulerMetrics, schedulerContext)
+	assert.Assert(t, healthInfo.Healthy, "dropped events should not make the scheduler unhealthy")
+	info = healthInfo.HealthChecks[len(healthInfo.HealthChecks)-1]
+	assert.Equal(t, "Dropped events", info.Name)
+	assert.Assert(t, !info.Succeeded, "dropped events over the threshold should fail")
+	assert.Assert(t, info.Warning)
+	assert.Equal(t, "There were 2 events and 1 RM events dropped since the last check, the warning threshold is 2", info.DiagnosisMessage)
+
+	// the warning clears when no more events are dropped
+	info = checkDroppedEvents(schedulerMetrics, schedulerContext)
+	assert.Assert(t, info.Succeeded, "no new dropped events should pass")
+	assert.Equal(t, "There were 0 events and 0 RM events dropped since the last check, the warning threshold is 2", info.DiagnosisMessage)
+
+	// a metrics reset counts from zero
+	metrics.Reset()
+	metrics.GetEventMetrics().IncEventsDropped("app", metrics.EventDropStore)
+	info = checkDroppedEvents(schedulerMetrics, schedulerContext)
+	assert.Equal(t, "There were 1 events and 0 RM events dropped since the last check, the warning threshold is 2", info.DiagnosisMessage)
+	metrics.Reset()
+}
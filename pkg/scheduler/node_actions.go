@@ -0,0 +1,116 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/G-Research/yunikorn-core/pkg/log"
+	"github.com/G-Research/yunikorn-core/pkg/metrics"
+	"github.com/G-Research/yunikorn-core/pkg/scheduler/objects"
+	"github.com/G-Research/yunikorn-scheduler-interface/lib/go/si"
+)
+
+// nodeDrain a drain waiting for the grace period to expire
+type nodeDrain struct {
+	timer *time.Timer
+}
+
+// CordonNode marks the node not schedulable on request of an operator: no new allocations are placed on the node,
+// the existing allocations are not affected.
+func (cc *ClusterContext) CordonNode(partition *PartitionContext, node *objects.Node, user, reason string) {
+	if node.SetCordoned(true, user, reason) {
+		metrics.GetSchedulerMetrics().IncDrainingNodes()
+	}
+	partition.markChanged()
+	log.Log(log.SchedContext).Info("node cordoned",
+		zap.String("nodeID", node.NodeID),
+		zap.String("partition", partition.Name),
+		zap.String("user", user),
+		zap.String("reason", reason))
+}
+
+// UncordonNode marks the node schedulable on request of an operator and cancels a drain of the node that is
+// waiting for the grace period.
+func (cc *ClusterContext) UncordonNode(partition *PartitionContext, node *objects.Node, user, reason string) {
+	cc.drainLock.Lock()
+	if drain, ok := cc.drains[node.ID]; ok {
+		drain.timer.Stop()
+		delete(cc.drains, node.ID)
+	}
+	cc.drainLock.Unlock()
+	if node.SetCordoned(false, user, reason) {
+		metrics.GetSchedulerMetrics().DecDrainingNodes()
+	}
+	partition.markChanged()
+	log.Log(log.SchedContext).Info("node uncordoned",
+		zap.String("nodeID", node.NodeID),
+		zap.String("partition", partition.Name),
+		zap.String("user", user),
+		zap.String("reason", reason))
+}
+
+// DrainNode cordons the node and asks the RM to release all allocations on the node after the grace period.
+// A new drain of the same node replaces the pending drain, uncordoning the node cancels it.
+func (cc *ClusterContext) DrainNode(partition *PartitionContext, node *objects.Node, user, reason string, gracePeriod time.Duration) {
+	cc.CordonNode(partition, node, user, reason)
+	node.SendNodeDrainRequestedEvent(user, reason, gracePeriod)
+	cc.drainLock.Lock()
+	defer cc.drainLock.Unlock()
+	if cc.drains == nil {
+		cc.drains = make(map[string]*nodeDrain)
+	}
+	if previous, ok := cc.drains[node.ID]; ok {
+		previous.timer.Stop()
+	}
+	drain := &nodeDrain{}
+	cc.drains[node.ID] = drain
+	// the lock is held until the timer is set: the callback cannot run before
+	drain.timer = time.AfterFunc(gracePeriod, func() {
+		cc.releaseDrainedNode(partition, node, drain, user, reason)
+	})
+}
+
+// releaseDrainedNode asks the RM to release the allocations on the node once the grace period of the drain has
+// expired. Nothing is released if the drain was replaced or cancelled, the node was removed or is schedulable again.
+func (cc *ClusterContext) releaseDrainedNode(partition *PartitionContext, node *objects.Node, drain *nodeDrain, user, reason string) {
+	cc.drainLock.Lock()
+	current := cc.drains[node.ID] == drain
+	if current {
+		delete(cc.drains, node.ID)
+	}
+	cc.drainLock.Unlock()
+	if !current || partition.GetNode(node.NodeID) != node || node.IsSchedulable() {
+		return
+	}
+	released := node.GetYunikornAllocations()
+	node.SendNodeDrainedEvent(user, reason, len(released))
+	log.Log(log.SchedContext).Info("node drained, releasing allocations",
+		zap.String("nodeID", node.NodeID),
+		zap.String("partition", partition.Name),
+		zap.String("user", user),
+		zap.Int("allocations", len(released)))
+	if len(released) != 0 {
+		cc.notifyRMAllocationReleased(partition.RmID, partition.Name, released, si.TerminationType_PREEMPTED_BY_SCHEDULER,
+			fmt.Sprintf("Node %s drained by %s", node.NodeID, user))
+	}
+}
@@ -0,0 +1,137 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/G-Research/yunikorn-core/pkg/common"
+	"github.com/G-Research/yunikorn-core/pkg/common/resources"
+	"github.com/G-Research/yunikorn-core/pkg/metrics"
+	"github.com/G-Research/yunikorn-core/pkg/scheduler/objects"
+	siCommon "github.com/G-Research/yunikorn-scheduler-interface/lib/go/common"
+	"github.com/G-Research/yunikorn-scheduler-interface/lib/go/si"
+)
+
+func addTestNode(t *testing.T, context *ClusterContext, nodeID string) (*PartitionContext, *objects.Node) {
+	err := context.addNode(&si.NodeInfo{
+		NodeID:              nodeID,
+		Attributes:          map[string]string{siCommon.NodePartition: pName},
+		SchedulableResource: &si.Resource{Resources: map[string]*si.Quantity{"first": {Value: 10}}},
+	}, true)
+	assert.NilError(t, err, "node should have been added")
+	partition := context.GetPartition(pName)
+	return partition, partition.GetNode(nodeID)
+}
+
+func TestCordonNode(t *testing.T) {
+	context := createTestContext(t, pName)
+	partition, node := addTestNode(t, context, "node-1")
+	generation := partition.GetGeneration()
+
+	context.CordonNode(partition, node, "admin", "maintenance")
+	assert.Assert(t, !node.IsSchedulable(), "node should have been cordoned")
+	user, reason := node.GetCordonReason()
+	assert.Equal(t, "admin", user)
+	assert.Equal(t, "maintenance", reason)
+	assert.Assert(t, partition.GetGeneration() > generation, "partition should have changed")
+
+	context.UncordonNode(partition, node, "admin", "done")
+	assert.Assert(t, node.IsSchedulable(), "node should have been uncordoned")
+	user, reason = node.GetCordonReason()
+	assert.Equal(t, "", user+reason, "reason should have been cleared")
+}
+
+func TestCordonNodeRMUpdate(t *testing.T) {
+	context := createTestContext(t, pName)
+	partition, node := addTestNode(t, context, "node-1")
+	updateNode := func(action si.NodeInfo_ActionFromRM) {
+		context.updateNode(&si.NodeInfo{
+			NodeID:     "node-1",
+			Action:     action,
+			Attributes: map[string]string{siCommon.NodePartition: pName},
+		})
+	}
+	draining, err := metrics.GetSchedulerMetrics().GetDrainingNodes()
+	assert.NilError(t, err)
+
+	// the RM making the node schedulable does not clear the cordon
+	context.CordonNode(partition, node, "admin", "maintenance")
+	updateNode(si.NodeInfo_DRAIN_NODE)
+	updateNode(si.NodeInfo_DRAIN_TO_SCHEDULABLE)
+	assert.Assert(t, !node.IsSchedulable(), "cordoned node should not be schedulable")
+	user, reason := node.GetCordonReason()
+	assert.Equal(t, "admin", user)
+	assert.Equal(t, "maintenance", reason)
+	assertDrainingNodes(t, draining+1)
+
+	// uncordon does not make a node drained by the RM schedulable
+	updateNode(si.NodeInfo_DRAIN_NODE)
+	context.UncordonNode(partition, node, "admin", "done")
+	assert.Assert(t, !node.IsSchedulable(), "node drained by the RM should not be schedulable")
+	assertDrainingNodes(t, draining+1)
+	updateNode(si.NodeInfo_DRAIN_TO_SCHEDULABLE)
+	assert.Assert(t, node.IsSchedulable(), "node should be schedulable")
+	assertDrainingNodes(t, draining)
+}
+
+func assertDrainingNodes(t *testing.T, expected int) {
+	t.Helper()
+	draining, err := metrics.GetSchedulerMetrics().GetDrainingNodes()
+	assert.NilError(t, err)
+	assert.Equal(t, expected, draining, "unexpected number of draining nodes")
+}
+
+func TestDrainNode(t *testing.T) {
+	context := createTestContext(t, pName)
+	handler := context.rmEventHandler.(*mockEventHandler) //nolint:errcheck
+	partition, node := addTestNode(t, context, "node-1")
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1})
+	node.AddAllocation(newAllocation("alloc-1", "app-1", "node-1", res))
+	node.AddAllocation(newForeignAllocation("foreign-1", "node-1"))
+
+	// uncordon cancels the drain
+	context.DrainNode(partition, node, "admin", "maintenance", time.Hour)
+	assert.Assert(t, !node.IsSchedulable(), "draining node should not be schedulable")
+	assert.Equal(t, 1, len(context.drains), "drain should be pending")
+	context.UncordonNode(partition, node, "admin", "cancelled")
+	assert.Equal(t, 0, len(context.drains), "drain should have been cancelled")
+
+	// a new drain replaces the pending drain, only YuniKorn allocations are released
+	context.DrainNode(partition, node, "admin", "maintenance", time.Hour)
+	context.DrainNode(partition, node, "admin", "maintenance", 0)
+	err := common.WaitForCondition(time.Millisecond, time.Second, func() bool {
+		return len(handler.getReleased()) != 0
+	})
+	assert.NilError(t, err, "allocations were not released")
+	released := handler.getReleased()
+	assert.Equal(t, 1, len(released), "only the allocation should have been released")
+	assert.Equal(t, "alloc-1", released[0].AllocationKey)
+	assert.Equal(t, si.TerminationType_PREEMPTED_BY_SCHEDULER, released[0].TerminationType)
+	assert.Equal(t, 0, len(context.drains), "drain should have been removed")
+
+	// nothing is released for a node that is schedulable again
+	context.DrainNode(partition, node, "admin", "maintenance", 20*time.Millisecond)
+	node.SetCordoned(false, "admin", "done")
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 1, len(handler.getReleased()), "nothing should have been released")
+}
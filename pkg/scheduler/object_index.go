@@ -0,0 +1,106 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"github.com/G-Research/yunikorn-core/pkg/locking"
+	"github.com/G-Research/yunikorn-core/pkg/scheduler/objects"
+)
+
+// objectIndex tracks the queues, applications and nodes of a partition by their ULID.
+// The index is internally synchronised and has its own lock: it never calls out to the indexed objects
+// and can be updated with or without holding the partition lock.
+type objectIndex struct {
+	queues       map[string]*objects.Queue
+	applications map[string]*objects.Application
+	nodes        map[string]*objects.Node
+
+	locking.RWMutex
+}
+
+func newObjectIndex() *objectIndex {
+	return &objectIndex{
+		queues:       make(map[string]*objects.Queue),
+		applications: make(map[string]*objects.Application),
+		nodes:        make(map[string]*objects.Node),
+	}
+}
+
+func (oi *objectIndex) addQueue(queue *objects.Queue) {
+	if queue == nil || queue.ID == "" {
+		return
+	}
+	oi.Lock()
+	defer oi.Unlock()
+	oi.queues[queue.ID] = queue
+}
+
+func (oi *objectIndex) removeQueue(id string) {
+	oi.Lock()
+	defer oi.Unlock()
+	delete(oi.queues, id)
+}
+
+func (oi *objectIndex) getQueue(id string) *objects.Queue {
+	oi.RLock()
+	defer oi.RUnlock()
+	return oi.queues[id]
+}
+
+func (oi *objectIndex) addApplication(app *objects.Application) {
+	if app == nil || app.ID == "" {
+		return
+	}
+	oi.Lock()
+	defer oi.Unlock()
+	oi.applications[app.ID] = app
+}
+
+func (oi *objectIndex) removeApplication(id string) {
+	oi.Lock()
+	defer oi.Unlock()
+	delete(oi.applications, id)
+}
+
+func (oi *objectIndex) getApplication(id string) *objects.Application {
+	oi.RLock()
+	defer oi.RUnlock()
+	return oi.applications[id]
+}
+
+func (oi *objectIndex) addNode(node *objects.Node) {
+	if node == nil || node.ID == "" {
+		return
+	}
+	oi.Lock()
+	defer oi.Unlock()
+	oi.nodes[node.ID] = node
+}
+
+func (oi *objectIndex) removeNode(id string) {
+	oi.Lock()
+	defer oi.Unlock()
+	delete(oi.nodes, id)
+}
+
+func (oi *objectIndex) getNode(id string) *objects.Node {
+	oi.RLock()
+	defer oi.RUnlock()
+	return oi.nodes[id]
+}
@@ -123,7 +123,8 @@ type Application struct {
 	rmID                  string
 	terminatedCallback    func(appID string)
 	appEvents             *schedEvt.ApplicationEvents
-	sendStateChangeEvents bool // whether to send state-change events or not (simplifies testing)
+	sendStateChangeEvents bool     // whether to send state-change events or not (simplifies testing)
+	webhookURLs           []string // lifecycle webhooks of an application rejected before it is added to a queue
 
 	snapshotLock locking.Mutex
 	snapshot     bytes.Buffer
@@ -134,6 +135,12 @@ type Application struct {
 func (sa *Application) GetApplicationSummary(rmID string) *ApplicationSummary {
 	sa.RLock()
 	defer sa.RUnlock()
+	return sa.getApplicationSummary(rmID)
+}
+
+// getApplicationSummary returns the summary of the application.
+// Lock free call, must be called holding the application lock.
+func (sa *Application) getApplicationSummary(rmID string) *ApplicationSummary {
 	state := sa.stateMachine.Current()
 	resourceUsage := sa.usedResource.Clone()
 	preemptedUsage := sa.preemptedResource.Clone()
@@ -309,6 +316,7 @@ func (sa *Application) HandleApplicationEventWithInfo(event applicationEvent, ev
 // The only state that does not generate an event is Rejected.
 func (sa *Application) OnStateChange(event *fsm.Event, eventInfo string) {
 	sa.recordState(event.Dst)
+	sa.notifyWebhooks(event, eventInfo)
 	if event.Dst == Rejected.String() || sa.rmEventHandler == nil {
 		return
 	}
@@ -1659,6 +1667,76 @@ func (sa *Application) UnSetQueue() {
 	sa.finishedTime = time.Now()
 }
 
+// MoveToQueue moves the application to the target leaf queue. The allocated and pending resources are moved between
+// the queues and the user and group usage trackers. The move fails if the allocations do not fit the maximum resources,
+// or a running application does not fit the maximum running applications, of the target queue hierarchy or of the
+// user and group of the application in the target queue hierarchy.
+func (sa *Application) MoveToQueue(target *Queue) error {
+	sa.Lock()
+	defer sa.Unlock()
+	source := sa.queue
+	switch {
+	case source == nil:
+		return fmt.Errorf("application %s is not assigned to a queue", sa.ApplicationID)
+	case source == target:
+		return fmt.Errorf("application %s is already in queue %s", sa.ApplicationID, target.QueuePath)
+	case !target.IsLeafQueue() || common.IsRecoveryQueue(target.QueuePath):
+		return fmt.Errorf("queue %s is not a leaf queue that can run applications", target.QueuePath)
+	case !target.IsRunning():
+		return fmt.Errorf("queue %s is not running", target.QueuePath)
+	}
+	state := sa.stateMachine.Current()
+	switch state {
+	case New.String(), Accepted.String(), Running.String(), Resuming.String(), Completing.String():
+	default:
+		return fmt.Errorf("application %s cannot be moved in state %s", sa.ApplicationID, state)
+	}
+	if (sa.hasPlaceholderAlloc || !resources.IsZero(sa.placeholderAsk)) && !target.SupportTaskGroup() {
+		return fmt.Errorf("queue %s cannot run application %s with task group request: unsupported sort type", target.QueuePath, sa.ApplicationID)
+	}
+	usage := resources.Add(sa.allocatedResource, sa.allocatedPlaceholder)
+	if err := ugm.GetUserManager().MoveTrackedResource(source.QueuePath, target.QueuePath, sa.ApplicationID, usage, sa.user); err != nil {
+		return err
+	}
+	if err := source.moveApplication(sa, target); err != nil {
+		// undo the tracker move: the usage was tracked in the source queue before the move
+		ugm.GetUserManager().DecreaseTrackedResource(target.QueuePath, sa.ApplicationID, usage, sa.user, true)
+		ugm.GetUserManager().IncreaseTrackedResource(source.QueuePath, sa.ApplicationID, usage, sa.user)
+		return err
+	}
+	moveStateMetrics(state, source.QueuePath, target.QueuePath)
+	sa.queuePath = target.QueuePath
+	sa.queue = target
+	log.Log(log.SchedApplication).Info("Application moved to queue",
+		zap.String("appID", sa.ApplicationID),
+		zap.String("source", source.QueuePath),
+		zap.String("target", target.QueuePath))
+	return nil
+}
+
+// moveStateMetrics moves the application from the state gauge of the source queue to the target queue.
+func moveStateMetrics(state string, source, target string) {
+	sourceMetrics := metrics.GetQueueMetrics(source)
+	targetMetrics := metrics.GetQueueMetrics(target)
+	switch state {
+	case New.String():
+		sourceMetrics.DecQueueApplicationsNew()
+		targetMetrics.IncQueueApplicationsNew()
+	case Accepted.String():
+		sourceMetrics.DecQueueApplicationsAccepted()
+		targetMetrics.IncQueueApplicationsAccepted()
+	case Running.String():
+		sourceMetrics.DecQueueApplicationsRunning()
+		targetMetrics.IncQueueApplicationsRunning()
+	case Resuming.String():
+		sourceMetrics.DecQueueApplicationsResuming()
+		targetMetrics.IncQueueApplicationsResuming()
+	case Completing.String():
+		sourceMetrics.DecQueueApplicationsCompleting()
+		targetMetrics.IncQueueApplicationsCompleting()
+	}
+}
+
 func (sa *Application) StartTime() time.Time {
 	sa.RLock()
 	defer sa.RUnlock()
@@ -1909,6 +1987,9 @@ func (sa *Application) removeAllocationInternal(allocationKey string, releaseTyp
 		if sa.hasZeroAllocations() {
 			removeApp = true
 			event = CompleteApplication
+			if sa.IsFailing() {
+				event = FailApplication
+			}
 			eventWarning = "Application state not changed to Waiting while removing an allocation"
 		}
 		sa.decUserResourceUsage(alloc.GetAllocatedResource(), removeApp)
@@ -1967,7 +2048,11 @@ func (sa *Application) RemoveAllAllocations() []*Allocation {
 	sa.allocations = make(map[string]*Allocation)
 	// When the resource trackers are zero we should not expect anything to come in later.
 	if resources.IsZero(sa.pending) {
-		if err := sa.HandleApplicationEvent(CompleteApplication); err != nil {
+		event := CompleteApplication
+		if sa.IsFailing() {
+			event = FailApplication
+		}
+		if err := sa.HandleApplicationEvent(event); err != nil {
 			log.Log(log.SchedApplication).Warn("Application state not changed to Waiting while removing all allocations",
 				zap.String("currentState", sa.CurrentState()),
 				zap.Error(err))
@@ -0,0 +1,167 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"sort"
+
+	"github.com/G-Research/yunikorn-core/pkg/common"
+	"github.com/G-Research/yunikorn-core/pkg/common/resources"
+	"github.com/G-Research/yunikorn-core/pkg/scheduler/ugm"
+	"github.com/G-Research/yunikorn-core/pkg/webservice/dao"
+)
+
+// Explain evaluates all pending asks of the application against the checks the scheduler performs and returns the
+// reasons why each ask is not scheduled. The reasons for an ask are ordered in the same way the scheduler checks them:
+// application limits, user and group quota, queue quota and cluster capacity, reservations and finally the
+// allocation log entries (most recent first).
+// This call is not read-only for the user group manager: it follows the same path as the scheduler and creates the
+// trackers if they do not exist.
+func (sa *Application) Explain() *dao.ApplicationExplainDAOInfo {
+	sa.RLock()
+	requests := make([]*Allocation, 0, len(sa.sortedRequests))
+	for _, request := range sa.sortedRequests {
+		if !request.IsAllocated() {
+			requests = append(requests, request)
+		}
+	}
+	reserved := make(map[string][]string)
+	for _, reserve := range sa.reservations {
+		reserved[reserve.askKey] = append(reserved[reserve.askKey], reserve.nodeID)
+	}
+	queue := sa.queue
+	info := &dao.ApplicationExplainDAOInfo{
+		ID:            sa.ID,
+		ApplicationID: sa.ApplicationID,
+		Partition:     common.GetPartitionNameWithoutClusterID(sa.Partition),
+		QueueName:     sa.queuePath,
+		State:         sa.CurrentState(),
+		PendingAsks:   make([]*dao.AskExplainDAOInfo, 0, len(requests)),
+	}
+	sa.RUnlock()
+
+	// the application limits are only checked when the application is not running yet
+	var appReasons []*dao.BlockingReasonDAOInfo
+	if sa.IsAccepted() {
+		appReasons = queue.explainCanRunApp(sa.ApplicationID)
+		for _, violation := range ugm.GetUserManager().ExplainCanRunApp(info.QueueName, sa.ApplicationID, sa.user) {
+			reason := dao.ReasonUserMaxApplications
+			if violation.Group != common.Empty {
+				reason = dao.ReasonGroupMaxApplications
+			}
+			appReasons = append(appReasons, getLimitViolationDAO(reason, violation))
+		}
+	}
+
+	for _, request := range requests {
+		ask := request.GetAllocatedResource()
+		reasons := make([]*dao.BlockingReasonDAOInfo, 0, len(appReasons))
+		reasons = append(reasons, appReasons...)
+		for _, violation := range ugm.GetUserManager().ExplainHeadroom(info.QueueName, sa.ApplicationID, sa.user, ask) {
+			reason := dao.ReasonUserQuota
+			if violation.Group != common.Empty {
+				reason = dao.ReasonGroupQuota
+			}
+			reasons = append(reasons, getLimitViolationDAO(reason, violation))
+		}
+		reasons = append(reasons, queue.explainHeadRoom(ask)...)
+		for _, nodeID := range reserved[request.GetAllocationKey()] {
+			reasons = append(reasons, &dao.BlockingReasonDAOInfo{
+				Reason: dao.ReasonReserved,
+				NodeID: nodeID,
+			})
+		}
+		logEntries := request.GetAllocationLog()
+		sort.SliceStable(logEntries, func(i, j int) bool {
+			return logEntries[i].LastOccurrence.After(logEntries[j].LastOccurrence)
+		})
+		for _, entry := range logEntries {
+			reasons = append(reasons, &dao.BlockingReasonDAOInfo{
+				Reason:         dao.ReasonAllocationLog,
+				Message:        entry.Message,
+				Count:          entry.Count,
+				LastOccurrence: entry.LastOccurrence.UnixNano(),
+			})
+		}
+		info.PendingAsks = append(info.PendingAsks, &dao.AskExplainDAOInfo{
+			AllocationKey:    request.GetAllocationKey(),
+			ResourcePerAlloc: ask.DAOMap(),
+			RequiredNodeID:   request.GetRequiredNode(),
+			Reasons:          reasons,
+		})
+	}
+	return info
+}
+
+func getLimitViolationDAO(reason string, violation *ugm.LimitViolation) *dao.BlockingReasonDAOInfo {
+	return &dao.BlockingReasonDAOInfo{
+		Reason:          reason,
+		ResourceTypes:   violation.ResourceTypes,
+		Queue:           violation.QueuePath,
+		User:            violation.User,
+		Group:           violation.Group,
+		MaxApplications: violation.MaxApplications,
+	}
+}
+
+// explainCanRunApp returns the queues, ordered from this queue up to the root, for which the maximum number of
+// running applications prevents the application from running.
+// Lock free call, the queue locks are taken when needed.
+func (sq *Queue) explainCanRunApp(appID string) []*dao.BlockingReasonDAOInfo {
+	var reasons []*dao.BlockingReasonDAOInfo
+	for queue := sq; queue != nil; queue = queue.parent {
+		queue.RLock()
+		if queue.maxRunningApps != 0 && !queue.allocatingAcceptedApps[appID] &&
+			queue.runningApps+uint64(len(queue.allocatingAcceptedApps)+1) > queue.maxRunningApps {
+			reasons = append(reasons, &dao.BlockingReasonDAOInfo{
+				Reason:          dao.ReasonQueueMaxApplications,
+				Queue:           queue.QueuePath,
+				MaxApplications: queue.maxRunningApps,
+			})
+		}
+		queue.RUnlock()
+	}
+	return reasons
+}
+
+// explainHeadRoom returns the queues, ordered from this queue up to the root, for which the request does not fit
+// in the headroom. A limit on the root queue is the size of the cluster and is reported as such.
+// Lock free call, the queue locks are taken when needed.
+func (sq *Queue) explainHeadRoom(request *resources.Resource) []*dao.BlockingReasonDAOInfo {
+	var reasons []*dao.BlockingReasonDAOInfo
+	for queue := sq; queue != nil; queue = queue.parent {
+		queue.RLock()
+		if queue.maxResource != nil {
+			headRoom := resources.SubOnlyExisting(queue.maxResource, queue.allocatedResource)
+			if notFit := headRoom.NotFitInMaxUndef(request); len(notFit) > 0 {
+				reason := dao.ReasonQueueQuota
+				if queue.parent == nil {
+					reason = dao.ReasonClusterCapacity
+				}
+				reasons = append(reasons, &dao.BlockingReasonDAOInfo{
+					Reason:        reason,
+					ResourceTypes: notFit,
+					Queue:         queue.QueuePath,
+				})
+			}
+		}
+		queue.RUnlock()
+	}
+	return reasons
+}
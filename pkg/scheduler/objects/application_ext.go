@@ -23,6 +23,7 @@ import (
 	"testing"
 
 	"github.com/G-Research/yunikorn-core/pkg/common"
+	"github.com/G-Research/yunikorn-core/pkg/common/security"
 	"github.com/G-Research/yunikorn-core/pkg/locking"
 	"github.com/G-Research/yunikorn-core/pkg/webservice/dao"
 	"github.com/brianvoe/gofakeit/v7"
@@ -87,6 +88,19 @@ func (app *Application) DAO() *dao.ApplicationDAOInfo {
 	}
 }
 
+// NewDryRunApplication creates an application that is only used to execute the placement rules against.
+// The application is not tracked and no events are sent for it.
+func NewDryRunApplication(applicationID, partition, queuePath string, tags map[string]string, ugi security.UserGroup) *Application {
+	return &Application{
+		ApplicationID: applicationID,
+		Partition:     partition,
+		queuePath:     queuePath,
+		tags:          tags,
+		user:          ugi,
+		stateMachine:  NewAppState(),
+	}
+}
+
 func NewTestApplication(t *testing.T) *Application {
 	var app Application
 	err := gofakeit.Struct(&app)
@@ -27,17 +27,17 @@ import (
 )
 
 type ApplicationSummary struct {
-	ApplicationID       string
-	SubmissionTime      time.Time
-	StartTime           time.Time
-	FinishTime          time.Time
-	User                string
-	Queue               string
-	State               string
-	RmID                string
-	ResourceUsage       *resources.TrackedResource
-	PreemptedResource   *resources.TrackedResource
-	PlaceholderResource *resources.TrackedResource
+	ApplicationID       string                     `json:"applicationID"`
+	SubmissionTime      time.Time                  `json:"submissionTime"`
+	StartTime           time.Time                  `json:"startTime"`
+	FinishTime          time.Time                  `json:"finishTime"`
+	User                string                     `json:"user"`
+	Queue               string                     `json:"queue"`
+	State               string                     `json:"state"`
+	RmID                string                     `json:"rmID"`
+	ResourceUsage       *resources.TrackedResource `json:"resourceUsage,omitempty"`
+	PreemptedResource   *resources.TrackedResource `json:"preemptedResource,omitempty"`
+	PlaceholderResource *resources.TrackedResource `json:"placeholderResource,omitempty"`
 }
 
 func (as *ApplicationSummary) String() string {
@@ -39,6 +39,7 @@ import (
 	"github.com/G-Research/yunikorn-core/pkg/rmproxy/rmevent"
 	schedEvt "github.com/G-Research/yunikorn-core/pkg/scheduler/objects/events"
 	"github.com/G-Research/yunikorn-core/pkg/scheduler/ugm"
+	"github.com/G-Research/yunikorn-core/pkg/webservice/dao"
 	siCommon "github.com/G-Research/yunikorn-scheduler-interface/lib/go/common"
 	"github.com/G-Research/yunikorn-scheduler-interface/lib/go/si"
 	"github.com/oklog/ulid/v2"
@@ -1225,6 +1226,28 @@ func TestCompleted(t *testing.T) {
 	assert.Equal(t, log[3].ApplicationState, Expired.String())
 }
 
+func TestFailedOnLastAllocation(t *testing.T) {
+	setupUGM()
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 100})
+	app := newApplication(appID1, "default", "root.a")
+	alloc1 := newAllocation(appID1, nodeID1, res)
+	alloc2 := newAllocation(appID1, nodeID1, res)
+	app.AddAllocation(alloc1)
+	app.AddAllocation(alloc2)
+	assert.NilError(t, app.FailApplication("failed"), "fail should not have failed")
+	assert.Assert(t, app.IsFailing(), "application should be failing")
+	assert.Assert(t, app.RemoveAllocation(alloc1.GetAllocationKey(), si.TerminationType_STOPPED_BY_RM) != nil, "allocation should have been removed")
+	assert.Assert(t, app.IsFailing(), "application should be failing until the last allocation is removed")
+	assert.Assert(t, app.RemoveAllocation(alloc2.GetAllocationKey(), si.TerminationType_STOPPED_BY_RM) != nil, "allocation should have been removed")
+	assert.Assert(t, app.IsFailed(), "application should have failed")
+
+	app = newApplication(appID2, "default", "root.a")
+	app.AddAllocation(newAllocation(appID2, nodeID1, res))
+	assert.NilError(t, app.FailApplication("failed"), "fail should not have failed")
+	assert.Equal(t, 1, len(app.RemoveAllAllocations()))
+	assert.Assert(t, app.IsFailed(), "application should have failed")
+}
+
 func assertResourceUsage(t *testing.T, appSummary *ApplicationSummary, memorySeconds int64, vcoresSecconds int64) {
 	detailedResource := appSummary.ResourceUsage.TrackedResourceMap[instType1]
 	if detailedResource != nil {
@@ -3009,3 +3032,55 @@ func TestGetUint64Tag(t *testing.T) {
 		})
 	}
 }
+
+func TestApplicationExplain(t *testing.T) {
+	setupUGM()
+	root, err := createRootQueue(map[string]string{"first": "10"})
+	assert.NilError(t, err, "failed to create root queue")
+	leaf, err := createManagedQueueMaxApps(root, "leaf", false, map[string]string{"first": "5"}, 1)
+	assert.NilError(t, err, "failed to create leaf queue")
+	app := newApplication(appID1, "default", "root.leaf")
+	app.SetQueue(leaf)
+	leaf.AddApplication(app)
+
+	// no asks: nothing to explain
+	info := app.Explain()
+	assert.Equal(t, info.ApplicationID, appID1)
+	assert.Equal(t, info.QueueName, "root.leaf")
+	assert.Equal(t, len(info.PendingAsks), 0, "no pending asks expected")
+
+	// ask fits in the root but not in the leaf
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 8})
+	ask := newAllocationAsk(aKey, appID1, res)
+	assert.NilError(t, app.AddAllocationAsk(ask), "failed to add ask to app")
+	assert.Assert(t, app.IsAccepted(), "application should be accepted")
+	info = app.Explain()
+	assert.Equal(t, len(info.PendingAsks), 1, "expected one pending ask")
+	assert.Equal(t, info.PendingAsks[0].AllocationKey, aKey)
+	assert.DeepEqual(t, info.PendingAsks[0].Reasons, []*dao.BlockingReasonDAOInfo{
+		{Reason: dao.ReasonQueueQuota, Queue: "root.leaf", ResourceTypes: []string{"first"}},
+	})
+
+	// fill up the leaf, reserve a node and log a failure: check the order
+	leaf.incRunningApps(appID2)
+	node := newNode(nodeID1, map[string]resources.Quantity{"first": 10})
+	assert.NilError(t, app.Reserve(node, ask), "reservation failed")
+	ask.LogAllocationFailure("test failure", true)
+	info = app.Explain()
+	assert.Equal(t, len(info.PendingAsks), 1, "expected one pending ask")
+	reasons := info.PendingAsks[0].Reasons
+	assert.Equal(t, len(reasons), 4, "unexpected reasons: %v", reasons)
+	assert.DeepEqual(t, reasons[0], &dao.BlockingReasonDAOInfo{Reason: dao.ReasonQueueMaxApplications, Queue: "root.leaf", MaxApplications: 1})
+	assert.DeepEqual(t, reasons[1], &dao.BlockingReasonDAOInfo{Reason: dao.ReasonQueueQuota, Queue: "root.leaf", ResourceTypes: []string{"first"}})
+	assert.DeepEqual(t, reasons[2], &dao.BlockingReasonDAOInfo{Reason: dao.ReasonReserved, NodeID: nodeID1})
+	assert.Equal(t, reasons[3].Reason, dao.ReasonAllocationLog)
+	assert.Equal(t, reasons[3].Message, "test failure")
+	assert.Equal(t, reasons[3].Count, int32(1))
+
+	// the cluster size is reported on the root
+	large := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 11})
+	reasons = leaf.explainHeadRoom(large)
+	assert.Equal(t, len(reasons), 2, "expected leaf and root limits")
+	assert.Equal(t, reasons[1].Reason, dao.ReasonClusterCapacity)
+	assert.Equal(t, reasons[1].Queue, "root")
+}
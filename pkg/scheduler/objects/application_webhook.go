@@ -0,0 +1,92 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"time"
+
+	"github.com/looplab/fsm"
+
+	"github.com/G-Research/yunikorn-core/pkg/common"
+	"github.com/G-Research/yunikorn-core/pkg/webhook"
+)
+
+// webhookStates the application states sent to the queue webhooks, the value is true for terminal states.
+var webhookStates = map[string]bool{
+	Running.String():   false,
+	Completed.String(): true,
+	Failed.String():    true,
+	Rejected.String():  true,
+}
+
+// ApplicationWebhookPayload is the JSON body POSTed to the application webhooks of a queue.
+// Summary is only set for terminal states.
+type ApplicationWebhookPayload struct {
+	ApplicationID string              `json:"applicationID"`
+	Partition     string              `json:"partition"`
+	Queue         string              `json:"queue"`
+	User          string              `json:"user"`
+	State         string              `json:"state"`
+	PreviousState string              `json:"previousState"`
+	Message       string              `json:"message,omitempty"`
+	Timestamp     int64               `json:"timestamp"`
+	Summary       *ApplicationSummary `json:"summary,omitempty"`
+}
+
+// SetWebhookURLs sets the webhooks for an application that is rejected before it is added to a queue.
+func (sa *Application) SetWebhookURLs(urls []string) {
+	sa.Lock()
+	defer sa.Unlock()
+	sa.webhookURLs = urls
+}
+
+// notifyWebhooks sends the state change to the application webhooks configured on the queue.
+// Lock free call, must be called holding the application lock.
+func (sa *Application) notifyWebhooks(event *fsm.Event, eventInfo string) {
+	terminal, ok := webhookStates[event.Dst]
+	if !ok {
+		return
+	}
+	urls := sa.webhookURLs
+	if sa.queue != nil {
+		urls = sa.queue.GetWebhookURLs()
+	}
+	if len(urls) == 0 {
+		return
+	}
+	now := time.Now()
+	payload := &ApplicationWebhookPayload{
+		ApplicationID: sa.ApplicationID,
+		Partition:     common.GetPartitionNameWithoutClusterID(sa.Partition),
+		Queue:         sa.queuePath,
+		User:          sa.user.User,
+		State:         event.Dst,
+		PreviousState: event.Src,
+		Message:       eventInfo,
+		Timestamp:     now.UnixNano(),
+	}
+	if terminal {
+		payload.Summary = sa.getApplicationSummary(sa.rmID)
+		// the finish time of completed and failed applications is only set when they are removed from the queue
+		if payload.Summary.FinishTime.IsZero() {
+			payload.Summary.FinishTime = now
+		}
+	}
+	webhook.GetNotifier().Notify(urls, sa.ApplicationID+"/"+event.Dst, payload)
+}
@@ -0,0 +1,126 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package objects
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/G-Research/yunikorn-core/pkg/common"
+	"github.com/G-Research/yunikorn-core/pkg/common/configs"
+	"github.com/G-Research/yunikorn-core/pkg/webhook"
+)
+
+// startWebhookReceiver starts the notifier and a server that collects the payloads.
+func startWebhookReceiver(t *testing.T) (string, func() []ApplicationWebhookPayload) {
+	var lock sync.Mutex
+	var payloads []ApplicationWebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload ApplicationWebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		payloads = append(payloads, payload)
+	}))
+	webhook.GetNotifier().StartService()
+	t.Cleanup(func() {
+		webhook.GetNotifier().Stop()
+		server.Close()
+	})
+	return server.URL, func() []ApplicationWebhookPayload {
+		lock.Lock()
+		defer lock.Unlock()
+		return payloads
+	}
+}
+
+func waitForPayloads(t *testing.T, getPayloads func() []ApplicationWebhookPayload, count int) []ApplicationWebhookPayload {
+	err := common.WaitForCondition(time.Millisecond, time.Second, func() bool {
+		return len(getPayloads()) == count
+	})
+	assert.NilError(t, err, "expected %d webhook payloads, got %d", count, len(getPayloads()))
+	return getPayloads()
+}
+
+func TestApplicationWebhooks(t *testing.T) {
+	url, getPayloads := startWebhookReceiver(t)
+	root, err := createRootQueue(nil)
+	assert.NilError(t, err, "failed to create root queue")
+	var leaf *Queue
+	leaf, err = createManagedQueueWithProps(root, "leaf", false, nil, map[string]string{configs.ApplicationWebhookURL: url})
+	assert.NilError(t, err, "failed to create leaf queue")
+
+	// the cluster ID is not part of the partition name in the payload
+	app := newApplication(appID1, "[rm-123]default", "root.leaf")
+	app.SetQueue(leaf)
+	// Accepted is not sent
+	assert.NilError(t, app.HandleApplicationEvent(RunApplication))
+	assert.NilError(t, app.HandleApplicationEvent(RunApplication))
+	assert.Equal(t, Running.String(), app.CurrentState())
+	payloads := waitForPayloads(t, getPayloads, 1)
+	assert.Equal(t, appID1, payloads[0].ApplicationID)
+	assert.Equal(t, "default", payloads[0].Partition)
+	assert.Equal(t, "root.leaf", payloads[0].Queue)
+	assert.Equal(t, Running.String(), payloads[0].State)
+	assert.Equal(t, Accepted.String(), payloads[0].PreviousState)
+	assert.Assert(t, payloads[0].Timestamp > 0)
+	assert.Assert(t, payloads[0].Summary == nil, "running state should not have a summary")
+
+	// Failing is not sent, Failed has the summary
+	assert.NilError(t, app.handleApplicationEventWithInfoLocking(FailApplication, "failure"))
+	assert.NilError(t, app.handleApplicationEventWithInfoLocking(FailApplication, "failure"))
+	assert.Equal(t, Failed.String(), app.CurrentState())
+	payloads = waitForPayloads(t, getPayloads, 2)
+	assert.Equal(t, Failed.String(), payloads[1].State)
+	assert.Equal(t, Failing.String(), payloads[1].PreviousState)
+	assert.Equal(t, "failure", payloads[1].Message)
+	assert.Assert(t, payloads[1].Summary != nil, "failed state should have a summary")
+	assert.Equal(t, appID1, payloads[1].Summary.ApplicationID)
+	assert.Equal(t, Failed.String(), payloads[1].Summary.State)
+	assert.Assert(t, !payloads[1].Summary.FinishTime.IsZero())
+}
+
+func TestApplicationWebhooks_Rejected(t *testing.T) {
+	url, getPayloads := startWebhookReceiver(t)
+	app := newApplication(appID1, "default", "root.unknown")
+	app.SetWebhookURLs([]string{url})
+	assert.NilError(t, app.handleApplicationEventWithInfoLocking(RejectApplication, "no placement rule matched"))
+	payloads := waitForPayloads(t, getPayloads, 1)
+	assert.Equal(t, Rejected.String(), payloads[0].State)
+	assert.Equal(t, "no placement rule matched", payloads[0].Message)
+	assert.Assert(t, payloads[0].Summary != nil, "rejected state should have a summary")
+	assert.Equal(t, Rejected.String(), payloads[0].Summary.State)
+}
+
+func TestApplicationWebhooks_NoURLs(t *testing.T) {
+	_, getPayloads := startWebhookReceiver(t)
+	app := newApplication(appID1, "default", "root.unknown")
+	assert.NilError(t, app.handleApplicationEventWithInfoLocking(RejectApplication, "rejected"))
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, 0, len(getPayloads()))
+}
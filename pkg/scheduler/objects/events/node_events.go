@@ -19,6 +19,9 @@
 package events
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/G-Research/yunikorn-core/pkg/common"
 	"github.com/G-Research/yunikorn-core/pkg/common/resources"
 	"github.com/G-Research/yunikorn-core/pkg/events"
@@ -80,6 +83,50 @@ func (n *NodeEvents) SendNodeSchedulableChangedEvent(nodeID string, ready bool,
 	n.eventSystem.AddEvent(event)
 }
 
+// SendNodeCordonChangedEvent records a schedulable state change requested by an operator.
+func (n *NodeEvents) SendNodeCordonChangedEvent(nodeID string, cordoned bool, user, reason string, state string) {
+	if !n.eventSystem.IsEventTrackingEnabled() {
+		return
+	}
+	message := "schedulable: true, uncordoned by " + user
+	if cordoned {
+		message = "schedulable: false, cordoned by " + user
+	}
+	event := events.CreateNodeEventRecord(nodeID, withReason(message, reason), common.Empty, si.EventRecord_SET,
+		si.EventRecord_NODE_SCHEDULABLE, nil, state)
+	n.eventSystem.AddEvent(event)
+}
+
+// SendNodeDrainRequestedEvent records a drain requested by an operator and the grace period before the release of
+// the allocations is requested from the RM.
+func (n *NodeEvents) SendNodeDrainRequestedEvent(nodeID string, user, reason string, gracePeriod time.Duration, state string) {
+	if !n.eventSystem.IsEventTrackingEnabled() {
+		return
+	}
+	message := fmt.Sprintf("drain requested by %s, grace period %s", user, gracePeriod)
+	event := events.CreateNodeEventRecord(nodeID, withReason(message, reason), common.Empty, si.EventRecord_SET,
+		si.EventRecord_NODE_SCHEDULABLE, nil, state)
+	n.eventSystem.AddEvent(event)
+}
+
+// SendNodeDrainedEvent records the release of the allocations requested from the RM after the grace period.
+func (n *NodeEvents) SendNodeDrainedEvent(nodeID string, user, reason string, released int, state string) {
+	if !n.eventSystem.IsEventTrackingEnabled() {
+		return
+	}
+	message := fmt.Sprintf("drained by %s, releasing %d allocations", user, released)
+	event := events.CreateNodeEventRecord(nodeID, withReason(message, reason), common.Empty, si.EventRecord_SET,
+		si.EventRecord_NODE_SCHEDULABLE, nil, state)
+	n.eventSystem.AddEvent(event)
+}
+
+func withReason(message, reason string) string {
+	if reason == "" {
+		return message
+	}
+	return message + ": " + reason
+}
+
 func (n *NodeEvents) SendNodeCapacityChangedEvent(nodeID string, total *resources.Resource, state string) {
 	if !n.eventSystem.IsEventTrackingEnabled() {
 		return
@@ -20,6 +20,7 @@ package events
 
 import (
 	"testing"
+	"time"
 
 	"gotest.tools/v3/assert"
 
@@ -191,6 +192,48 @@ func TestNodeSchedulableChangedEvent(t *testing.T) {
 	assert.Equal(t, 0, len(event.Resource.Resources))
 }
 
+func TestNodeCordonChangedEvent(t *testing.T) {
+	eventSystem := mock.NewEventSystemDisabled()
+	ne := NewNodeEvents(eventSystem)
+	ne.SendNodeCordonChangedEvent(nodeID1, true, "admin", "maintenance", "")
+	assert.Equal(t, 0, len(eventSystem.Events), "unexpected event")
+
+	eventSystem = mock.NewEventSystem()
+	ne = NewNodeEvents(eventSystem)
+	ne.SendNodeCordonChangedEvent(nodeID1, true, "admin", "maintenance", "")
+	assert.Equal(t, 1, len(eventSystem.Events), "event was not generated")
+	event := eventSystem.Events[0]
+	assert.Equal(t, nodeID1, event.ObjectID)
+	assert.Equal(t, "schedulable: false, cordoned by admin: maintenance", event.Message)
+	assert.Equal(t, si.EventRecord_SET, event.EventChangeType)
+	assert.Equal(t, si.EventRecord_NODE_SCHEDULABLE, event.EventChangeDetail)
+
+	eventSystem.Reset()
+	ne.SendNodeCordonChangedEvent(nodeID1, false, "admin", "", "")
+	assert.Equal(t, 1, len(eventSystem.Events), "event was not generated")
+	assert.Equal(t, "schedulable: true, uncordoned by admin", eventSystem.Events[0].Message)
+}
+
+func TestNodeDrainEvents(t *testing.T) {
+	eventSystem := mock.NewEventSystemDisabled()
+	ne := NewNodeEvents(eventSystem)
+	ne.SendNodeDrainRequestedEvent(nodeID1, "admin", "maintenance", time.Minute, "")
+	ne.SendNodeDrainedEvent(nodeID1, "admin", "maintenance", 2, "")
+	assert.Equal(t, 0, len(eventSystem.Events), "unexpected event")
+
+	eventSystem = mock.NewEventSystem()
+	ne = NewNodeEvents(eventSystem)
+	ne.SendNodeDrainRequestedEvent(nodeID1, "admin", "maintenance", time.Minute, "")
+	ne.SendNodeDrainedEvent(nodeID1, "admin", "maintenance", 2, "")
+	assert.Equal(t, 2, len(eventSystem.Events), "events were not generated")
+	event := eventSystem.Events[0]
+	assert.Equal(t, nodeID1, event.ObjectID)
+	assert.Equal(t, "drain requested by admin, grace period 1m0s: maintenance", event.Message)
+	assert.Equal(t, si.EventRecord_SET, event.EventChangeType)
+	assert.Equal(t, si.EventRecord_NODE_SCHEDULABLE, event.EventChangeDetail)
+	assert.Equal(t, "drained by admin, releasing 2 allocations: maintenance", eventSystem.Events[1].Message)
+}
+
 func TestNodeReservationEvent(t *testing.T) {
 	resource := resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1})
 	eventSystem := mock.NewEventSystemDisabled()
@@ -22,6 +22,7 @@ import (
 	"bytes"
 	"fmt"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -57,7 +58,10 @@ type Node struct {
 	allocatedResource *resources.Resource
 	availableResource *resources.Resource
 	allocations       map[string]*Allocation
-	schedulable       bool
+	schedulable       bool   // schedulable state set by the RM
+	cordoned          bool   // cordoned by an operator, independent of the RM state
+	cordonedBy        string // operator that cordoned the node
+	cordonReason      string // reason given by the operator, cleared when the node is uncordoned
 
 	reservations map[string]*reservation // a map of reservations
 	listeners    []NodeListener          // a list of node listeners
@@ -106,7 +110,7 @@ func (sn *Node) String() string {
 		return "node is nil"
 	}
 	return fmt.Sprintf("NodeID %s, Partition %s, PartitionID %s, Schedulable %t, Total %s, Allocated %s, #allocations %d, ID %s",
-		sn.NodeID, sn.Partition, sn.PartitionID, sn.schedulable, sn.totalResource, sn.allocatedResource,
+		sn.NodeID, sn.Partition, sn.PartitionID, sn.isSchedulable(), sn.totalResource, sn.allocatedResource,
 		len(sn.allocations), sn.ID)
 }
 
@@ -267,20 +271,57 @@ func (sn *Node) getAllocations(foreign bool) []*Allocation {
 
 // Set the node to unschedulable.
 // This will cause the node to be skipped during the scheduling cycle.
-// Visible for testing only
-func (sn *Node) SetSchedulable(schedulable bool) {
+// The RM state is kept separate from a cordon: a cordoned node stays unschedulable until it is uncordoned.
+// Returns true if the schedulable state of the node, as returned by IsSchedulable, changed.
+func (sn *Node) SetSchedulable(schedulable bool) bool {
 	defer sn.notifyListeners()
 	sn.Lock()
 	defer sn.Unlock()
+	before := sn.isSchedulable()
 	sn.schedulable = schedulable
 	sn.nodeEvents.SendNodeSchedulableChangedEvent(sn.NodeID, sn.schedulable, sn.daoSnapshot())
+	return before != sn.isSchedulable()
+}
+
+// SetCordoned changes the cordon of the node on request of an operator. A cordoned node is not schedulable, the
+// operator and reason are kept until the node is uncordoned. The schedulable state set by the RM is not changed.
+// Returns true if the schedulable state of the node, as returned by IsSchedulable, changed. The operator and reason
+// are updated if the node was already cordoned.
+func (sn *Node) SetCordoned(cordoned bool, user, reason string) bool {
+	defer sn.notifyListeners()
+	sn.Lock()
+	defer sn.Unlock()
+	before := sn.isSchedulable()
+	sn.cordoned = cordoned
+	sn.cordonedBy = ""
+	sn.cordonReason = ""
+	if cordoned {
+		sn.cordonedBy = user
+		sn.cordonReason = reason
+	}
+	sn.nodeEvents.SendNodeCordonChangedEvent(sn.NodeID, cordoned, user, reason, sn.daoSnapshot())
+	return before != sn.isSchedulable()
+}
+
+// GetCordonReason returns the operator that cordoned the node and the reason given.
+// Both are empty if the node is not cordoned.
+func (sn *Node) GetCordonReason() (string, string) {
+	sn.RLock()
+	defer sn.RUnlock()
+	return sn.cordonedBy, sn.cordonReason
 }
 
 // Can this node be used in scheduling.
 func (sn *Node) IsSchedulable() bool {
 	sn.RLock()
 	defer sn.RUnlock()
-	return sn.schedulable
+	return sn.isSchedulable()
+}
+
+// isSchedulable returns true if the RM allows scheduling on the node and it is not cordoned.
+// Must be called holding the node lock.
+func (sn *Node) isSchedulable() bool {
+	return sn.schedulable && !sn.cordoned
 }
 
 // Get the allocated resource on this node.
@@ -670,3 +711,17 @@ func (sn *Node) SendNodeAddedEvent() {
 func (sn *Node) SendNodeRemovedEvent() {
 	sn.nodeEvents.SendNodeRemovedEvent(sn.NodeID, sn.daoSnapshot())
 }
+
+// SendNodeDrainRequestedEvent records that an operator requested to drain the node.
+func (sn *Node) SendNodeDrainRequestedEvent(user, reason string, gracePeriod time.Duration) {
+	sn.RLock()
+	defer sn.RUnlock()
+	sn.nodeEvents.SendNodeDrainRequestedEvent(sn.NodeID, user, reason, gracePeriod, sn.daoSnapshot())
+}
+
+// SendNodeDrainedEvent records that the release of the allocations was requested after the drain grace period.
+func (sn *Node) SendNodeDrainedEvent(user, reason string, released int) {
+	sn.RLock()
+	defer sn.RUnlock()
+	sn.nodeEvents.SendNodeDrainedEvent(sn.NodeID, user, reason, released, sn.daoSnapshot())
+}
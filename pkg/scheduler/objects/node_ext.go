@@ -40,9 +40,11 @@ func (node *Node) dao() *dao.NodeDAOInfo {
 		Utilized:           node.getUtilizedResource().DAOMap(),
 		Allocations:        getAllocationsDAO(node.getAllocations(false)),
 		ForeignAllocations: getForeignAllocationsDAO(node.getAllocations(true)),
-		Schedulable:        node.schedulable,
+		Schedulable:        node.isSchedulable(),
 		IsReserved:         len(node.reservations) > 0,
 		Reservations:       node.getReservationKeys(),
+		CordonedBy:         node.cordonedBy,
+		CordonReason:       node.cordonReason,
 	}
 }
 
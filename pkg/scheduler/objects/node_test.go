@@ -786,6 +786,51 @@ func TestAddRemoveListener(t *testing.T) {
 	assert.Equal(t, 1, tl.updateCount, "listener should not have fired again")
 }
 
+func TestSetCordoned(t *testing.T) {
+	mockEvents := evtMock.NewEventSystem()
+	node := newNode("node-1", map[string]resources.Quantity{"first": 10})
+	node.nodeEvents = schedEvt.NewNodeEvents(mockEvents)
+	tl := testListener{}
+	node.AddListener(&tl)
+
+	assert.Assert(t, node.SetCordoned(true, "admin", "maintenance"), "cordon should have changed the node")
+	assert.Assert(t, !node.IsSchedulable(), "cordoned node should not be schedulable")
+	user, reason := node.GetCordonReason()
+	assert.Equal(t, "admin", user)
+	assert.Equal(t, "maintenance", reason)
+	assert.Equal(t, "maintenance", node.dao().CordonReason)
+	assert.Equal(t, 1, tl.updateCount, "listener should have fired")
+	assert.Equal(t, "schedulable: false, cordoned by admin: maintenance", mockEvents.Events[0].Message)
+
+	// second cordon updates the reason
+	assert.Assert(t, !node.SetCordoned(true, "other", "upgrade"), "cordon should not have changed the schedulable state")
+	user, reason = node.GetCordonReason()
+	assert.Equal(t, "other", user)
+	assert.Equal(t, "upgrade", reason)
+
+	assert.Assert(t, node.SetCordoned(false, "admin", "done"), "uncordon should have changed the schedulable state")
+	assert.Assert(t, node.IsSchedulable(), "uncordoned node should be schedulable")
+	user, reason = node.GetCordonReason()
+	assert.Equal(t, "", user+reason, "reason should have been cleared")
+
+	// the RM changing the state does not clear the cordon
+	node.SetCordoned(true, "admin", "maintenance")
+	assert.Assert(t, !node.SetSchedulable(false), "RM drain of a cordoned node should not have changed the schedulable state")
+	assert.Assert(t, !node.SetSchedulable(true), "RM schedulable update should not have uncordoned the node")
+	assert.Assert(t, !node.IsSchedulable(), "cordoned node should not be schedulable")
+	assert.Assert(t, !node.dao().Schedulable, "cordoned node should not be schedulable in the DAO")
+	user, reason = node.GetCordonReason()
+	assert.Equal(t, "admin", user)
+	assert.Equal(t, "maintenance", reason)
+
+	// uncordon keeps the node unschedulable while the RM drains it
+	assert.Assert(t, !node.SetSchedulable(false), "RM drain of a cordoned node should not have changed the schedulable state")
+	assert.Assert(t, !node.SetCordoned(false, "admin", "done"), "uncordon of a drained node should not have changed the schedulable state")
+	assert.Assert(t, !node.IsSchedulable(), "node drained by the RM should not be schedulable")
+	assert.Assert(t, node.SetSchedulable(true), "RM schedulable update should have changed the schedulable state")
+	assert.Assert(t, node.IsSchedulable(), "node should be schedulable")
+}
+
 func TestNodeEvents(t *testing.T) {
 	mockEvents := evtMock.NewEventSystem()
 	total := resources.NewResourceFromMap(map[string]resources.Quantity{"cpu": 100, "memory": 100})
@@ -23,6 +23,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -65,6 +68,7 @@ type Queue struct {
 	parent              *Queue                    // link back to the parent in the scheduler
 	pending             *resources.Resource       // pending resource for the apps in the queue
 	allocatedResource   *resources.Resource       // allocated resource for the apps in the queue
+	usageHistory        *resources.DecayedUsage   // allocated resource integrated over time with decay
 	preemptingResource  *resources.Resource       // preempting resource for the apps in the queue
 	prioritySortEnabled bool                      // whether priority is used for request sorting
 	priorityPolicy      policies.PriorityPolicy   // priority policy
@@ -72,6 +76,8 @@ type Queue struct {
 	preemptionPolicy    policies.PreemptionPolicy // preemption policy
 	preemptionDelay     time.Duration             // time before preemption is considered
 	currentPriority     int32                     // the current scheduling priority of this queue
+	webhookURLs         []string                  // application lifecycle webhooks
+	weight              float64                   // share of the queue relative to its siblings for fair sorting
 
 	// The queue properties should be treated as immutable the value is a merge of the
 	// parent properties with the config for this queue only manipulated during creation
@@ -111,12 +117,14 @@ func newBlankQueue() *Queue {
 		properties:             make(map[string]string),
 		stateMachine:           NewObjectState(),
 		allocatedResource:      resources.NewResource(),
+		usageHistory:           resources.NewDecayedUsage(),
 		preemptingResource:     resources.NewResource(),
 		pending:                resources.NewResource(),
 		currentPriority:        configs.MinPriority,
 		prioritySortEnabled:    true,
 		preemptionDelay:        configs.DefaultPreemptionDelay,
 		preemptionPolicy:       policies.DefaultPreemptionPolicy,
+		weight:                 configs.DefaultQueueWeight,
 	}
 }
 
@@ -283,6 +291,50 @@ func priorityOffset(value string) (int32, error) {
 	return int32(intValue), nil
 }
 
+// parseWebhookURLs parses a comma separated list of http or https URLs. Invalid URLs are skipped.
+func parseWebhookURLs(value string) ([]string, error) {
+	var result []string
+	var errs []error
+	for _, raw := range strings.Split(value, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		parsed, err := url.Parse(raw)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			errs = append(errs, fmt.Errorf("invalid %s value: %s", configs.ApplicationWebhookURL, raw))
+			continue
+		}
+		result = append(result, raw)
+	}
+	return result, errors.Join(errs...)
+}
+
+// queueWeight returns the weight of the queue, a positive number. The default weight is returned on error.
+func queueWeight(value string) (float64, error) {
+	weight, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return configs.DefaultQueueWeight, err
+	}
+	if weight <= 0 || math.IsInf(weight, 0) || math.IsNaN(weight) {
+		return configs.DefaultQueueWeight, fmt.Errorf("invalid %s value: %s, must be a positive number", configs.QueueWeight, value)
+	}
+	return weight, nil
+}
+
+// queueSortPolicy returns the policy used to sort the child queues of a parent queue, fair unless DRF or the usage
+// history is set.
+func queueSortPolicy(value string) (policies.SortPolicy, error) {
+	policy, err := policies.SortPolicyFromString(value)
+	if err != nil {
+		return policies.FairSortPolicy, err
+	}
+	if policy != policies.FairSortPolicy && policy != policies.DrfSortPolicy && policy != policies.UsageHistorySortPolicy {
+		return policies.FairSortPolicy, fmt.Errorf("unsupported %s value: %s", configs.QueueSortPolicy, value)
+	}
+	return policy, nil
+}
+
 func applicationSortPriorityEnabled(value string) (bool, error) {
 	switch strings.ToLower(value) {
 	case configs.ApplicationSortPriorityEnabled:
@@ -303,6 +355,9 @@ func filterParentProperty(key string, value string) string {
 	case configs.PriorityOffset:
 		// priority offsets are not inherited as they are additive
 		return "0"
+	case configs.QueueWeight:
+		// weights are relative to the siblings, not inherited
+		return strconv.FormatFloat(configs.DefaultQueueWeight, 'f', -1, 64)
 	case configs.PreemptionPolicy:
 		// only 'disabled' should be allowed to propagate
 		if pol, err := policies.PreemptionPolicyFromString(value); err != nil || pol != policies.DisabledPreemptionPolicy {
@@ -496,6 +551,8 @@ func (sq *Queue) UpdateQueueProperties() {
 		// set the sorting type for parent queues
 		sq.sortType = policies.FairSortPolicy
 	}
+	sq.webhookURLs = nil
+	sq.weight = configs.DefaultQueueWeight
 	// walk over all properties and process
 	var err error
 	for key, value := range sq.properties {
@@ -512,6 +569,14 @@ func (sq *Queue) UpdateQueueProperties() {
 					sq.sortType = policies.FifoSortPolicy
 				}
 			}
+		case configs.QueueSortPolicy:
+			if !sq.isLeaf {
+				sq.sortType, err = queueSortPolicy(value)
+				if err != nil {
+					log.Log(log.SchedQueue).Debug("queue sort property configuration error",
+						zap.Error(err))
+				}
+			}
 		case configs.ApplicationSortPriority:
 			sq.prioritySortEnabled, err = applicationSortPriorityEnabled(value)
 			if err != nil {
@@ -544,6 +609,19 @@ func (sq *Queue) UpdateQueueProperties() {
 						zap.Error(err))
 				}
 			}
+		case configs.QueueWeight:
+			sq.weight, err = queueWeight(value)
+			if err != nil {
+				log.Log(log.SchedQueue).Debug("queue weight property configuration error",
+					zap.Error(err))
+			}
+		case configs.ApplicationWebhookURL:
+			sq.webhookURLs, err = parseWebhookURLs(value)
+			if err != nil {
+				log.Log(log.SchedQueue).Warn("application webhook property configuration error",
+					zap.String("queue", sq.QueuePath),
+					zap.Error(err))
+			}
 		default:
 			// skip unknown properties just log them
 			log.Log(log.SchedQueue).Debug("queue property skipped",
@@ -627,6 +705,13 @@ func (sq *Queue) GetMaxApps() uint64 {
 	return sq.maxRunningApps
 }
 
+// GetRunningApps returns the number of applications running in this queue.
+func (sq *Queue) GetRunningApps() uint64 {
+	sq.RLock()
+	defer sq.RUnlock()
+	return sq.runningApps
+}
+
 // GetActualGuaranteedResources returns the actual (including parent) guaranteed resources for the queue.
 func (sq *Queue) GetActualGuaranteedResource() *resources.Resource {
 	if sq == nil {
@@ -644,6 +729,13 @@ func (sq *Queue) GetPreemptionDelay() time.Duration {
 	return sq.preemptionDelay
 }
 
+// GetWebhookURLs returns the application lifecycle webhook URLs of the queue.
+func (sq *Queue) GetWebhookURLs() []string {
+	sq.RLock()
+	defer sq.RUnlock()
+	return sq.webhookURLs
+}
+
 // CheckSubmitAccess checks if the user has access to the queue to submit an application.
 // The check is performed recursively: i.e. access to the parent allows access to this queue.
 // This will check both submitACL and adminACL.
@@ -720,6 +812,8 @@ func (sq *Queue) GetPartitionQueueDAOInfo(include bool) dao.PartitionQueueDAOInf
 	}
 	queueInfo.MaxRunningApps = sq.maxRunningApps
 	queueInfo.RunningApps = sq.runningApps
+	queueInfo.Weight = sq.weight
+	queueInfo.DecayedUsage = sq.usageHistory.Value(time.Now()).DAOMap()
 	queueInfo.AllocatingAcceptedApps = make([]string, 0)
 	for appID, result := range sq.allocatingAcceptedApps {
 		if result {
@@ -729,6 +823,14 @@ func (sq *Queue) GetPartitionQueueDAOInfo(include bool) dao.PartitionQueueDAOInf
 	return queueInfo
 }
 
+// GetDecayedUsage returns the allocated resources of the queue integrated over time, with the configured decay, in
+// resource-seconds at the time passed in.
+func (sq *Queue) GetDecayedUsage(now time.Time) *resources.Resource {
+	sq.RLock()
+	defer sq.RUnlock()
+	return sq.usageHistory.Value(now)
+}
+
 // GetPendingResource returns the pending resources for this queue.
 func (sq *Queue) GetPendingResource() *resources.Resource {
 	sq.RLock()
@@ -1054,6 +1156,7 @@ func (sq *Queue) TryIncAllocatedResource(alloc *resources.Resource) error {
 	// all OK update this queue
 	sq.allocatedResource = resources.Add(sq.allocatedResource, alloc)
 	sq.updateAllocatedResourceMetrics()
+	sq.usageHistory.Update(time.Now(), sq.allocatedResource)
 	return nil
 }
 
@@ -1072,6 +1175,7 @@ func (sq *Queue) IncAllocatedResource(alloc *resources.Resource) {
 	defer sq.Unlock()
 	sq.allocatedResource = resources.Add(sq.allocatedResource, alloc)
 	sq.updateAllocatedResourceMetrics()
+	sq.usageHistory.Update(time.Now(), sq.allocatedResource)
 }
 
 // allocatedResFits adds the passed in resource to the allocatedResource of the queue and checks if it still fits in the
@@ -1121,6 +1225,7 @@ func (sq *Queue) DecAllocatedResource(alloc *resources.Resource) error {
 	defer sq.Unlock()
 	// all OK update the queue
 	sq.allocatedResource = resources.Sub(sq.allocatedResource, alloc)
+	sq.usageHistory.Update(time.Now(), sq.allocatedResource)
 	// We should update the metrics before pruning the resource.
 	// For example:
 	// If we prune the resource first and the resource become nil after pruning,
@@ -1194,8 +1299,14 @@ func (sq *Queue) sortApplications(withPlaceholdersOnly bool) []*Application {
 		return nil
 	}
 
-	// sort applications based on the sorting policy
-	return sortApplications(apps, sq.getSortType(), sq.IsPrioritySortEnabled(), sq.GetGuaranteedResource())
+	// sort applications based on the sorting policy, DRF and the usage history use the partition capacity for the
+	// dominant share, the user fairness for the usage ratio of the users
+	sortType := sq.getSortType()
+	globalResource := sq.GetGuaranteedResource()
+	if sortType == policies.DrfSortPolicy || sortType == policies.UsageHistorySortPolicy || sortType == policies.UserFairSortPolicy {
+		globalResource = sq.getPartitionCapacity()
+	}
+	return sortApplications(apps, sortType, sq.IsPrioritySortEnabled(), globalResource)
 }
 
 // sortQueues returns a sorted shallow copy of the queues for this parent queue.
@@ -1209,7 +1320,11 @@ func (sq *Queue) sortQueues() []*Queue {
 	// Create a list of the queues with pending resources
 	sortedQueues := make([]*Queue, 0)
 	sortedMaxFairResources := make([]*resources.Resource, 0)
-	for _, child := range sq.GetCopyOfChildren() {
+	children := sq.GetCopyOfChildren()
+	// the weights and the fair max of this queue are the same for all children: calculate them once
+	weights := getRelativeWeights(children)
+	fairMax := sq.GetFairMaxResource()
+	for _, child := range children {
 		// a stopped queue cannot be scheduled
 		if child.IsStopped() {
 			continue
@@ -1217,11 +1332,11 @@ func (sq *Queue) sortQueues() []*Queue {
 		// queue must have pending resources to be considered for scheduling
 		if resources.StrictlyGreaterThanZero(child.GetPendingResource()) {
 			sortedQueues = append(sortedQueues, child)
-			sortedMaxFairResources = append(sortedMaxFairResources, child.GetFairMaxResource())
+			sortedMaxFairResources = append(sortedMaxFairResources, child.getWeightedFairMaxResource(fairMax, weights))
 		}
 	}
 	// Sort the queues
-	sortQueue(sortedQueues, sortedMaxFairResources, sq.getSortType(), sq.IsPrioritySortEnabled())
+	sortQueue(sortedQueues, sortedMaxFairResources, sq.getSortType(), sq.IsPrioritySortEnabled(), sq.getPartitionCapacity(), weights)
 
 	return sortedQueues
 }
@@ -1296,14 +1411,57 @@ func (sq *Queue) GetMaxResource() *resources.Resource {
 // If the root includes an explicit 0 value for a Resource, do not include it in the accumulator and treat it as missing.
 // If no children provide a maximum capacity override, the resulting value will be the value found on the Root.
 // It is useful for fair-scheduling to allow a ratio to be produced representing the rough utilization % of a given queue.
+// The fair max of a queue is scaled by its weight relative to the average weight of its siblings: a queue with twice
+// the weight of a sibling gets twice the share before it is sorted after that sibling.
 func (sq *Queue) GetFairMaxResource() *resources.Resource {
-	var limit *resources.Resource
 	if sq.parent == nil {
 		return sq.GetMaxResource().Clone()
 	}
+	weights := getRelativeWeights(sq.parent.GetCopyOfChildren())
+	return sq.getWeightedFairMaxResource(sq.parent.GetFairMaxResource(), weights)
+}
 
-	limit = sq.parent.GetFairMaxResource()
-	return sq.internalGetFairMaxResource(limit)
+// getWeightedFairMaxResource returns the fair max of the queue based on the fair max of the parent, scaled by the
+// relative weight of the queue.
+func (sq *Queue) getWeightedFairMaxResource(limit *resources.Resource, weights map[*Queue]float64) *resources.Resource {
+	fairMax := sq.internalGetFairMaxResource(limit)
+	if weight, ok := weights[sq]; ok && weight != 1 {
+		fairMax = resources.MultiplyBy(fairMax, weight)
+	}
+	return fairMax
+}
+
+// getRelativeWeights returns the weight of each queue divided by the average weight of the queues. Returns nil if
+// all queues use the same weight, which is the case if no weights are configured: the weights do not change anything.
+// Lock free call all locks are taken when needed in called functions
+func getRelativeWeights(queues map[string]*Queue) map[*Queue]float64 {
+	weights := make(map[*Queue]float64, len(queues))
+	var total, first float64
+	same := true
+	for _, queue := range queues {
+		weight := queue.GetWeight()
+		if len(weights) == 0 {
+			first = weight
+		} else if weight != first {
+			same = false
+		}
+		weights[queue] = weight
+		total += weight
+	}
+	if same || total <= 0 {
+		return nil
+	}
+	for queue, weight := range weights {
+		weights[queue] = weight * float64(len(queues)) / total
+	}
+	return weights
+}
+
+// GetWeight returns the weight of the queue for fair sharing with its siblings.
+func (sq *Queue) GetWeight() float64 {
+	sq.RLock()
+	defer sq.RUnlock()
+	return sq.weight
 }
 
 func (sq *Queue) internalGetFairMaxResource(limit *resources.Resource) *resources.Resource {
@@ -1657,6 +1815,16 @@ func (sq *Queue) getSortType() policies.SortPolicy {
 	return sq.sortType
 }
 
+// getPartitionCapacity returns the capacity of the partition: the max resource of the root queue.
+// Lock free call all locks are taken when needed in called functions
+func (sq *Queue) getPartitionCapacity() *resources.Resource {
+	root := sq
+	for root.parent != nil {
+		root = root.parent
+	}
+	return root.GetMaxResource()
+}
+
 // SupportTaskGroup returns true if the queue supports task groups.
 // FIFO policy is required to support this.
 // NOTE: this call does not make sense for a parent queue, and always returns false
@@ -1775,6 +1943,177 @@ func (sq *Queue) setAllocatingAccepted(appID string) {
 	sq.allocatingAcceptedApps[appID] = true
 }
 
+// applicationUsage is the accounting of an application in the queue hierarchy, used when moving an application.
+type applicationUsage struct {
+	allocated          *resources.Resource // allocated resources, placeholders included
+	pending            *resources.Resource // pending resources
+	preempting         *resources.Resource // resources of allocations being preempted
+	running            bool                // application is tracked as running
+	allocatingAccepted bool                // application in accepted state with placeholders allocated
+	reservations       int                 // reservations of the application
+}
+
+// moveApplication moves the application and its usage from this leaf queue to the target leaf queue. The usage of
+// the queues above the closest common parent does not change. The common parent and the queues below it on the
+// source and target side are locked together while the usage is checked and moved: the move fails without changes
+// if the usage does not fit the maximum resources or maximum running applications of the target queues.
+// Lock free call, the application lock must be held.
+func (sq *Queue) moveApplication(app *Application, target *Queue) error {
+	appID := app.ApplicationID
+	usage := &applicationUsage{
+		allocated:  resources.Add(app.allocatedResource, app.allocatedPlaceholder),
+		pending:    app.pending.Clone(),
+		preempting: resources.NewResource(),
+		running:    app.stateMachine.Is(Running.String()),
+	}
+	for _, alloc := range app.allocations {
+		if alloc.IsPreempted() {
+			usage.preempting.AddTo(alloc.GetAllocatedResource())
+		}
+	}
+
+	ancestor := commonParent(sq, target)
+	sourcePath := sq.pathBelow(ancestor)
+	targetPath := target.pathBelow(ancestor)
+	unlock := lockQueues(ancestor, sourcePath, targetPath)
+	usage.allocatingAccepted = sq.allocatingAcceptedApps[appID]
+	usage.reservations = sq.reservedApps[appID]
+	if err := checkApplicationUsage(targetPath, appID, usage); err != nil {
+		unlock()
+		return err
+	}
+	now := time.Now()
+	for _, queue := range targetPath {
+		queue.addApplicationUsage(now, appID, usage)
+	}
+	for _, queue := range sourcePath {
+		queue.removeApplicationUsage(now, appID, usage)
+	}
+	target.applications[appID] = app
+	if usage.reservations > 0 {
+		target.reservedApps[appID] = usage.reservations
+	}
+	delete(sq.applications, appID)
+	delete(sq.appPriorities, appID)
+	delete(sq.reservedApps, appID)
+	priority := sq.recalculatePriority()
+	target.queueEvents.SendNewApplicationEvent(target.QueuePath, appID, target.daoSnapshot())
+	sq.queueEvents.SendRemoveApplicationEvent(sq.QueuePath, appID, sq.daoSnapshot())
+	unlock()
+
+	target.UpdateApplicationPriority(appID, app.askMaxPriority)
+	sq.parent.UpdateQueuePriority(sq.Name, priority)
+	return nil
+}
+
+// lockQueues locks the ancestor and the queues in the paths below it, and returns the function that unlocks them.
+// The queues are locked top down, ordered by depth and path within the same depth, which is the same order for
+// every move: two moves can never wait on each other.
+func lockQueues(ancestor *Queue, paths ...[]*Queue) func() {
+	var queues []*Queue
+	if ancestor != nil {
+		queues = append(queues, ancestor)
+	}
+	for _, path := range paths {
+		queues = append(queues, path...)
+	}
+	sort.SliceStable(queues, func(i, j int) bool {
+		depthI := strings.Count(queues[i].QueuePath, configs.DOT)
+		depthJ := strings.Count(queues[j].QueuePath, configs.DOT)
+		if depthI != depthJ {
+			return depthI < depthJ
+		}
+		return queues[i].QueuePath < queues[j].QueuePath
+	})
+	for _, queue := range queues {
+		queue.Lock()
+	}
+	return func() {
+		for i := len(queues) - 1; i >= 0; i-- {
+			queues[i].Unlock()
+		}
+	}
+}
+
+// checkApplicationUsage returns an error if the usage of a moved application does not fit one of the queues.
+// Lock free call, the queues must be locked.
+func checkApplicationUsage(path []*Queue, appID string, usage *applicationUsage) error {
+	for _, queue := range path {
+		if !queue.maxResource.FitInMaxUndef(resources.AddOnlyExisting(usage.allocated, queue.allocatedResource)) {
+			return fmt.Errorf("allocations (%v) of application %s put queue '%s' over maximum allocation (%v), current usage (%v)",
+				usage.allocated, appID, queue.QueuePath, queue.maxResource, queue.allocatedResource)
+		}
+		if (usage.running || usage.allocatingAccepted) && queue.maxRunningApps > 0 &&
+			queue.runningApps+uint64(len(queue.allocatingAcceptedApps)+1) > queue.maxRunningApps {
+			return fmt.Errorf("application %s puts queue '%s' over maximum running applications (%d)",
+				appID, queue.QueuePath, queue.maxRunningApps)
+		}
+	}
+	return nil
+}
+
+// addApplicationUsage adds the usage of a moved application to the queue.
+// Lock free call, the queue must be locked.
+func (sq *Queue) addApplicationUsage(now time.Time, appID string, usage *applicationUsage) {
+	sq.allocatedResource = resources.Add(sq.allocatedResource, usage.allocated)
+	sq.usageHistory.Update(now, sq.allocatedResource)
+	sq.pending = resources.Add(sq.pending, usage.pending)
+	sq.preemptingResource = resources.Add(sq.preemptingResource, usage.preempting)
+	if usage.running {
+		sq.runningApps++
+	}
+	if usage.allocatingAccepted {
+		sq.allocatingAcceptedApps[appID] = true
+	}
+	sq.updateAllocatedResourceMetrics()
+	sq.updatePendingResourceMetrics()
+	sq.updatePreemptingResourceMetrics()
+}
+
+// removeApplicationUsage removes the usage of a moved application from the queue.
+// Lock free call, the queue must be locked.
+func (sq *Queue) removeApplicationUsage(now time.Time, appID string, usage *applicationUsage) {
+	sq.allocatedResource = resources.SubEliminateNegative(sq.allocatedResource, usage.allocated)
+	sq.usageHistory.Update(now, sq.allocatedResource)
+	sq.pending = resources.SubEliminateNegative(sq.pending, usage.pending)
+	sq.preemptingResource = resources.SubEliminateNegative(sq.preemptingResource, usage.preempting)
+	if usage.running && sq.runningApps > 0 {
+		sq.runningApps--
+	}
+	delete(sq.allocatingAcceptedApps, appID)
+	// update the metrics before pruning: a nil resource would not update the metrics
+	sq.updateAllocatedResourceMetrics()
+	sq.updatePendingResourceMetrics()
+	sq.updatePreemptingResourceMetrics()
+	sq.allocatedResource.Prune()
+	sq.pending.Prune()
+	sq.preemptingResource.Prune()
+}
+
+// pathBelow returns the queue and its parents up to, but not including, the ancestor.
+func (sq *Queue) pathBelow(ancestor *Queue) []*Queue {
+	var path []*Queue
+	for queue := sq; queue != nil && queue != ancestor; queue = queue.parent {
+		path = append(path, queue)
+	}
+	return path
+}
+
+// commonParent returns the closest queue that is a parent of both queues, nil if the queues are not in the same
+// hierarchy.
+func commonParent(first, second *Queue) *Queue {
+	parents := make(map[*Queue]bool)
+	for queue := first.parent; queue != nil; queue = queue.parent {
+		parents[queue] = true
+	}
+	for queue := second.parent; queue != nil; queue = queue.parent {
+		if parents[queue] {
+			return queue
+		}
+	}
+	return nil
+}
+
 func (sq *Queue) GetPreemptionPolicy() policies.PreemptionPolicy {
 	sq.RLock()
 	defer sq.RUnlock()
@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/G-Research/yunikorn-core/pkg/common/configs"
 	"github.com/G-Research/yunikorn-core/pkg/common/resources"
@@ -85,6 +86,8 @@ func (sq *Queue) DAO(include bool) dao.PartitionQueueDAOInfo {
 	}
 	queueInfo.MaxRunningApps = sq.maxRunningApps
 	queueInfo.RunningApps = sq.runningApps
+	queueInfo.Weight = sq.weight
+	queueInfo.DecayedUsage = sq.usageHistory.Value(time.Now()).DAOMap()
 	queueInfo.AllocatingAcceptedApps = make([]string, 0)
 	for appID, result := range sq.allocatingAcceptedApps {
 		if result {
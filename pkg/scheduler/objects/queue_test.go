@@ -1014,6 +1014,74 @@ func TestGetFairMaxResource(t *testing.T) {
 	}
 }
 
+func TestGetFairMaxResourceWeight(t *testing.T) {
+	root, err := createRootQueue(map[string]string{"memory": "1000"})
+	assert.NilError(t, err, "queue create failed")
+	var parent, heavy, light, other *Queue
+	parent, err = createManagedQueueWithProps(root, "parent", true, nil, map[string]string{configs.QueueWeight: "2"})
+	assert.NilError(t, err, "failed to create parent queue")
+	heavy, err = createManagedQueueWithProps(parent, "heavy", false, nil, map[string]string{configs.QueueWeight: "3"})
+	assert.NilError(t, err, "failed to create leaf queue")
+	light, err = createManagedQueue(parent, "light", false, nil)
+	assert.NilError(t, err, "failed to create leaf queue")
+
+	// the only child of the root uses the full root fair max, the weight is relative to the siblings
+	assert.DeepEqual(t, parent.GetFairMaxResource(), resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 1000}))
+	// average weight of the siblings is 2
+	assert.DeepEqual(t, heavy.GetFairMaxResource(), resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 1500}))
+	assert.DeepEqual(t, light.GetFairMaxResource(), resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 500}))
+
+	// a sibling for the parent changes the share of the whole sub tree
+	other, err = createManagedQueue(root, "other", false, nil)
+	assert.NilError(t, err, "failed to create leaf queue")
+	assert.DeepEqual(t, other.GetFairMaxResource(), resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 666}))
+	assert.DeepEqual(t, light.GetFairMaxResource(), resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 666}))
+}
+
+func TestGetRelativeWeights(t *testing.T) {
+	root, err := createRootQueue(nil)
+	assert.NilError(t, err, "queue create failed")
+	var first, second *Queue
+	first, err = createManagedQueue(root, "first", false, nil)
+	assert.NilError(t, err, "failed to create leaf queue")
+	second, err = createManagedQueueWithProps(root, "second", false, nil, map[string]string{configs.QueueWeight: "2"})
+	assert.NilError(t, err, "failed to create leaf queue")
+
+	assert.Assert(t, getRelativeWeights(nil) == nil, "no queues should not have weights")
+	assert.Assert(t, getRelativeWeights(map[string]*Queue{"first": first}) == nil, "single queue should not have weights")
+	weights := getRelativeWeights(root.GetCopyOfChildren())
+	assert.DeepEqual(t, weights, map[*Queue]float64{first: 2.0 / 3, second: 4.0 / 3})
+
+	// the same weight everywhere does not change anything
+	second.weight = configs.DefaultQueueWeight
+	assert.Assert(t, getRelativeWeights(root.GetCopyOfChildren()) == nil, "equal weights should not be returned")
+}
+
+func TestQueueWeight(t *testing.T) {
+	root, err := createRootQueue(nil)
+	assert.NilError(t, err, "failed to create basic root queue: %v", err)
+	assert.Equal(t, configs.DefaultQueueWeight, root.GetWeight())
+
+	// weight is not inherited by the children
+	var parent, leaf *Queue
+	parent, err = createManagedQueueWithProps(root, "parent", true, nil, map[string]string{configs.QueueWeight: "2.5"})
+	assert.NilError(t, err, "failed to create queue: %v", err)
+	assert.Equal(t, 2.5, parent.GetWeight())
+	assert.Equal(t, 2.5, parent.GetPartitionQueueDAOInfo(false).Weight)
+	leaf, err = createManagedQueue(parent, "leaf", false, nil)
+	assert.NilError(t, err, "failed to create queue: %v", err)
+	assert.Equal(t, configs.DefaultQueueWeight, leaf.GetWeight())
+	assert.Equal(t, "1", leaf.GetPartitionQueueDAOInfo(false).Properties[configs.QueueWeight])
+
+	for _, value := range []string{"0", "-1", "NaN", "Inf", "heavy"} {
+		_, err = queueWeight(value)
+		assert.Assert(t, err != nil, "weight %s should be rejected", value)
+	}
+	leaf, err = createManagedQueueWithProps(parent, "invalid", false, nil, map[string]string{configs.QueueWeight: "0"})
+	assert.NilError(t, err, "failed to create queue: %v", err)
+	assert.Equal(t, configs.DefaultQueueWeight, leaf.GetWeight())
+}
+
 func TestGetMaxResource(t *testing.T) {
 	// create the root
 	root, err := createRootQueue(nil)
@@ -1639,6 +1707,78 @@ func TestSupportTaskGroup(t *testing.T) {
 	assert.Assert(t, !leaf.SupportTaskGroup(), "leaf queue (FAIR policy) should not support task group")
 }
 
+func TestQueueSortPolicy(t *testing.T) {
+	root, err := createRootQueue(nil)
+	assert.NilError(t, err, "failed to create basic root queue: %v", err)
+	assert.Equal(t, policies.FairSortPolicy, root.getSortType(), "parent queues should default to fair")
+
+	// queue sort policy is inherited by the children but only used by parent queues
+	properties := map[string]string{configs.QueueSortPolicy: "drf"}
+	var parent, leaf *Queue
+	parent, err = createManagedQueueWithProps(root, "parent", true, nil, properties)
+	assert.NilError(t, err, "failed to create queue: %v", err)
+	assert.Equal(t, policies.DrfSortPolicy, parent.getSortType())
+	leaf, err = createManagedQueue(parent, "leaf", false, nil)
+	assert.NilError(t, err, "failed to create queue: %v", err)
+	assert.Equal(t, policies.FifoSortPolicy, leaf.getSortType())
+
+	// fifo cannot be used to sort queues
+	properties = map[string]string{configs.QueueSortPolicy: "fifo"}
+	parent, err = createManagedQueueWithProps(root, "fifo", true, nil, properties)
+	assert.NilError(t, err, "failed to create queue: %v", err)
+	assert.Equal(t, policies.FairSortPolicy, parent.getSortType())
+
+	properties = map[string]string{configs.ApplicationSortPolicy: "drf"}
+	leaf, err = createManagedQueueWithProps(root, "drf", false, nil, properties)
+	assert.NilError(t, err, "failed to create queue: %v", err)
+	assert.Equal(t, policies.DrfSortPolicy, leaf.getSortType())
+	assert.Assert(t, !leaf.SupportTaskGroup(), "leaf queue (DRF policy) should not support task group")
+
+	properties = map[string]string{configs.QueueSortPolicy: "history", configs.ApplicationSortPolicy: "history"}
+	parent, err = createManagedQueueWithProps(root, "history", true, nil, properties)
+	assert.NilError(t, err, "failed to create queue: %v", err)
+	assert.Equal(t, policies.UsageHistorySortPolicy, parent.getSortType())
+	leaf, err = createManagedQueue(parent, "leaf", false, nil)
+	assert.NilError(t, err, "failed to create queue: %v", err)
+	assert.Equal(t, policies.UsageHistorySortPolicy, leaf.getSortType())
+
+	// user fair only sorts applications
+	properties = map[string]string{configs.QueueSortPolicy: "userfair", configs.ApplicationSortPolicy: "userfair"}
+	parent, err = createManagedQueueWithProps(root, "userfair", true, nil, properties)
+	assert.NilError(t, err, "failed to create queue: %v", err)
+	assert.Equal(t, policies.FairSortPolicy, parent.getSortType())
+	leaf, err = createManagedQueue(parent, "leaf", false, nil)
+	assert.NilError(t, err, "failed to create queue: %v", err)
+	assert.Equal(t, policies.UserFairSortPolicy, leaf.getSortType())
+	assert.Assert(t, !leaf.SupportTaskGroup(), "leaf queue (user fair policy) should not support task group")
+}
+
+func TestQueueDecayedUsage(t *testing.T) {
+	resources.SetUsageHalfLife(0)
+	defer resources.SetUsageHalfLife(resources.DefaultUsageHalfLife)
+	root, err := createRootQueue(nil)
+	assert.NilError(t, err, "failed to create basic root queue: %v", err)
+	var leaf *Queue
+	leaf, err = createManagedQueue(root, "leaf", false, nil)
+	assert.NilError(t, err, "failed to create queue: %v", err)
+	assert.Assert(t, resources.IsZero(leaf.GetDecayedUsage(time.Now())), "new queue should not have history")
+
+	usage := resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 2})
+	leaf.IncAllocatedResource(usage)
+	expected := resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 20})
+	for _, queue := range []*Queue{root, leaf} {
+		assert.Assert(t, resources.Equals(expected, queue.GetDecayedUsage(time.Now().Add(10*time.Second))),
+			"unexpected decayed usage for queue %s", queue.QueuePath)
+	}
+
+	// usage stops adding up after the release, the history is kept
+	leaf.usageHistory.Update(time.Now().Add(-10*time.Second), usage)
+	assert.NilError(t, leaf.DecAllocatedResource(usage))
+	later := time.Now().Add(time.Hour)
+	assert.Assert(t, resources.Equals(expected, leaf.GetDecayedUsage(later)), "usage should not add up without allocations")
+	assert.DeepEqual(t, leaf.GetDecayedUsage(later).DAOMap(), leaf.GetPartitionQueueDAOInfo(false).DecayedUsage)
+}
+
 func TestGetPartitionQueueDAOInfo(t *testing.T) {
 	root, err := createRootQueue(nil)
 	assert.NilError(t, err, "failed to create basic root queue: %v", err)
@@ -1786,6 +1926,48 @@ func TestPreemptingResource(t *testing.T) {
 	assert.Check(t, resources.IsZero(queue.GetPreemptingResource()), "final value should be zero")
 }
 
+func TestQueueWebhookURLs(t *testing.T) {
+	root, err := createRootQueue(nil)
+	assert.NilError(t, err, "failed to create basic root queue")
+	assert.Assert(t, root.GetWebhookURLs() == nil, "root queue should not have webhooks")
+
+	props := map[string]string{configs.ApplicationWebhookURL: "http://hooks.example.com/a, https://other.example.com/b"}
+	var parent *Queue
+	parent, err = createManagedQueueWithProps(root, "parent", true, nil, props)
+	assert.NilError(t, err, "failed to create parent queue")
+	expected := []string{"http://hooks.example.com/a", "https://other.example.com/b"}
+	assert.DeepEqual(t, expected, parent.GetWebhookURLs())
+
+	// inherited by the child
+	var leaf *Queue
+	leaf, err = createManagedQueue(parent, "leaf", false, nil)
+	assert.NilError(t, err, "failed to create leaf queue")
+	assert.DeepEqual(t, expected, leaf.GetWebhookURLs())
+
+	// invalid URLs are skipped
+	props = map[string]string{configs.ApplicationWebhookURL: "ftp://hooks.example.com,http://,:invalid,,https://hooks.example.com/c"}
+	leaf, err = createManagedQueueWithProps(root, "leaf", false, nil, props)
+	assert.NilError(t, err, "failed to create leaf queue")
+	assert.DeepEqual(t, []string{"https://hooks.example.com/c"}, leaf.GetWebhookURLs())
+
+	// removed on property update
+	leaf.properties = map[string]string{}
+	leaf.UpdateQueueProperties()
+	assert.Assert(t, leaf.GetWebhookURLs() == nil, "webhooks should have been removed")
+}
+
+func TestParseWebhookURLs(t *testing.T) {
+	urls, err := parseWebhookURLs("")
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(urls))
+	urls, err = parseWebhookURLs(" https://hooks.example.com/a ")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"https://hooks.example.com/a"}, urls)
+	urls, err = parseWebhookURLs("hooks.example.com,https://hooks.example.com/a")
+	assert.ErrorContains(t, err, "invalid application.webhook.url value: hooks.example.com")
+	assert.DeepEqual(t, []string{"https://hooks.example.com/a"}, urls)
+}
+
 func TestPreemptionDelay(t *testing.T) {
 	queue, err := createManagedQueueWithProps(nil, "tmp", true, nil, nil)
 	assert.NilError(t, err, "failed to create basic queue queue: %v", err)
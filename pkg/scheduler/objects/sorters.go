@@ -25,17 +25,30 @@ import (
 	"github.com/G-Research/yunikorn-core/pkg/common/resources"
 	"github.com/G-Research/yunikorn-core/pkg/metrics"
 	"github.com/G-Research/yunikorn-core/pkg/scheduler/policies"
+	"github.com/G-Research/yunikorn-core/pkg/scheduler/ugm"
 )
 
-func sortQueue(queues []*Queue, fairMaxResources []*resources.Resource, sortType policies.SortPolicy, considerPriority bool) {
+// sortQueue sorts the queues using the sort policy. The weights are the relative weights of the queues, nil if all
+// queues have the same weight: the fair max resources already include the weights, the dominant shares of the drf and
+// usage history policies are divided by the weights.
+func sortQueue(queues []*Queue, fairMaxResources []*resources.Resource, sortType policies.SortPolicy, considerPriority bool, capacity *resources.Resource, weights map[*Queue]float64) {
 	sortingStart := time.Now()
-	if sortType == policies.FairSortPolicy {
+	switch sortType {
+	case policies.FairSortPolicy:
 		if considerPriority {
 			sortQueuesByPriorityAndFairness(queues, fairMaxResources)
 		} else {
 			sortQueuesByFairnessAndPriority(queues, fairMaxResources)
 		}
-	} else {
+	case policies.DrfSortPolicy:
+		if considerPriority {
+			sortQueuesByPriorityAndDominantShare(queues, capacity, weights)
+		} else {
+			sortQueuesByDominantShareAndPriority(queues, capacity, weights)
+		}
+	case policies.UsageHistorySortPolicy:
+		sortQueuesByUsageHistory(queues, capacity, considerPriority, weights)
+	default:
 		if considerPriority {
 			sortQueuesByPriority(queues)
 		}
@@ -98,6 +111,94 @@ func sortQueuesByFairnessAndPriority(queues []*Queue, fairMaxResources []*resour
 	})
 }
 
+// sortQueuesByPriorityAndDominantShare sorts the queues on priority first and then on the weighted dominant share of
+// the allocated resources compared to the capacity.
+func sortQueuesByPriorityAndDominantShare(queues []*Queue, capacity *resources.Resource, weights map[*Queue]float64) {
+	shares := make(map[*Queue]float64, len(queues))
+	for _, queue := range queues {
+		shares[queue] = weightedShare(queue.GetAllocatedResource(), capacity, weights[queue])
+	}
+	sort.SliceStable(queues, func(i, j int) bool {
+		l := queues[i]
+		r := queues[j]
+		lPriority := l.GetCurrentPriority()
+		rPriority := r.GetCurrentPriority()
+		if lPriority > rPriority {
+			return true
+		}
+		if lPriority < rPriority {
+			return false
+		}
+		if shares[l] == shares[r] {
+			return resources.StrictlyGreaterThan(resources.Sub(l.GetPendingResource(), r.GetPendingResource()), resources.Zero)
+		}
+		return shares[l] < shares[r]
+	})
+}
+
+// sortQueuesByDominantShareAndPriority sorts the queues on the weighted dominant share of the allocated resources
+// compared to the capacity first and then on priority.
+func sortQueuesByDominantShareAndPriority(queues []*Queue, capacity *resources.Resource, weights map[*Queue]float64) {
+	shares := make(map[*Queue]float64, len(queues))
+	for _, queue := range queues {
+		shares[queue] = weightedShare(queue.GetAllocatedResource(), capacity, weights[queue])
+	}
+	sort.SliceStable(queues, func(i, j int) bool {
+		l := queues[i]
+		r := queues[j]
+		if shares[l] == shares[r] {
+			lPriority := l.GetCurrentPriority()
+			rPriority := r.GetCurrentPriority()
+			if lPriority > rPriority {
+				return true
+			}
+			if lPriority < rPriority {
+				return false
+			}
+			return resources.StrictlyGreaterThan(resources.Sub(l.GetPendingResource(), r.GetPendingResource()), resources.Zero)
+		}
+		return shares[l] < shares[r]
+	})
+}
+
+// sortQueuesByUsageHistory sorts the queues on the weighted dominant share of the decayed usage compared to the
+// capacity. Equal shares are sorted on priority and pending resources. With priority considered the priority is
+// compared first.
+func sortQueuesByUsageHistory(queues []*Queue, capacity *resources.Resource, considerPriority bool, weights map[*Queue]float64) {
+	// the decayed usage changes over time: use one value per queue for the whole sort
+	now := time.Now()
+	shares := make(map[*Queue]float64, len(queues))
+	for _, queue := range queues {
+		shares[queue] = weightedShare(queue.GetDecayedUsage(now), capacity, weights[queue])
+	}
+	sort.SliceStable(queues, func(i, j int) bool {
+		l := queues[i]
+		r := queues[j]
+		lPriority := l.GetCurrentPriority()
+		rPriority := r.GetCurrentPriority()
+		if considerPriority && lPriority != rPriority {
+			return lPriority > rPriority
+		}
+		if shares[l] != shares[r] {
+			return shares[l] < shares[r]
+		}
+		if lPriority != rPriority {
+			return lPriority > rPriority
+		}
+		return resources.StrictlyGreaterThan(resources.Sub(l.GetPendingResource(), r.GetPendingResource()), resources.Zero)
+	})
+}
+
+// weightedShare returns the dominant share of the usage compared to the capacity divided by the relative weight.
+// A weight of 0 means the queue has no relative weight and the share is not changed.
+func weightedShare(usage, capacity *resources.Resource, weight float64) float64 {
+	share := usage.DominantShare(capacity)
+	if weight > 0 {
+		share /= weight
+	}
+	return share
+}
+
 func sortApplications(apps map[string]*Application, sortType policies.SortPolicy, considerPriority bool, globalResource *resources.Resource) []*Application {
 	sortingStart := time.Now()
 	sortedApps := filterOnPendingResources(apps)
@@ -108,6 +209,16 @@ func sortApplications(apps map[string]*Application, sortType policies.SortPolicy
 		} else {
 			sortApplicationsByFairnessAndPriority(sortedApps, globalResource)
 		}
+	case policies.DrfSortPolicy:
+		if considerPriority {
+			sortApplicationsByPriorityAndDominantShare(sortedApps, globalResource)
+		} else {
+			sortApplicationsByDominantShareAndPriority(sortedApps, globalResource)
+		}
+	case policies.UsageHistorySortPolicy:
+		sortApplicationsByUserUsageHistory(sortedApps, globalResource, considerPriority)
+	case policies.UserFairSortPolicy:
+		sortApplicationsByUserFairness(sortedApps, globalResource, considerPriority)
 	case policies.FifoSortPolicy:
 		if considerPriority {
 			sortApplicationsByPriorityAndSubmissionTime(sortedApps)
@@ -146,6 +257,73 @@ func sortApplicationsByPriorityAndFairness(sortedApps []*Application, globalReso
 	})
 }
 
+// sortApplicationsByDominantShareAndPriority sorts the applications on the dominant share of the allocated resources
+// compared to the capacity, then on priority and submission time.
+func sortApplicationsByDominantShareAndPriority(sortedApps []*Application, capacity *resources.Resource) {
+	sort.SliceStable(sortedApps, func(i, j int) bool {
+		l := sortedApps[i]
+		r := sortedApps[j]
+		if comp := resources.CompDominantShare(l.GetAllocatedResource(), r.GetAllocatedResource(), capacity); comp != 0 {
+			return comp < 0
+		}
+		leftPriority := l.GetAskMaxPriority()
+		rightPriority := r.GetAskMaxPriority()
+		if leftPriority != rightPriority {
+			return leftPriority > rightPriority
+		}
+		return l.SubmissionTime.Before(r.SubmissionTime)
+	})
+}
+
+// sortApplicationsByPriorityAndDominantShare sorts the applications on priority, then on the dominant share of the
+// allocated resources compared to the capacity and submission time.
+func sortApplicationsByPriorityAndDominantShare(sortedApps []*Application, capacity *resources.Resource) {
+	sort.SliceStable(sortedApps, func(i, j int) bool {
+		l := sortedApps[i]
+		r := sortedApps[j]
+		leftPriority := l.GetAskMaxPriority()
+		rightPriority := r.GetAskMaxPriority()
+		if leftPriority != rightPriority {
+			return leftPriority > rightPriority
+		}
+		if comp := resources.CompDominantShare(l.GetAllocatedResource(), r.GetAllocatedResource(), capacity); comp != 0 {
+			return comp < 0
+		}
+		return l.SubmissionTime.Before(r.SubmissionTime)
+	})
+}
+
+// sortApplicationsByUserUsageHistory sorts the applications on the dominant share of the decayed usage of their user
+// compared to the capacity. Equal shares are sorted on priority and submission time. With priority considered the
+// priority is compared first.
+func sortApplicationsByUserUsageHistory(sortedApps []*Application, capacity *resources.Resource, considerPriority bool) {
+	// the decayed usage changes over time: use one value per user for the whole sort
+	now := time.Now()
+	usage := make(map[string]*resources.Resource)
+	for _, app := range sortedApps {
+		userName := app.GetUser().User
+		if _, ok := usage[userName]; !ok {
+			usage[userName] = ugm.GetUserManager().GetUserDecayedUsage(userName, now)
+		}
+	}
+	sort.SliceStable(sortedApps, func(i, j int) bool {
+		l := sortedApps[i]
+		r := sortedApps[j]
+		leftPriority := l.GetAskMaxPriority()
+		rightPriority := r.GetAskMaxPriority()
+		if considerPriority && leftPriority != rightPriority {
+			return leftPriority > rightPriority
+		}
+		if comp := resources.CompDominantShare(usage[l.GetUser().User], usage[r.GetUser().User], capacity); comp != 0 {
+			return comp < 0
+		}
+		if leftPriority != rightPriority {
+			return leftPriority > rightPriority
+		}
+		return l.SubmissionTime.Before(r.SubmissionTime)
+	})
+}
+
 func sortApplicationsBySubmissionTimeAndPriority(sortedApps []*Application) {
 	sort.SliceStable(sortedApps, func(i, j int) bool {
 		l := sortedApps[i]
@@ -176,6 +354,39 @@ func sortApplicationsByPriorityAndSubmissionTime(sortedApps []*Application) {
 	})
 }
 
+// sortApplicationsByUserFairness sorts the applications on the usage of their user in the queue compared to the
+// capacity, the applications of the user with the lowest usage first. The applications of a user are sorted on
+// submission time, with priority considered on priority first. Users with equal usage take turns: the first
+// application of each user comes before the second application of any of them.
+func sortApplicationsByUserFairness(sortedApps []*Application, capacity *resources.Resource, considerPriority bool) {
+	if considerPriority {
+		sortApplicationsByPriorityAndSubmissionTime(sortedApps)
+	} else {
+		sortApplicationsBySubmissionTimeAndPriority(sortedApps)
+	}
+	// the usage changes while sorting: use one value per user for the whole sort
+	usage := make(map[string]*resources.Resource)
+	// the turn of each application is its position in the sorted applications of its user
+	turns := make(map[*Application]int, len(sortedApps))
+	userApps := make(map[string]int)
+	for _, app := range sortedApps {
+		userName := app.GetUser().User
+		if _, ok := usage[userName]; !ok {
+			usage[userName] = ugm.GetUserManager().GetUserQueueUsage(userName, app.GetQueuePath())
+		}
+		turns[app] = userApps[userName]
+		userApps[userName]++
+	}
+	sort.SliceStable(sortedApps, func(i, j int) bool {
+		l := sortedApps[i]
+		r := sortedApps[j]
+		if comp := resources.CompUsageRatio(usage[l.GetUser().User], usage[r.GetUser().User], capacity); comp != 0 {
+			return comp < 0
+		}
+		return turns[l] < turns[r]
+	})
+}
+
 func filterOnPendingResources(apps map[string]*Application) []*Application {
 	filteredApps := make([]*Application, 0)
 	for _, app := range apps {
@@ -26,8 +26,10 @@ import (
 
 	"gotest.tools/v3/assert"
 
+	"github.com/G-Research/yunikorn-core/pkg/common/configs"
 	"github.com/G-Research/yunikorn-core/pkg/common/resources"
 	"github.com/G-Research/yunikorn-core/pkg/scheduler/policies"
+	"github.com/G-Research/yunikorn-core/pkg/scheduler/ugm"
 )
 
 // verify queue ordering is working when explicity guarantees are provided
@@ -70,49 +72,49 @@ func TestSortQueuesWithGuarantees(t *testing.T) {
 	// fifo
 	queues = []*Queue{q0, q1, q2, q3}
 
-	sortQueue(queues, fairMaxResources, policies.FifoSortPolicy, false)
+	sortQueue(queues, fairMaxResources, policies.FifoSortPolicy, false, nil, nil)
 	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q0, q1, q2, q3}), "fifo first")
 
 	queues = []*Queue{q0, q1, q2, q3}
-	sortQueue(queues, fairMaxResources, policies.FifoSortPolicy, true)
+	sortQueue(queues, fairMaxResources, policies.FifoSortPolicy, true, nil, nil)
 	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q3, q0, q1, q2}), "fifo first - priority")
 
 	// fifo - different starting order
 	queues = []*Queue{q1, q3, q0, q2}
-	sortQueue(queues, fairMaxResources, policies.FifoSortPolicy, false)
+	sortQueue(queues, fairMaxResources, policies.FifoSortPolicy, false, nil, nil)
 	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q1, q3, q0, q2}), "fifo second")
 
 	queues = []*Queue{q1, q3, q0, q2}
-	sortQueue(queues, fairMaxResources, policies.FifoSortPolicy, true)
+	sortQueue(queues, fairMaxResources, policies.FifoSortPolicy, true, nil, nil)
 	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q3, q1, q0, q2}), "fifo second - priority")
 
 	// fairness ratios: q0:300/500=0.6, q1:200/300=0.67, q2:100/200=0.5, q3:100/200=0.5
 	queues = []*Queue{q0, q1, q2, q3}
-	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, false)
+	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, false, nil, nil)
 	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q3, q2, q0, q1}), "fair first")
 
 	queues = []*Queue{q0, q1, q2, q3}
-	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, true)
+	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, true, nil, nil)
 	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q3, q2, q0, q1}), "fair first - priority")
 
 	// fairness ratios: q0:200/500=0.4, q1:300/300=1, q2:100/200=0.5, q3:100/200=0.5
 	q0.allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 200, "vcore": 200})
 	q1.allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 300, "vcore": 300})
 	queues = []*Queue{q0, q1, q2, q3}
-	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, false)
+	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, false, nil, nil)
 	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q0, q3, q2, q1}), "fair second")
 	queues = []*Queue{q0, q1, q2, q3}
-	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, true)
+	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, true, nil, nil)
 	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q3, q0, q2, q1}), "fair second - priority")
 
 	// fairness ratios: q0:150/500=0.3, q1:120/300=0.4, q2:100/200=0.5, q3:100/200=0.5
 	q0.allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 150, "vcore": 150})
 	q1.allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 120, "vcore": 120})
 	queues = []*Queue{q0, q1, q2, q3}
-	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, false)
+	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, false, nil, nil)
 	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q0, q1, q3, q2}), "fair third")
 	queues = []*Queue{q0, q1, q2, q3}
-	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, true)
+	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, true, nil, nil)
 	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q3, q0, q1, q2}), "fair third - priority")
 
 	// fairness ratios: q0:400/800=0.5, q1:200/400= 0.5, q2:100/200=0.5, q3:100/200=0.5
@@ -121,7 +123,7 @@ func TestSortQueuesWithGuarantees(t *testing.T) {
 	q1.guaranteedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 400, "vcore": 300})
 	q1.allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 200, "vcore": 150})
 	queues = []*Queue{q0, q1, q2, q3}
-	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, false)
+	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, false, nil, nil)
 	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q3, q0, q1, q2}), "fair - pending resource")
 }
 
@@ -157,19 +159,19 @@ func TestSortQueuesNoGuarantees(t *testing.T) {
 		resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 1000, "vcore": 1000}),
 		resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 1000, "vcore": 1000}),
 	}
-	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, false)
+	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, false, nil, nil)
 	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q3, q2, q1, q0}), "fair no gaurantees first")
 
-	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, true)
+	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, true, nil, nil)
 	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q3, q2, q0, q1}), "fair no gaurantees first - priority")
 
 	q0.allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 200, "vcore": 200})
 	q1.allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 300, "vcore": 300})
 
-	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, false)
+	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, false, nil, nil)
 	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q3, q2, q0, q1}), "fair no gaurantees second")
 
-	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, true)
+	sortQueue(queues, fairMaxResources, policies.FairSortPolicy, true, nil, nil)
 	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q3, q2, q0, q1}), "fair no limit second - priority")
 }
 
@@ -417,3 +419,357 @@ func TestSortBySubmissionTime(t *testing.T) {
 	list = sortApplications(input, policies.FifoSortPolicy, true, nil)
 	assertAppList(t, list, []int{3, 2, 1, 0}, "sort by submission time")
 }
+
+func TestSortQueuesDrf(t *testing.T) {
+	capacity := resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 1000, "vcore": 100})
+	root, err := createRootQueue(nil)
+	assert.NilError(t, err, "queue create failed")
+	var q0, q1, q2 *Queue
+	// dominant share 0.3 (memory)
+	q0, err = createManagedQueue(root, "q0", false, nil)
+	assert.NilError(t, err, "failed to create leaf queue")
+	q0.allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 300, "vcore": 10})
+	q0.currentPriority = 1
+	// dominant share 0.2 (vcore)
+	q1, err = createManagedQueue(root, "q1", false, nil)
+	assert.NilError(t, err, "failed to create leaf queue")
+	q1.allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 100, "vcore": 20})
+	// dominant share 0.3 (vcore)
+	q2, err = createManagedQueue(root, "q2", false, nil)
+	assert.NilError(t, err, "failed to create leaf queue")
+	q2.allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 100, "vcore": 30})
+	q2.currentPriority = 2
+
+	queues := []*Queue{q0, q1, q2}
+	sortQueue(queues, nil, policies.DrfSortPolicy, false, capacity, nil)
+	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q1, q2, q0}), "drf")
+	sortQueue(queues, nil, policies.DrfSortPolicy, true, capacity, nil)
+	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q2, q0, q1}), "drf - priority")
+
+	// fair sorting compares the shares of all types: q0 (0.3, 0.1) and q2 (0.3, 0.1) are equal, q1 is the smallest
+	// with DRF only the dominant share counts
+	q2.allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 300, "vcore": 30})
+	sortQueue(queues, nil, policies.DrfSortPolicy, false, capacity, nil)
+	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q1, q2, q0}), "drf equal share, priority first")
+
+	// weighted shares: q0 0.2, q1 0.4, q2 0.1
+	weights := map[*Queue]float64{q0: 1.5, q1: 0.5, q2: 3}
+	sortQueue(queues, nil, policies.DrfSortPolicy, false, capacity, weights)
+	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q2, q0, q1}), "drf - weights")
+}
+
+func TestSortAppsDrf(t *testing.T) {
+	capacity := resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 1000, "vcore": 100})
+	input := make(map[string]*Application, 4)
+	for i := 0; i < 4; i++ {
+		num := strconv.Itoa(i)
+		appID := "app-" + num
+		app := newApplication(appID, "partition", "queue")
+		app.pending = capacity
+		app.SubmissionTime = time.Unix(int64(i), 0)
+		input[appID] = app
+	}
+	// dominant shares: app-0 0.4 (memory), app-1 0.1 (vcore), app-2 0.3 (memory), app-3 0.3 (vcore)
+	input["app-0"].allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 400, "vcore": 10})
+	input["app-1"].allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 50, "vcore": 10})
+	input["app-2"].allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 300, "vcore": 5})
+	input["app-3"].allocatedResource = resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 100, "vcore": 30})
+	list := sortApplications(input, policies.DrfSortPolicy, false, capacity)
+	assertAppList(t, list, []int{3, 0, 1, 2}, "drf, equal share on submission time")
+
+	input["app-3"].askMaxPriority = 1
+	list = sortApplications(input, policies.DrfSortPolicy, false, capacity)
+	assertAppList(t, list, []int{3, 0, 2, 1}, "drf, equal share on priority")
+
+	input["app-0"].askMaxPriority = 2
+	list = sortApplications(input, policies.DrfSortPolicy, true, capacity)
+	assertAppList(t, list, []int{0, 2, 3, 1}, "drf - priority")
+}
+
+// TestDrfConvergenceApps allocates one task at a time to the first application in DRF order that fits, as in the
+// example of the DRF paper: the CPU and memory heavy applications end up with equal dominant shares.
+func TestDrfConvergenceApps(t *testing.T) {
+	capacity := resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 9, "memory": 18})
+	tasks := map[string]*resources.Resource{
+		"app-mem": resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 1, "memory": 4}),
+		"app-cpu": resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 3, "memory": 1}),
+	}
+	input := make(map[string]*Application, len(tasks))
+	for appID := range tasks {
+		app := newApplication(appID, "partition", "queue")
+		app.pending = capacity
+		app.allocatedResource = resources.NewResource()
+		input[appID] = app
+	}
+	allocated := fillInOrder(capacity.Clone(), func() []string {
+		var names []string
+		for _, app := range sortApplications(input, policies.DrfSortPolicy, false, capacity) {
+			names = append(names, app.ApplicationID)
+		}
+		return names
+	}, tasks, func(name string, task *resources.Resource) {
+		input[name].allocatedResource.AddTo(task)
+	})
+	assert.Equal(t, 3, allocated["app-mem"], "memory heavy application tasks")
+	assert.Equal(t, 2, allocated["app-cpu"], "cpu heavy application tasks")
+	assert.Equal(t, input["app-mem"].allocatedResource.DominantShare(capacity), input["app-cpu"].allocatedResource.DominantShare(capacity))
+}
+
+// TestDrfConvergenceQueues runs CPU, memory and GPU heavy tenants in queues below a parent with the DRF queue sort
+// policy. The capacity of the partition is taken from the root queue.
+func TestDrfConvergenceQueues(t *testing.T) {
+	root, err := createRootQueue(map[string]string{"vcore": "12", "memory": "48", "gpu": "6"})
+	assert.NilError(t, err, "queue create failed")
+	var parent *Queue
+	parent, err = createManagedQueueWithProps(root, "parent", true, nil, map[string]string{configs.QueueSortPolicy: "drf"})
+	assert.NilError(t, err, "failed to create parent queue")
+	assert.Equal(t, policies.DrfSortPolicy, parent.getSortType())
+	tasks := map[string]*resources.Resource{
+		"cpu": resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 2000, "memory": 2}),
+		"mem": resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 1000, "memory": 8}),
+		"gpu": resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 1000, "memory": 2, "gpu": 1}),
+	}
+	leafs := make(map[string]*Queue, len(tasks))
+	for name := range tasks {
+		var leaf *Queue
+		leaf, err = createManagedQueue(parent, name, false, nil)
+		assert.NilError(t, err, "failed to create leaf queue")
+		leaf.pending = root.GetMaxResource()
+		leaf.allocatedResource = resources.NewResource()
+		leafs[name] = leaf
+	}
+	// one tenant already runs: the others catch up before it gets more
+	leafs["mem"].allocatedResource.AddTo(resources.Multiply(tasks["mem"], 2))
+	allocated := fillInOrder(resources.Sub(root.GetMaxResource(), leafs["mem"].allocatedResource), func() []string {
+		var names []string
+		for _, queue := range parent.sortQueues() {
+			names = append(names, queue.Name)
+		}
+		return names
+	}, tasks, func(name string, task *resources.Resource) {
+		leafs[name].allocatedResource.AddTo(task)
+	})
+	assert.Equal(t, 3, allocated["cpu"], "cpu heavy queue tasks")
+	assert.Equal(t, 1, allocated["mem"], "memory heavy queue tasks")
+	assert.Equal(t, 3, allocated["gpu"], "gpu heavy queue tasks")
+	for name, leaf := range leafs {
+		assert.Equal(t, 0.5, leaf.GetAllocatedResource().DominantShare(root.GetMaxResource()), "dominant share of queue %s", name)
+	}
+}
+
+func TestSortQueuesUsageHistory(t *testing.T) {
+	resources.SetUsageHalfLife(0)
+	defer resources.SetUsageHalfLife(resources.DefaultUsageHalfLife)
+	capacity := resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 1000, "vcore": 100})
+	root, err := createRootQueue(nil)
+	assert.NilError(t, err, "queue create failed")
+	// usage over the last 100 seconds, the current allocation does not matter
+	now := time.Now()
+	history := map[string]*resources.Resource{
+		// dominant share 30 seconds (memory)
+		"q0": resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 300, "vcore": 10}),
+		// dominant share 20 seconds (vcore)
+		"q1": resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 100, "vcore": 20}),
+		// dominant share 30 seconds (vcore)
+		"q2": resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 100, "vcore": 30}),
+	}
+	var queues []*Queue
+	for _, name := range []string{"q0", "q1", "q2"} {
+		var queue *Queue
+		queue, err = createManagedQueue(root, name, false, nil)
+		assert.NilError(t, err, "failed to create leaf queue")
+		queue.usageHistory.Update(now.Add(-100*time.Second), history[name])
+		queue.usageHistory.Update(now, nil)
+		queues = append(queues, queue)
+	}
+	q0, q1, q2 := queues[0], queues[1], queues[2]
+	q0.currentPriority = 1
+	q1.allocatedResource = capacity.Clone()
+	q2.currentPriority = 2
+
+	sortQueue(queues, nil, policies.UsageHistorySortPolicy, false, capacity, nil)
+	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q1, q2, q0}), "history")
+	sortQueue(queues, nil, policies.UsageHistorySortPolicy, true, capacity, nil)
+	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q2, q0, q1}), "history - priority")
+
+	// weighted shares: q0 0.2, q1 0.4, q2 0.1
+	weights := map[*Queue]float64{q0: 1.5, q1: 0.5, q2: 3}
+	sortQueue(queues, nil, policies.UsageHistorySortPolicy, false, capacity, weights)
+	assert.Equal(t, queueNames(queues), queueNames([]*Queue{q2, q0, q1}), "history - weights")
+}
+
+func TestSortAppsUsageHistory(t *testing.T) {
+	capacity := resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 10000000})
+	manager := ugm.GetUserManager()
+	manager.ClearUserTrackers()
+	defer manager.ClearUserTrackers()
+	// app-0 runs for a heavy user, app-1 for a light user, app-2 and app-3 for users without history
+	users := []string{"heavy", "light", "new", "newer"}
+	input := make(map[string]*Application, len(users))
+	for i, user := range users {
+		appID := "app-" + strconv.Itoa(i)
+		app := newApplicationWithUserGroup(appID, "partition", "root.queue", user, []string{})
+		app.pending = capacity
+		app.SubmissionTime = time.Unix(int64(i), 0)
+		input[appID] = app
+	}
+	manager.IncreaseTrackedResource("root.queue", "app-0", resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 1000000}), input["app-0"].user)
+	manager.IncreaseTrackedResource("root.queue", "app-1", resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 1000}), input["app-1"].user)
+	// let the usage add up
+	time.Sleep(10 * time.Millisecond)
+
+	list := sortApplications(input, policies.UsageHistorySortPolicy, false, capacity)
+	assertAppList(t, list, []int{3, 2, 0, 1}, "history, no history on submission time")
+
+	input["app-3"].askMaxPriority = 1
+	list = sortApplications(input, policies.UsageHistorySortPolicy, false, capacity)
+	assertAppList(t, list, []int{3, 2, 1, 0}, "history, no history on priority")
+
+	input["app-0"].askMaxPriority = 2
+	list = sortApplications(input, policies.UsageHistorySortPolicy, true, capacity)
+	assertAppList(t, list, []int{0, 3, 2, 1}, "history - priority")
+}
+
+func TestSortAppsUserFair(t *testing.T) {
+	manager := ugm.GetUserManager()
+	manager.ClearUserTrackers()
+	manager.ClearGroupTrackers()
+	defer manager.ClearUserTrackers()
+	// alice submitted first, bob has no usage in the queue and carol uses less than alice
+	users := []string{"alice", "alice", "alice", "bob", "carol"}
+	input := make(map[string]*Application, len(users))
+	for i, user := range users {
+		appID := "app-" + strconv.Itoa(i)
+		app := newApplicationWithUserGroup(appID, "partition", "root.queue", user, []string{})
+		app.pending = resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 1})
+		app.SubmissionTime = time.Unix(int64(i), 0)
+		input[appID] = app
+	}
+	manager.IncreaseTrackedResource("root.queue", "app-0", resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 10}), input["app-0"].user)
+	manager.IncreaseTrackedResource("root.queue", "app-4", resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 5}), input["app-4"].user)
+	// usage in other queues does not count
+	manager.IncreaseTrackedResource("root.other", "app-other", resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 50}), input["app-3"].user)
+
+	list := sortApplications(input, policies.UserFairSortPolicy, false, nil)
+	assertAppListLength(t, list, []string{"app-3", "app-4", "app-0", "app-1", "app-2"}, "user fair")
+
+	// priority only changes the order of the applications of a user
+	input["app-2"].askMaxPriority = 5
+	list = sortApplications(input, policies.UserFairSortPolicy, true, nil)
+	assertAppListLength(t, list, []string{"app-3", "app-4", "app-2", "app-0", "app-1"}, "user fair - priority")
+	list = sortApplications(input, policies.UserFairSortPolicy, false, nil)
+	assertAppListLength(t, list, []string{"app-3", "app-4", "app-0", "app-1", "app-2"}, "user fair - priority not considered")
+}
+
+// TestSortAppsUserFairTurns checks that users with equal usage take turns, and that the usage is compared relative to
+// the capacity.
+func TestSortAppsUserFairTurns(t *testing.T) {
+	manager := ugm.GetUserManager()
+	manager.ClearUserTrackers()
+	manager.ClearGroupTrackers()
+	defer manager.ClearUserTrackers()
+	users := []string{"alice", "alice", "alice", "bob", "bob"}
+	input := make(map[string]*Application, len(users))
+	for i, user := range users {
+		appID := "app-" + strconv.Itoa(i)
+		app := newApplicationWithUserGroup(appID, "partition", "root.queue", user, []string{})
+		app.pending = resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 1})
+		app.SubmissionTime = time.Unix(int64(i), 0)
+		input[appID] = app
+	}
+	list := sortApplications(input, policies.UserFairSortPolicy, false, nil)
+	assertAppListLength(t, list, []string{"app-0", "app-3", "app-1", "app-4", "app-2"}, "user fair - turns")
+
+	// alice uses 10% of the vcores, bob 1% of the memory: bob uses less of the capacity
+	capacity := resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 100, "memory": 10000})
+	manager.IncreaseTrackedResource("root.queue", "app-0", resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 10}), input["app-0"].user)
+	manager.IncreaseTrackedResource("root.queue", "app-3", resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 100}), input["app-3"].user)
+	list = sortApplications(input, policies.UserFairSortPolicy, false, capacity)
+	assertAppListLength(t, list, []string{"app-3", "app-4", "app-0", "app-1", "app-2"}, "user fair - capacity")
+}
+
+// TestUserFairAlternates allocates to the first application in the sorted list: a user that submitted many
+// applications first does not starve a user that submitted later.
+func TestUserFairAlternates(t *testing.T) {
+	manager := ugm.GetUserManager()
+	manager.ClearUserTrackers()
+	manager.ClearGroupTrackers()
+	defer manager.ClearUserTrackers()
+	input := make(map[string]*Application)
+	for i := 0; i < 8; i++ {
+		user := "alice"
+		if i >= 5 {
+			user = "bob"
+		}
+		appID := "app-" + strconv.Itoa(i)
+		app := newApplicationWithUserGroup(appID, "partition", "root.queue", user, []string{})
+		app.pending = resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 1})
+		app.SubmissionTime = time.Unix(int64(i), 0)
+		input[appID] = app
+	}
+	var order []string
+	for len(input) > 0 {
+		app := sortApplications(input, policies.UserFairSortPolicy, false, nil)[0]
+		manager.IncreaseTrackedResource("root.queue", app.ApplicationID, app.pending, app.user)
+		order = append(order, app.ApplicationID)
+		delete(input, app.ApplicationID)
+	}
+	assert.DeepEqual(t, []string{"app-0", "app-5", "app-1", "app-6", "app-2", "app-7", "app-3", "app-4"}, order)
+}
+
+// fillInOrder allocates one task at a time to the first entry of the sorted list that still fits in the free resources,
+// until no task fits anymore. It returns the number of tasks allocated per entry.
+func fillInOrder(free *resources.Resource, sorted func() []string, tasks map[string]*resources.Resource, allocate func(string, *resources.Resource)) map[string]int {
+	allocated := make(map[string]int)
+	for {
+		progress := false
+		for _, name := range sorted() {
+			if free.FitIn(tasks[name]) {
+				free.SubFrom(tasks[name])
+				allocate(name, tasks[name])
+				allocated[name]++
+				progress = true
+				break
+			}
+		}
+		if !progress {
+			return allocated
+		}
+	}
+}
+
+// TestWeightedConvergence runs the same tasks in sibling queues with weights 3 and 1 under the fair and drf sort
+// policies: the queue with weight 3 ends up with three times the resources.
+func TestWeightedConvergence(t *testing.T) {
+	for _, policy := range []string{"fair", "drf"} {
+		t.Run(policy, func(t *testing.T) {
+			root, err := createRootQueue(map[string]string{"vcore": "8"})
+			assert.NilError(t, err, "queue create failed")
+			var parent *Queue
+			parent, err = createManagedQueueWithProps(root, "parent", true, nil, map[string]string{configs.QueueSortPolicy: policy})
+			assert.NilError(t, err, "failed to create parent queue")
+			task := resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 1000})
+			tasks := map[string]*resources.Resource{"heavy": task, "light": task}
+			leafs := make(map[string]*Queue, len(tasks))
+			for name, weight := range map[string]string{"heavy": "3", "light": "1"} {
+				var leaf *Queue
+				leaf, err = createManagedQueueWithProps(parent, name, false, nil, map[string]string{configs.QueueWeight: weight})
+				assert.NilError(t, err, "failed to create leaf queue")
+				leaf.pending = root.GetMaxResource()
+				leaf.allocatedResource = resources.NewResource()
+				leafs[name] = leaf
+			}
+			allocated := fillInOrder(root.GetMaxResource(), func() []string {
+				var names []string
+				for _, queue := range parent.sortQueues() {
+					names = append(names, queue.Name)
+				}
+				return names
+			}, tasks, func(name string, task *resources.Resource) {
+				leafs[name].allocatedResource.AddTo(task)
+			})
+			assert.Equal(t, 6, allocated["heavy"], "weight 3 queue tasks")
+			assert.Equal(t, 2, allocated["light"], "weight 1 queue tasks")
+		})
+	}
+}
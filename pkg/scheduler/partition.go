@@ -25,6 +25,7 @@ import (
 	"math"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/looplab/fsm"
@@ -55,6 +56,7 @@ type PartitionContext struct {
 	root                   *objects.Queue                  // start of the queue hierarchy
 	applications           map[string]*objects.Application // applications assigned to this partition
 	completedApplications  map[string]*objects.Application // completed applications from this partition
+	completedAppIDs        map[string]int                  // number of completed applications per application ID
 	rejectedApplications   map[string]*objects.Application // rejected applications from this partition
 	nodes                  objects.NodeCollection          // nodes assigned to this partition
 	placementManager       *placement.AppPlacementManager  // placement manager for this partition
@@ -68,6 +70,8 @@ type PartitionContext struct {
 	placeholderAllocations int                             // number of placeholder allocations
 	preemptionEnabled      bool                            // whether preemption is enabled or not
 	foreignAllocs          map[string]string               // allocKey-nodeID assignment of non-Yunikorn allocations
+	index                  *objectIndex                    // queues, applications and nodes by ID, internally synchronised
+	generation             atomic.Uint64                   // change generation of the partition, internally synchronised
 
 	// The partition write lock must not be held while manipulating an application.
 	// Scheduling is running continuously as a lock free background task. Scheduling an application
@@ -99,8 +103,10 @@ func newPartitionContext(conf configs.PartitionConfig, rmID string, cc *ClusterC
 		stateTime:             time.Now(),
 		applications:          make(map[string]*objects.Application),
 		completedApplications: make(map[string]*objects.Application),
+		completedAppIDs:       make(map[string]int),
 		nodes:                 objects.NewNodeCollection(conf.Name),
 		foreignAllocs:         make(map[string]string),
+		index:                 newObjectIndex(),
 	}
 	pc.partitionManager = newPartitionManager(pc, cc)
 	if err := pc.initialPartitionFromConfig(conf); err != nil {
@@ -124,6 +130,7 @@ func (pc *PartitionContext) initialPartitionFromConfig(conf configs.PartitionCon
 		return err
 	}
 	pc.root.PartitionID = pc.ID
+	pc.index.addQueue(pc.root)
 
 	// recursively add the queues to the root
 	if err = pc.addQueue(queueConf.Queues, pc.root); err != nil {
@@ -181,6 +188,8 @@ func (pc *PartitionContext) updatePartitionDetails(conf configs.PartitionConfig)
 
 	pc.Lock()
 	defer pc.Unlock()
+	// queues could be changed even if the update fails
+	defer pc.markChanged()
 	pc.updatePreemption(conf)
 	// start at the root: there is only one queue
 	queueConf := conf.Queues[0]
@@ -207,6 +216,7 @@ func (pc *PartitionContext) addQueue(conf []configs.QueueConfig, parent *objects
 			return err
 		}
 		thisQueue.PartitionID = pc.ID
+		pc.index.addQueue(thisQueue)
 
 		// recursive create the queues below
 		if len(queueConf.Queues) > 0 {
@@ -234,6 +244,7 @@ func (pc *PartitionContext) updateQueues(config []configs.QueueConfig, parent *o
 		var err error
 		if queue == nil {
 			queue, err = objects.NewConfiguredQueue(queueConfig, parent, pc.ID)
+			pc.index.addQueue(queue)
 		} else {
 			err = queue.ApplyConf(queueConfig)
 		}
@@ -285,6 +296,7 @@ func (pc *PartitionContext) handlePartitionEvent(event objects.ObjectEvent) erro
 	err := pc.stateMachine.Event(context.Background(), event.String(), pc.Name)
 	if err == nil {
 		pc.stateTime = time.Now()
+		pc.markChanged()
 		return nil
 	}
 	// handle the same state transition not nil error (limit of fsm).
@@ -391,6 +403,8 @@ func (pc *PartitionContext) AddApplication(app *objects.Application) error {
 	app.SetTerminatedCallback(pc.moveTerminatedApp)
 	queue.AddApplication(app)
 	pc.applications[appID] = app
+	pc.index.addApplication(app)
+	pc.markChanged()
 
 	return nil
 }
@@ -412,27 +426,102 @@ func (pc *PartitionContext) removeApplication(appID string) []*objects.Allocatio
 	// Remove all allocations
 	allocations := app.RemoveAllAllocations()
 	// Remove all allocations from node(s) (queues have been updated already)
-	if len(allocations) != 0 {
-		// track the number of allocations
-		pc.updateAllocationCount(-len(allocations))
-		for _, alloc := range allocations {
-			currentAllocationKey := alloc.GetAllocationKey()
-			node := pc.GetNode(alloc.GetNodeID())
-			if node == nil {
-				log.Log(log.SchedPartition).Warn("unknown node: not found in active node list",
-					zap.String("appID", appID),
-					zap.String("nodeID", alloc.GetNodeID()))
-				continue
-			}
-			if nodeAlloc := node.RemoveAllocation(currentAllocationKey); nodeAlloc == nil {
-				log.Log(log.SchedPartition).Warn("unknown allocation: not found on the node",
-					zap.String("appID", appID),
-					zap.String("allocationKey", currentAllocationKey),
-					zap.String("nodeID", alloc.GetNodeID()))
-			}
+	pc.removeAllocationsFromNodes(appID, allocations)
+	pc.markChanged()
+	return allocations
+}
+
+// removeAllocationsFromNodes removes the allocations of the application from the nodes and the allocation count.
+func (pc *PartitionContext) removeAllocationsFromNodes(appID string, allocations []*objects.Allocation) {
+	if len(allocations) == 0 {
+		return
+	}
+	// track the number of allocations
+	pc.updateAllocationCount(-len(allocations))
+	for _, alloc := range allocations {
+		currentAllocationKey := alloc.GetAllocationKey()
+		node := pc.GetNode(alloc.GetNodeID())
+		if node == nil {
+			log.Log(log.SchedPartition).Warn("unknown node: not found in active node list",
+				zap.String("appID", appID),
+				zap.String("nodeID", alloc.GetNodeID()))
+			continue
+		}
+		if nodeAlloc := node.RemoveAllocation(currentAllocationKey); nodeAlloc == nil {
+			log.Log(log.SchedPartition).Warn("unknown allocation: not found on the node",
+				zap.String("appID", appID),
+				zap.String("allocationKey", currentAllocationKey),
+				zap.String("nodeID", alloc.GetNodeID()))
 		}
 	}
-	return allocations
+}
+
+// failApplication fails the application and removes all pending requests. The removed requests and the allocations
+// of the application are returned: the RM must release them. The application fails when the last allocation is
+// released, or immediately if it has no allocations.
+func (pc *PartitionContext) failApplication(app *objects.Application, message string) ([]*objects.Allocation, error) {
+	// a second fail event would fail the application before the allocations are released
+	if app.IsFailing() {
+		return nil, fmt.Errorf("application %s is already failing", app.ApplicationID)
+	}
+	if err := app.FailApplication(message); err != nil {
+		return nil, err
+	}
+	released := getPendingRequests(app)
+	_ = app.RemoveAllocationAsk("")
+	allocations := app.GetAllAllocations()
+	if len(allocations) == 0 {
+		if err := app.FailApplication(message); err != nil {
+			log.Log(log.SchedPartition).Warn("Application state not changed to Failed",
+				zap.String("appID", app.ApplicationID),
+				zap.String("currentState", app.CurrentState()),
+				zap.Error(err))
+		}
+	}
+	pc.markChanged()
+	return append(released, allocations...), nil
+}
+
+// killApplication fails the application and removes all pending requests and allocations from the scheduler
+// without waiting for the RM. The removed requests and allocations are returned: the RM must release them.
+// The application is moved to the completed applications like any other failed application. A failing application
+// is killed without waiting for the RM to release the allocations.
+func (pc *PartitionContext) killApplication(app *objects.Application, message string) ([]*objects.Allocation, error) {
+	if !app.IsFailing() {
+		if err := app.FailApplication(message); err != nil {
+			return nil, err
+		}
+	}
+	released := getPendingRequests(app)
+	_ = app.RemoveAllocationAsk("")
+	// the queue is updated before the allocations are removed from the application
+	if queue := app.GetQueue(); queue != nil {
+		queue.RemoveApplication(app)
+	}
+	allocations := app.RemoveAllAllocations()
+	pc.removeAllocationsFromNodes(app.ApplicationID, allocations)
+	pc.markChanged()
+	return append(released, allocations...), nil
+}
+
+// moveApplication moves the application to the leaf queue.
+func (pc *PartitionContext) moveApplication(app *objects.Application, queue *objects.Queue) error {
+	if err := app.MoveToQueue(queue); err != nil {
+		return err
+	}
+	pc.markChanged()
+	return nil
+}
+
+// getPendingRequests returns the requests of the application that are not allocated.
+func getPendingRequests(app *objects.Application) []*objects.Allocation {
+	var pending []*objects.Allocation
+	for _, request := range app.GetAllRequests() {
+		if !request.IsAllocated() {
+			pending = append(pending, request)
+		}
+	}
+	return pending
 }
 
 // Locked updates of the partition tracking info
@@ -447,6 +536,7 @@ func (pc *PartitionContext) removeAppInternal(appID string) *objects.Application
 	}
 	// remove from partition then cleanup underlying objects
 	delete(pc.applications, appID)
+	pc.index.removeApplication(app.ID)
 	return app
 }
 
@@ -468,6 +558,31 @@ func (pc *PartitionContext) getRejectedApplication(appID string) *objects.Applic
 	return pc.rejectedApplications[appID]
 }
 
+// hasApplication returns true if the application is active, completed or rejected in the partition.
+func (pc *PartitionContext) hasApplication(appID string) bool {
+	pc.RLock()
+	defer pc.RUnlock()
+
+	return pc.applications[appID] != nil || pc.rejectedApplications[appID] != nil || pc.completedAppIDs[appID] > 0
+}
+
+// hasEventObject returns true if the object an event record refers to is part of the partition.
+// User and group events are not linked to a partition and never match.
+func (pc *PartitionContext) hasEventObject(event *si.EventRecord) bool {
+	switch event.Type {
+	case si.EventRecord_APP:
+		return pc.hasApplication(event.ObjectID)
+	case si.EventRecord_REQUEST:
+		return pc.hasApplication(event.ReferenceID)
+	case si.EventRecord_NODE:
+		return pc.GetNode(event.ObjectID) != nil
+	case si.EventRecord_QUEUE:
+		return pc.GetQueue(event.ObjectID) != nil
+	default:
+		return false
+	}
+}
+
 // GetQueue returns queue from the structure based on the fully qualified name.
 // Wrapper around the unlocked version getQueueInternal()
 // Visible by tests
@@ -513,9 +628,24 @@ func (pc *PartitionContext) GetPlacementRules() []*dao.RuleDAO {
 	return pc.getPlacementManager().GetRulesDAO()
 }
 
+// PlacementDryRun executes the placement rules for the described application without adding it to the partition.
+// No queues are created and the application is not tracked.
+func (pc *PartitionContext) PlacementDryRun(request *dao.PlacementDryRunRequest) *dao.PlacementDryRunDAOInfo {
+	ugi := security.UserGroup{User: request.User, Groups: request.Groups}
+	app := objects.NewDryRunApplication(request.ApplicationID, pc.Name, request.Queue, request.Tags, ugi)
+	result := pc.getPlacementManager().DryRun(app)
+	result.Partition = common.GetPartitionNameWithoutClusterID(pc.Name)
+	return result
+}
+
 // createRecoveryQueue creates the recovery queue to add to the hierarchy
 func (pc *PartitionContext) createRecoveryQueue() (*objects.Queue, error) {
-	return objects.NewRecoveryQueue(pc.root)
+	queue, err := objects.NewRecoveryQueue(pc.root)
+	if err == nil {
+		pc.index.addQueue(queue)
+		pc.markChanged()
+	}
+	return queue, err
 }
 
 // Create a queue with full hierarchy. This is called when a new queue is created from a placement rule.
@@ -556,7 +686,9 @@ func (pc *PartitionContext) createQueue(name string, user security.UserGroup) (*
 				zap.Error(err))
 			return nil, err
 		}
+		pc.index.addQueue(queue)
 	}
+	pc.markChanged()
 	return queue, nil
 }
 
@@ -565,6 +697,22 @@ func (pc *PartitionContext) GetNode(nodeID string) *objects.Node {
 	return pc.nodes.GetNode(nodeID)
 }
 
+// GetNodeByID returns the node with the given ID, or nil if the node is not part of this partition.
+func (pc *PartitionContext) GetNodeByID(id string) *objects.Node {
+	return pc.index.getNode(id)
+}
+
+// GetQueueByID returns the queue with the given ID, or nil if the queue is not part of this partition.
+func (pc *PartitionContext) GetQueueByID(id string) *objects.Queue {
+	return pc.index.getQueue(id)
+}
+
+// GetApplicationByID returns the application with the given ID, or nil if the application is not part of this
+// partition. Active, completed and rejected applications are returned until they expire.
+func (pc *PartitionContext) GetApplicationByID(id string) *objects.Application {
+	return pc.index.getApplication(id)
+}
+
 // AddNode adds the node to the partition. Updates the partition and root queue resources if the node is added
 // successfully to the partition.
 // NOTE: this is a lock free call. It must NOT be called holding the PartitionContext lock.
@@ -600,6 +748,7 @@ func (pc *PartitionContext) updatePartitionResource(delta *resources.Resource) {
 		pc.totalPartitionResource.Prune()
 		// set the root queue size
 		pc.root.SetMaxResource(pc.totalPartitionResource)
+		pc.markChanged()
 	}
 }
 
@@ -612,6 +761,7 @@ func (pc *PartitionContext) addNodeToList(node *objects.Node) error {
 		return fmt.Errorf("failed to add node %s to partition %s, error: %v", node.NodeID, pc.Name, err)
 	}
 
+	pc.index.addNode(node)
 	pc.updatePartitionResource(node.GetCapacity())
 	metrics.GetSchedulerMetrics().IncActiveNodes()
 	log.Log(log.SchedPartition).Info("Updated available resources from added node",
@@ -632,6 +782,7 @@ func (pc *PartitionContext) removeNodeFromList(nodeID string) *objects.Node {
 	}
 
 	// Remove node from list of tracked nodes
+	pc.index.removeNode(node.ID)
 	metrics.GetSchedulerMetrics().DecActiveNodes()
 	log.Log(log.SchedPartition).Info("Removed node from available partition nodes",
 		zap.String("partitionName", pc.Name),
@@ -861,6 +1012,7 @@ func (pc *PartitionContext) tryPlaceholderAllocate() *objects.AllocationResult {
 			zap.String("appID", result.Request.GetApplicationID()),
 			zap.String("allocationKey", result.Request.GetAllocationKey()),
 			zap.String("placeholder released allocationKey", result.Request.GetRelease().GetAllocationKey()))
+		pc.markChanged()
 		// pass the release back to the RM via the cluster context
 		return result
 	}
@@ -870,6 +1022,8 @@ func (pc *PartitionContext) tryPlaceholderAllocate() *objects.AllocationResult {
 // Process the allocation and make the left over changes in the partition.
 // NOTE: this is a lock free call. It must NOT be called holding the PartitionContext lock.
 func (pc *PartitionContext) allocate(result *objects.AllocationResult) *objects.AllocationResult {
+	// the application and queues have been changed by the scheduling cycle, even if the result is dropped
+	defer pc.markChanged()
 	// find the app make sure it still exists
 	appID := result.Request.GetApplicationID()
 	app := pc.getApplication(appID)
@@ -1107,21 +1261,44 @@ func (pc *PartitionContext) getCompletedAppsByState(state string) []string {
 func (pc *PartitionContext) cleanupExpiredApps() {
 	for _, appID := range pc.getAppsByState(objects.Expired.String()) {
 		pc.Lock()
-		delete(pc.applications, appID)
+		pc.removeExpiredApp(pc.applications, appID)
 		pc.Unlock()
 	}
 	for _, appID := range pc.getRejectedAppsByState(objects.Expired.String()) {
 		pc.Lock()
-		delete(pc.rejectedApplications, appID)
+		pc.removeExpiredApp(pc.rejectedApplications, appID)
 		pc.Unlock()
 	}
-	for _, appID := range pc.getCompletedAppsByState(objects.Expired.String()) {
+	for _, key := range pc.getCompletedAppsByState(objects.Expired.String()) {
 		pc.Lock()
-		delete(pc.completedApplications, appID)
+		if app, ok := pc.completedApplications[key]; ok {
+			pc.removeCompletedAppID(app.ApplicationID)
+		}
+		pc.removeExpiredApp(pc.completedApplications, key)
 		pc.Unlock()
 	}
 }
 
+// removeExpiredApp removes the application from the tracking map and the ID index.
+// NOTE: this is a lock free call. It should only be called holding the PartitionContext lock.
+func (pc *PartitionContext) removeExpiredApp(appMap map[string]*objects.Application, appID string) {
+	if app, ok := appMap[appID]; ok {
+		pc.index.removeApplication(app.ID)
+		delete(appMap, appID)
+		pc.markChanged()
+	}
+}
+
+// removeCompletedAppID removes one completed application with the ID from the lookup by application ID.
+// NOTE: this is a lock free call. It should only be called holding the PartitionContext lock.
+func (pc *PartitionContext) removeCompletedAppID(appID string) {
+	if pc.completedAppIDs[appID] <= 1 {
+		delete(pc.completedAppIDs, appID)
+		return
+	}
+	pc.completedAppIDs[appID]--
+}
+
 // GetNodes returns a slice of all nodes unfiltered from the iterator
 func (pc *PartitionContext) GetNodes() []*objects.Node {
 	return pc.nodes.GetNodes()
@@ -1141,6 +1318,8 @@ func (pc *PartitionContext) UpdateAllocation(alloc *objects.Allocation) (request
 	if pc.isStopped() {
 		return false, false, fmt.Errorf("partition %s is stopped; cannot process allocation %s", pc.Name, alloc.GetAllocationKey())
 	}
+	// a failed update could have changed the application before failing
+	defer pc.markChanged()
 
 	allocationKey := alloc.GetAllocationKey()
 	applicationID := alloc.GetApplicationID()
@@ -1430,6 +1609,7 @@ func (pc *PartitionContext) removeAllocation(release *si.AllocationRelease) ([]*
 	if release == nil {
 		return nil, nil
 	}
+	defer pc.markChanged()
 	appID := release.ApplicationID
 	allocationKey := release.GetAllocationKey()
 	if appID == "" {
@@ -1581,6 +1761,18 @@ func (pc *PartitionContext) removeForeignAllocation(allocID string) {
 	node.RemoveAllocation(allocID)
 }
 
+// GetGeneration returns the change generation of the partition. The generation increases after every change of the
+// queues, applications, nodes or allocations of the partition. Read the generation before reading the objects:
+// a change made while reading is then always picked up by the next read.
+func (pc *PartitionContext) GetGeneration() uint64 {
+	return pc.generation.Load()
+}
+
+// markChanged increases the change generation of the partition, call after the change has been made.
+func (pc *PartitionContext) markChanged() {
+	pc.generation.Add(1)
+}
+
 func (pc *PartitionContext) GetCurrentState() string {
 	return pc.stateMachine.Current()
 }
@@ -1627,9 +1819,13 @@ func (pc *PartitionContext) moveTerminatedApp(appID string) {
 	defer pc.Unlock()
 	delete(pc.applications, appID)
 	pc.completedApplications[newID] = app
+	pc.completedAppIDs[appID]++
+	pc.markChanged()
 }
 
 func (pc *PartitionContext) AddRejectedApplication(rejectedApplication *objects.Application, rejectedMessage string) {
+	// the application is not in a queue: use the webhooks of the closest existing queue on its path
+	rejectedApplication.SetWebhookURLs(pc.getWebhookURLs(rejectedApplication.GetQueuePath()))
 	if err := rejectedApplication.RejectApplication(rejectedMessage); err != nil {
 		log.Log(log.SchedPartition).Warn("BUG: Unexpected failure: Application state not changed to Rejected",
 			zap.String("currentState", rejectedApplication.CurrentState()),
@@ -1639,6 +1835,27 @@ func (pc *PartitionContext) AddRejectedApplication(rejectedApplication *objects.
 		pc.rejectedApplications = make(map[string]*objects.Application)
 	}
 	pc.rejectedApplications[rejectedApplication.ApplicationID] = rejectedApplication
+	pc.index.addApplication(rejectedApplication)
+	pc.markChanged()
+}
+
+// getWebhookURLs returns the application webhooks of the queue, or of its closest existing parent queue if the
+// queue does not exist. The root queue is used if the path is not set.
+func (pc *PartitionContext) getWebhookURLs(queuePath string) []string {
+	for path := queuePath; path != ""; {
+		if queue := pc.GetQueue(path); queue != nil {
+			return queue.GetWebhookURLs()
+		}
+		idx := strings.LastIndex(path, configs.DOT)
+		if idx == -1 {
+			break
+		}
+		path = path[:idx]
+	}
+	if root := pc.GetQueue(configs.RootQueue); root != nil {
+		return root.GetWebhookURLs()
+	}
+	return nil
 }
 
 func (pc *PartitionContext) incPhAllocationCount() {
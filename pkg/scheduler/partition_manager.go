@@ -121,6 +121,9 @@ func (manager *partitionManager) cleanQueues(queue *objects.Queue) {
 				log.Log(log.SchedPartition).Debug("unexpected failure removing the queue",
 					zap.String("partitionName", manager.pc.Name),
 					zap.String("queue", queue.QueuePath))
+			} else {
+				manager.pc.index.removeQueue(queue.ID)
+				manager.pc.markChanged()
 			}
 		} else {
 			log.Log(log.SchedPartition).Debug("skip removing the queue",
@@ -1537,6 +1537,26 @@ func TestGetQueue(t *testing.T) {
 	assert.Equal(t, queue, parent, "partition returned nil for existing queue name request")
 }
 
+func TestGetWebhookURLs(t *testing.T) {
+	partition, err := newBasePartition()
+	assert.NilError(t, err, "test partition create failed with error")
+	assert.Assert(t, partition.getWebhookURLs("") == nil, "no webhooks expected")
+
+	parentConf := configs.QueueConfig{
+		Name:       "parent",
+		Parent:     true,
+		Properties: map[string]string{configs.ApplicationWebhookURL: "https://hooks.example.com/parent"},
+	}
+	_, err = objects.NewConfiguredQueue(parentConf, partition.root, partition.ID)
+	assert.NilError(t, err, "failed to create parent queue")
+	expected := []string{"https://hooks.example.com/parent"}
+	assert.DeepEqual(t, expected, partition.getWebhookURLs("root.parent"))
+	// closest existing parent queue
+	assert.DeepEqual(t, expected, partition.getWebhookURLs("root.parent.missing.leaf"))
+	assert.Assert(t, partition.getWebhookURLs("root.default") == nil, "no webhooks expected on default queue")
+	assert.Assert(t, partition.getWebhookURLs("unknown") == nil, "no webhooks expected for unknown queue")
+}
+
 func TestTryAllocate(t *testing.T) {
 	setupUGM()
 	partition := createQueuesNodes(t)
@@ -2752,6 +2772,7 @@ func TestCleanupCompletedApps(t *testing.T) {
 	assert.Equal(t, 0, len(partition.GetApplications()), "the partition should have 0 apps")
 	assert.Equal(t, 2, len(partition.GetCompletedApplications()), "the partition should have 2 completed apps")
 	assert.Equal(t, 2, len(partition.getCompletedAppsByState(objects.Completed.String())), "the partition should have 2 completed apps")
+	assert.Assert(t, partition.hasApplication("completedApp1"), "completed application should be found by ID")
 
 	// mark the app for removal
 	completedApp1.SetState(objects.Expired.String())
@@ -2761,6 +2782,8 @@ func TestCleanupCompletedApps(t *testing.T) {
 	partition.cleanupExpiredApps()
 	assert.Equal(t, 1, len(partition.GetCompletedApplications()), "the partition should have 1 completed app")
 	assert.Equal(t, 0, len(partition.getCompletedAppsByState(objects.Expired.String())), "the partition should have 0 expired apps")
+	assert.Assert(t, !partition.hasApplication("completedApp1"), "expired application should not be found by ID")
+	assert.Assert(t, partition.hasApplication("completedApp2"), "completed application should be found by ID")
 }
 
 func TestCleanupRejectedApps(t *testing.T) {
@@ -4742,3 +4765,101 @@ func TestForeignAllocation(t *testing.T) {
 	assert.Equal(t, 0, len(node1.GetYunikornAllocations()))
 	assert.Assert(t, node1.GetAllocation(foreignAlloc1) == nil)
 }
+
+func TestGetObjectsByID(t *testing.T) {
+	setupUGM()
+	partition, err := newBasePartition()
+	assert.NilError(t, err, "partition create failed")
+	root := partition.GetQueue("root")
+	assert.Equal(t, root, partition.GetQueueByID(root.ID), "root queue not indexed")
+	leaf := partition.GetQueue(defQueue)
+	assert.Equal(t, leaf, partition.GetQueueByID(leaf.ID), "configured queue not indexed")
+	assert.Assert(t, partition.GetQueueByID("unknown") == nil, "unknown queue ID returned a queue")
+
+	// dynamic queues
+	dynamic, err := partition.createQueue("root.parent.leaf", security.UserGroup{User: "testuser"})
+	assert.NilError(t, err, "dynamic queue create failed")
+	assert.Equal(t, dynamic, partition.GetQueueByID(dynamic.ID), "dynamic leaf queue not indexed")
+	parent := partition.GetQueue("root.parent")
+	assert.Equal(t, parent, partition.GetQueueByID(parent.ID), "dynamic parent queue not indexed")
+
+	// nodes are indexed while part of the partition
+	node := newNodeMaxResource(nodeID1, resources.NewResource())
+	err = partition.AddNode(node)
+	assert.NilError(t, err, "test node add failed unexpected")
+	assert.Equal(t, node, partition.GetNodeByID(node.ID), "node not indexed")
+	_, _ = partition.removeNode(nodeID1)
+	assert.Assert(t, partition.GetNodeByID(node.ID) == nil, "removed node still indexed")
+
+	// applications are indexed while part of the partition
+	app := newApplication(appID1, "default", defQueue)
+	err = partition.AddApplication(app)
+	assert.NilError(t, err, "add application to partition should not have failed")
+	assert.Equal(t, app, partition.GetApplicationByID(app.ID), "application not indexed")
+	_ = partition.removeApplication(appID1)
+	assert.Assert(t, partition.GetApplicationByID(app.ID) == nil, "removed application still indexed")
+
+	// rejected applications until they expire
+	app = newApplication(appID2, "default", defQueue)
+	partition.AddRejectedApplication(app, "rejected")
+	assert.Equal(t, app, partition.GetApplicationByID(app.ID), "rejected application not indexed")
+	app.SetState(objects.Expired.String())
+	partition.cleanupExpiredApps()
+	assert.Assert(t, partition.GetApplicationByID(app.ID) == nil, "expired application still indexed")
+
+	// queue cleanup removes the queue from the index
+	partition.partitionManager.cleanQueues(partition.root)
+	assert.Assert(t, partition.GetQueue("root.parent") == nil, "dynamic queue should have been removed")
+	assert.Assert(t, partition.GetQueueByID(dynamic.ID) == nil, "removed dynamic leaf queue still indexed")
+	assert.Assert(t, partition.GetQueueByID(parent.ID) == nil, "removed dynamic parent queue still indexed")
+}
+
+func TestPartitionGeneration(t *testing.T) {
+	setupUGM()
+	partition, err := newBasePartition()
+	assert.NilError(t, err, "partition create failed")
+	generation := partition.GetGeneration()
+	assertChanged := func(action string) {
+		t.Helper()
+		current := partition.GetGeneration()
+		assert.Assert(t, current > generation, "generation not changed after %s", action)
+		generation = current
+	}
+
+	res := resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 10})
+	err = partition.AddNode(newNodeMaxResource(nodeID1, res))
+	assert.NilError(t, err, "failed to add node")
+	assertChanged("adding a node")
+
+	app := newApplication(appID1, "default", defQueue)
+	err = partition.AddApplication(app)
+	assert.NilError(t, err, "failed to add app")
+	assertChanged("adding an application")
+
+	askRes := resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 1})
+	_, _, err = partition.UpdateAllocation(newAllocationAsk(allocKey, appID1, askRes))
+	assert.NilError(t, err, "failed to add ask")
+	assertChanged("adding an ask")
+
+	// reading does not change the generation
+	_ = partition.GetPartitionQueues()
+	assert.Equal(t, generation, partition.GetGeneration(), "generation changed on read")
+
+	result := partition.tryAllocate()
+	assert.Assert(t, result != nil && result.ResultType == objects.Allocated, "expected an allocation")
+	assertChanged("allocating")
+
+	partition.removeAllocation(&si.AllocationRelease{
+		PartitionName:   "default",
+		ApplicationID:   appID1,
+		AllocationKey:   allocKey,
+		TerminationType: si.TerminationType_STOPPED_BY_RM,
+	})
+	assertChanged("releasing an allocation")
+
+	partition.removeApplication(appID1)
+	assertChanged("removing an application")
+
+	partition.removeNode(nodeID1)
+	assertChanged("removing a node")
+}
@@ -88,13 +88,14 @@ func (fr *fixedRule) initialise(conf configs.PlacementRule) error {
 	return err
 }
 
-func (fr *fixedRule) placeApplication(app *objects.Application, queueFn func(string) *objects.Queue) (string, error) {
+func (fr *fixedRule) placeApplication(app *objects.Application, queueFn func(string) *objects.Queue, trace *ruleTrace) (string, error) {
 	// before anything run the filter
 	if !fr.filter.allowUser(app.GetUser()) {
 		log.Log(log.SchedApplication).Debug("Fixed rule filtered",
 			zap.String("application", app.ApplicationID),
 			zap.Any("user", app.GetUser()),
 			zap.String("queueName", fr.queue))
+		trace.skip(dao.PlacementSkipFilterDenied)
 		return "", nil
 	}
 	queueName := fr.queue
@@ -104,7 +105,7 @@ func (fr *fixedRule) placeApplication(app *objects.Application, queueFn func(str
 		var err error
 		// run the parent rule if set
 		if fr.parent != nil {
-			parentName, err = fr.parent.placeApplication(app, queueFn)
+			parentName, err = fr.parent.placeApplication(app, queueFn, trace)
 			// failed parent rule, fail this rule
 			if err != nil {
 				return "", err
@@ -137,6 +138,7 @@ func (fr *fixedRule) placeApplication(app *objects.Application, queueFn func(str
 	queue := queueFn(queueName)
 	// if we cannot create the queue must exist
 	if !fr.create && queue == nil {
+		trace.skip(dao.PlacementSkipQueueNotExist)
 		return "", nil
 	}
 	log.Log(log.SchedApplication).Info("Fixed rule application placed",
@@ -97,12 +97,12 @@ func TestFixedRulePlace(t *testing.T) {
 				}
 				var queue string
 				if tt.nilError {
-					queue, err = fr.placeApplication(app, queueFunc)
+					queue, err = fr.placeApplication(app, queueFunc, nil)
 					if queue != tt.expectedQueue || err != nil {
 						t.Errorf("fixed rule failed to place queue in correct queue '%s', err %v", queue, err)
 					}
 				} else {
-					_, err = fr.placeApplication(app, queueFunc)
+					_, err = fr.placeApplication(app, queueFunc, nil)
 					if err == nil {
 						t.Errorf("fixed rule should have failed to place queue, err %v", err)
 					}
@@ -142,7 +142,7 @@ func TestFixedRuleParent(t *testing.T) {
 		t.Errorf("fixed rule create failed with queue name, err %v", err)
 	}
 	var queue string
-	queue, err = fr.placeApplication(app, queueFunc)
+	queue, err = fr.placeApplication(app, queueFunc, nil)
 	if queue != "" || err != nil {
 		t.Errorf("fixed rule with create false for child should have failed and gave '%s', error %v", queue, err)
 	}
@@ -162,7 +162,7 @@ func TestFixedRuleParent(t *testing.T) {
 	if err != nil || fr == nil {
 		t.Errorf("fixed rule create failed with queue name, err %v", err)
 	}
-	queue, err = fr.placeApplication(app, queueFunc)
+	queue, err = fr.placeApplication(app, queueFunc, nil)
 	if queue != "" || err != nil {
 		t.Errorf("fixed rule with non existing parent queue should have failed '%s', error %v", queue, err)
 	}
@@ -182,7 +182,7 @@ func TestFixedRuleParent(t *testing.T) {
 	if err != nil || fr == nil {
 		t.Errorf("fixed rule create failed with queue name, err %v", err)
 	}
-	queue, err = fr.placeApplication(app, queueFunc)
+	queue, err = fr.placeApplication(app, queueFunc, nil)
 	if queue != nameParentChild || err != nil {
 		t.Errorf("fixed rule with non existing parent queue should created '%s', error %v", queue, err)
 	}
@@ -217,7 +217,7 @@ func TestFixedRuleParent(t *testing.T) {
 	if err != nil || fr == nil {
 		t.Errorf("fixed rule create failed with queue name, err %v", err)
 	}
-	queue, err = fr.placeApplication(app, queueFunc)
+	queue, err = fr.placeApplication(app, queueFunc, nil)
 	if queue != "" || err == nil {
 		t.Errorf("fixed rule with parent declared as leaf should have failed '%s', error %v", queue, err)
 	}
@@ -239,7 +239,7 @@ func TestFixedRuleParent(t *testing.T) {
 	if err != nil || fr == nil {
 		t.Errorf("fixed rule create failed with queue name, err %v", err)
 	}
-	queue, err = fr.placeApplication(app, queueFunc)
+	queue, err = fr.placeApplication(app, queueFunc, nil)
 	if queue != "" || err == nil {
 		t.Errorf("fixed rule with parent declared as leaf should have failed '%s', error %v", queue, err)
 	}
@@ -258,7 +258,7 @@ func TestFixedRuleParent(t *testing.T) {
 	if err != nil || fr == nil {
 		t.Errorf("fixed rule create failed with queue name, err %v", err)
 	}
-	queue, err = fr.placeApplication(app, queueFunc)
+	queue, err = fr.placeApplication(app, queueFunc, nil)
 	if queue != "root.root.testchild" || err != nil {
 		t.Errorf("fixed rule with parent declared as leaf should have failed '%s', error %v", queue, err)
 	}
@@ -36,6 +36,9 @@ import (
 // RejectedError is the standard error returned if placement has failed
 var RejectedError = errors.New("application rejected: no placement rule matched")
 
+// defaultQueueRuleName is the name reported by a dry run if no rule matched and the default queue is used
+const defaultQueueRuleName = "default queue"
+
 type AppPlacementManager struct {
 	rules   []rule
 	queueFn func(string) *objects.Queue
@@ -102,6 +105,40 @@ func (m *AppPlacementManager) PlaceApplication(app *objects.Application) error {
 	m.RLock()
 	defer m.RUnlock()
 
+	queueName, err := m.placeApplication(app, nil)
+	// Add the queue into the application, overriding what was submitted
+	app.SetQueuePath(queueName)
+	return err
+}
+
+// DryRun executes the rules for the passed in application without placing it.
+// It returns the queue the application would be placed in, the rule that placed it and the result of each rule that
+// was executed. The application, the placement manager and the queues are not changed.
+func (m *AppPlacementManager) DryRun(app *objects.Application) *dao.PlacementDryRunDAOInfo {
+	m.RLock()
+	defer m.RUnlock()
+
+	result := &dao.PlacementDryRunDAOInfo{
+		Rules: make([]*dao.PlacementRuleResultDAOInfo, 0, len(m.rules)),
+	}
+	queueName, err := m.placeApplication(app, result)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Queue = queueName
+	result.QueueExists = m.queueFn(queueName) != nil
+	for _, ruleResult := range result.Rules {
+		if ruleResult.Matched {
+			result.Rule = ruleResult.Name
+		}
+	}
+	return result
+}
+
+// placeApplication executes the rules and returns the queue the application is placed in.
+// If a dry run result is passed in the result of each rule is added to it.
+func (m *AppPlacementManager) placeApplication(app *objects.Application, dryRun *dao.PlacementDryRunDAOInfo) (string, error) {
 	var queueName string
 	var err error
 	var remainingRules = len(m.rules)
@@ -110,13 +147,19 @@ func (m *AppPlacementManager) PlaceApplication(app *objects.Application) error {
 		log.Log(log.SchedApplication).Debug("Executing rule for placing application",
 			zap.String("ruleName", checkRule.getName()),
 			zap.String("application", app.ApplicationID))
-		queueName, err = checkRule.placeApplication(app, m.queueFn)
+		var trace *ruleTrace
+		var ruleResult *dao.PlacementRuleResultDAOInfo
+		if dryRun != nil {
+			trace = &ruleTrace{}
+			ruleResult = &dao.PlacementRuleResultDAOInfo{Name: checkRule.getName()}
+			dryRun.Rules = append(dryRun.Rules, ruleResult)
+		}
+		queueName, err = checkRule.placeApplication(app, m.queueFn, trace)
 		if err != nil {
 			log.Log(log.SchedApplication).Error("rule execution failed",
 				zap.String("ruleName", checkRule.getName()),
 				zap.Error(err))
-			app.SetQueuePath("")
-			return err
+			return "", err
 		}
 		// if no queue found even after the last rule, try to place in the default queue
 		if remainingRules == 0 && queueName == "" {
@@ -128,10 +171,17 @@ func (m *AppPlacementManager) PlaceApplication(app *objects.Application) error {
 			if queue != nil {
 				// default queue exist
 				queueName = common.DefaultPlacementQueue
+				// report the default queue as a separate step after the last rule
+				if dryRun != nil {
+					recordSkip(ruleResult, "", trace.getReason())
+					ruleResult = &dao.PlacementRuleResultDAOInfo{Name: defaultQueueRuleName}
+					dryRun.Rules = append(dryRun.Rules, ruleResult)
+				}
 			}
 		}
 		// no queue name next rule
 		if queueName == "" {
+			recordSkip(ruleResult, "", trace.getReason())
 			continue
 		}
 		// We have the recovery queue bail out: only if we are doing forced placement
@@ -140,6 +190,7 @@ func (m *AppPlacementManager) PlaceApplication(app *objects.Application) error {
 		if queueName == common.RecoveryQueueFull && app.IsCreateForced() {
 			log.Log(log.SchedApplication).Info("Placing application in recovery queue",
 				zap.String("application", app.ApplicationID))
+			recordMatch(ruleResult, queueName)
 			break
 		}
 		// queueName returned make sure ACL allows access and set the queueName in the app
@@ -158,6 +209,7 @@ func (m *AppPlacementManager) PlaceApplication(app *objects.Application) error {
 					zap.String("queueName", queue.GetQueuePath()),
 					zap.String("ruleName", checkRule.getName()),
 					zap.String("application", app.ApplicationID))
+				recordSkip(ruleResult, queueName, dao.PlacementSkipSubmitDenied)
 				// reset the queue name for the last rule in the chain
 				queueName = ""
 				continue
@@ -169,6 +221,7 @@ func (m *AppPlacementManager) PlaceApplication(app *objects.Application) error {
 					zap.String("queueName", queueName),
 					zap.String("ruleName", checkRule.getName()),
 					zap.String("application", app.ApplicationID))
+				recordSkip(ruleResult, queueName, dao.PlacementSkipParentQueue)
 				// reset the queue name for the last rule in the chain
 				queueName = ""
 				continue
@@ -179,6 +232,7 @@ func (m *AppPlacementManager) PlaceApplication(app *objects.Application) error {
 					zap.String("queueName", queueName),
 					zap.String("ruleName", checkRule.getName()),
 					zap.String("application", app.ApplicationID))
+				recordSkip(ruleResult, queueName, dao.PlacementSkipSubmitDenied)
 				// reset the queue name for the last rule in the chain
 				queueName = ""
 				continue
@@ -189,16 +243,32 @@ func (m *AppPlacementManager) PlaceApplication(app *objects.Application) error {
 			zap.String("application", app.ApplicationID),
 			zap.String("ruleName", checkRule.getName()),
 			zap.String("queueName", queueName))
+		recordMatch(ruleResult, queueName)
 		break
 	}
 	// no more rules to check no queueName found reject placement
 	if queueName == "" {
-		app.SetQueuePath("")
-		return RejectedError
+		return "", RejectedError
 	}
-	// Add the queue into the application, overriding what was submitted
-	app.SetQueuePath(queueName)
-	return nil
+	return queueName, nil
+}
+
+// recordSkip updates the dry run result of a rule that did not place the application.
+func recordSkip(ruleResult *dao.PlacementRuleResultDAOInfo, queueName, reason string) {
+	if ruleResult == nil {
+		return
+	}
+	ruleResult.Queue = queueName
+	ruleResult.SkipReason = reason
+}
+
+// recordMatch updates the dry run result of the rule that placed the application.
+func recordMatch(ruleResult *dao.PlacementRuleResultDAOInfo, queueName string) {
+	if ruleResult == nil {
+		return
+	}
+	ruleResult.Queue = queueName
+	ruleResult.Matched = true
 }
 
 // buildRules builds a new rule set based on the config.
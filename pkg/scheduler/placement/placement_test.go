@@ -28,6 +28,7 @@ import (
 	"github.com/G-Research/yunikorn-core/pkg/common/configs"
 	"github.com/G-Research/yunikorn-core/pkg/common/security"
 	"github.com/G-Research/yunikorn-core/pkg/scheduler/placement/types"
+	"github.com/G-Research/yunikorn-core/pkg/webservice/dao"
 	siCommon "github.com/G-Research/yunikorn-scheduler-interface/lib/go/common"
 )
 
@@ -540,6 +541,85 @@ partitions:
 	}
 }
 
+func TestManagerDryRun(t *testing.T) {
+	data := `
+partitions:
+  - name: default
+    queues:
+      - name: root
+        submitacl: "any-user"
+        queues:
+          - name: provided
+            submitacl: "*"
+          - name: acldeny
+            submitacl: " "
+          - name: parent
+            parent: true
+            submitacl: "*"
+`
+	err := initQueueStructure([]byte(data))
+	assert.NilError(t, err, "setting up the queue config failed")
+	rules := []configs.PlacementRule{
+		{Name: "user",
+			Create: true,
+			Filter: configs.Filter{Type: filterAllow, Users: []string{"nobody"}}},
+		{Name: "provided",
+			Create: false},
+		{Name: "tag",
+			Value:  "namespace",
+			Create: true},
+	}
+	man := NewPlacementManager(rules, queueFunc)
+	user := security.UserGroup{User: "any-user"}
+	deny := security.UserGroup{User: "deny-user"}
+	namespace := map[string]string{"namespace": "namespace"}
+
+	type ruleResult struct {
+		queue  string
+		reason string
+	}
+	var tests = []struct {
+		name    string
+		queue   string
+		tags    map[string]string
+		user    security.UserGroup
+		placed  string
+		rule    string
+		exists  bool
+		results []ruleResult
+	}{
+		{"rejected", "unknown", nil, user, "", "", false, []ruleResult{
+			{"", dao.PlacementSkipFilterDenied}, {"", dao.PlacementSkipQueueNotExist}, {"", dao.PlacementSkipTagMissing}, {"", dao.PlacementSkipNotForced}}},
+		{"provided parent", "root.parent", nil, user, "", "", false, []ruleResult{
+			{"", dao.PlacementSkipFilterDenied}, {"root.parent", dao.PlacementSkipParentQueue}, {"", dao.PlacementSkipTagMissing}, {"", dao.PlacementSkipNotForced}}},
+		{"acl deny", "root.acldeny", nil, deny, "", "", false, []ruleResult{
+			{"", dao.PlacementSkipFilterDenied}, {"root.acldeny", dao.PlacementSkipSubmitDenied}, {"", dao.PlacementSkipTagMissing}, {"", dao.PlacementSkipNotForced}}},
+		{"provided", "root.provided", nil, user, "root.provided", types.Provided, true, []ruleResult{
+			{"", dao.PlacementSkipFilterDenied}, {"root.provided", ""}}},
+		{"create", "unknown", namespace, user, "root.namespace", types.Tag, false, []ruleResult{
+			{"", dao.PlacementSkipFilterDenied}, {"", dao.PlacementSkipQueueNotExist}, {"root.namespace", ""}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newApplication("app1", "default", tt.queue, tt.user, tt.tags, nil, "")
+			result := man.DryRun(app)
+			assert.Equal(t, app.GetQueuePath(), tt.queue, "dry run should not change the application")
+			assert.Equal(t, result.Queue, tt.placed, "unexpected queue")
+			assert.Equal(t, result.Rule, tt.rule, "unexpected matching rule")
+			assert.Equal(t, result.QueueExists, tt.exists, "unexpected queue exists flag")
+			if tt.placed == "" {
+				assert.Equal(t, result.Error, RejectedError.Error(), "unexpected error")
+			}
+			assert.Equal(t, len(result.Rules), len(tt.results), "unexpected number of rule results")
+			for i, expected := range tt.results {
+				assert.Equal(t, result.Rules[i].Queue, expected.queue, "unexpected queue for rule %d", i)
+				assert.Equal(t, result.Rules[i].SkipReason, expected.reason, "unexpected skip reason for rule %d", i)
+				assert.Equal(t, result.Rules[i].Matched, expected.reason == "" && tt.placed != "", "unexpected match for rule %d", i)
+			}
+		})
+	}
+}
+
 func TestManagerPlaceApp_Error(t *testing.T) {
 	// Create the structure for the test
 	data := `
@@ -69,10 +69,11 @@ func (pr *providedRule) initialise(conf configs.PlacementRule) error {
 	return err
 }
 
-func (pr *providedRule) placeApplication(app *objects.Application, queueFn func(string) *objects.Queue) (string, error) {
+func (pr *providedRule) placeApplication(app *objects.Application, queueFn func(string) *objects.Queue, trace *ruleTrace) (string, error) {
 	// since this is the provided rule we must have a queue in the info already
 	queueName := app.GetQueuePath()
 	if queueName == "" {
+		trace.skip(dao.PlacementSkipNoQueue)
 		return "", nil
 	}
 
@@ -81,6 +82,7 @@ func (pr *providedRule) placeApplication(app *objects.Application, queueFn func(
 		log.Log(log.SchedApplication).Debug("Provided rule filtered",
 			zap.String("application", app.ApplicationID),
 			zap.Any("user", app.GetUser()))
+		trace.skip(dao.PlacementSkipFilterDenied)
 		return "", nil
 	}
 	var parentName string
@@ -102,7 +104,7 @@ func (pr *providedRule) placeApplication(app *objects.Application, queueFn func(
 		}
 		// run the parent rule if set
 		if pr.parent != nil {
-			parentName, err = pr.parent.placeApplication(app, queueFn)
+			parentName, err = pr.parent.placeApplication(app, queueFn, trace)
 			// failed parent rule, fail this rule
 			if err != nil {
 				return "", err
@@ -136,6 +138,7 @@ func (pr *providedRule) placeApplication(app *objects.Application, queueFn func(
 	queue := queueFn(queueName)
 	// if we cannot create the queue must exist
 	if !pr.create && queue == nil {
+		trace.skip(dao.PlacementSkipQueueNotExist)
 		return "", nil
 	}
 	log.Log(log.SchedApplication).Info("Provided rule application placed",
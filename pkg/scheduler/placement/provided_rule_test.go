@@ -59,19 +59,19 @@ partitions:
 	// queue that does not exists directly under the root
 	appInfo := newApplication("app1", "default", "unknown", user, tags, nil, "")
 	var queue string
-	queue, err = pr.placeApplication(appInfo, queueFunc)
+	queue, err = pr.placeApplication(appInfo, queueFunc, nil)
 	if queue != "" || err != nil {
 		t.Errorf("provided rule placed app in incorrect queue '%s', err %v", queue, err)
 	}
 	// trying to place when no queue provided in the app
 	appInfo = newApplication("app1", "default", "", user, tags, nil, "")
-	queue, err = pr.placeApplication(appInfo, queueFunc)
+	queue, err = pr.placeApplication(appInfo, queueFunc, nil)
 	if queue != "" || err != nil {
 		t.Errorf("provided rule placed app in incorrect queue '%s', error %v", queue, err)
 	}
 	// trying to place in a qualified queue that does not exist
 	appInfo = newApplication("app1", "default", "root.unknown", user, tags, nil, "")
-	queue, err = pr.placeApplication(appInfo, queueFunc)
+	queue, err = pr.placeApplication(appInfo, queueFunc, nil)
 	if queue != "" || err != nil {
 		t.Errorf("provided rule placed app in incorrect queue '%s', error %v", queue, err)
 	}
@@ -84,7 +84,7 @@ partitions:
 	if err != nil || pr == nil {
 		t.Errorf("provided rule create failed, err %v", err)
 	}
-	queue, err = pr.placeApplication(appInfo, queueFunc)
+	queue, err = pr.placeApplication(appInfo, queueFunc, nil)
 	if queue != "root.unknown" || err != nil {
 		t.Errorf("provided rule placed app in incorrect queue '%s', error %v", queue, err)
 	}
@@ -99,7 +99,7 @@ partitions:
 	if err != nil || pr == nil {
 		t.Errorf("provided rule create failed, err %v", err)
 	}
-	_, err = pr.placeApplication(appInfo, queueFunc)
+	_, err = pr.placeApplication(appInfo, queueFunc, nil)
 	if err == nil {
 		t.Errorf("provided rule should have failed to place app, error %v", err)
 	}
@@ -118,21 +118,21 @@ partitions:
 
 	// unqualified queue with parent rule that exists directly in hierarchy
 	appInfo = newApplication("app1", "default", "testchild", user, tags, nil, "")
-	queue, err = pr.placeApplication(appInfo, queueFunc)
+	queue, err = pr.placeApplication(appInfo, queueFunc, nil)
 	assert.NilError(t, err)
 	assert.Equal(t, "root.testparent.testchild", queue)
 
 	// qualified queue with parent rule (parent rule ignored)
 	appInfo = newApplication("app1", "default", "root.testparent", user, tags, nil, "")
 
-	queue, err = pr.placeApplication(appInfo, queueFunc)
+	queue, err = pr.placeApplication(appInfo, queueFunc, nil)
 	if queue != "root.testparent" || err != nil {
 		t.Errorf("provided rule placed in to be created queue with create false '%s', err %v", queue, err)
 	}
 
 	// invalid queue with parent rule (parent rule ignored)
 	appInfo = newApplication("app1", "default", "root.testp!arent", user, tags, nil, "")
-	_, err = pr.placeApplication(appInfo, queueFunc)
+	_, err = pr.placeApplication(appInfo, queueFunc, nil)
 	if err == nil {
 		t.Errorf("provided rule should have failed to place app, error %v", err)
 	}
@@ -149,7 +149,7 @@ partitions:
 	}
 
 	appInfo = newApplication("app1", "default", "testchild", user, tags, nil, "")
-	queue, err = pr.placeApplication(appInfo, queueFunc)
+	queue, err = pr.placeApplication(appInfo, queueFunc, nil)
 	if queue != "" || err != nil {
 		t.Errorf("provided rule with deny filter type should got empty queue, err nil")
 	}
@@ -183,7 +183,7 @@ func TestProvidedRuleParent(t *testing.T) {
 
 	appInfo := newApplication("app1", "default", "unknown", user, tags, nil, "")
 	var queue string
-	queue, err = pr.placeApplication(appInfo, queueFunc)
+	queue, err = pr.placeApplication(appInfo, queueFunc, nil)
 	if queue != "" || err != nil {
 		t.Errorf("provided rule placed app in incorrect queue '%s', err %v", queue, err)
 	}
@@ -204,7 +204,7 @@ func TestProvidedRuleParent(t *testing.T) {
 	}
 
 	appInfo = newApplication("app1", "default", "testchild", user, tags, nil, "")
-	queue, err = pr.placeApplication(appInfo, queueFunc)
+	queue, err = pr.placeApplication(appInfo, queueFunc, nil)
 	if queue != "" || err != nil {
 		t.Errorf("provided rule placed app in incorrect queue '%s', err %v", queue, err)
 	}
@@ -223,7 +223,7 @@ func TestProvidedRuleParent(t *testing.T) {
 	if err != nil || pr == nil {
 		t.Errorf("provided rule create failed, err %v", err)
 	}
-	queue, err = pr.placeApplication(appInfo, queueFunc)
+	queue, err = pr.placeApplication(appInfo, queueFunc, nil)
 	if queue != nameParentChild || err != nil {
 		t.Errorf("provided rule with non existing parent queue should create '%s', error %v", queue, err)
 	}
@@ -243,7 +243,7 @@ func TestProvidedRuleParent(t *testing.T) {
 		t.Errorf("provided rule create failed, err %v", err)
 	}
 	appInfo = newApplication("app1", "default", "testc!hild", user, tags, nil, "")
-	_, err = pr.placeApplication(appInfo, queueFunc)
+	_, err = pr.placeApplication(appInfo, queueFunc, nil)
 	if err == nil {
 		t.Errorf("provided rule with non existing parent invalid queue should have failed to create, error %v", err)
 	}
@@ -263,7 +263,7 @@ func TestProvidedRuleParent(t *testing.T) {
 	}
 
 	appInfo = newApplication("app1", "default", "unknown", user, tags, nil, "")
-	queue, err = pr.placeApplication(appInfo, queueFunc)
+	queue, err = pr.placeApplication(appInfo, queueFunc, nil)
 	if queue != "" || err == nil {
 		t.Errorf("provided rule placed app in incorrect queue '%s', err %v", queue, err)
 	}
@@ -287,7 +287,7 @@ func TestProvidedRuleParent(t *testing.T) {
 	}
 
 	appInfo = newApplication("app1", "default", "unknown", user, tags, nil, "")
-	queue, err = pr.placeApplication(appInfo, queueFunc)
+	queue, err = pr.placeApplication(appInfo, queueFunc, nil)
 	if queue != "" || err == nil {
 		t.Errorf("provided rule placed app in incorrect queue '%s', err %v", queue, err)
 	}
@@ -311,7 +311,7 @@ func TestProvidedRuleParent(t *testing.T) {
 	}
 
 	appInfo = newApplication("app1", "default", "unknown", user, tags, nil, "")
-	queue, err = pr.placeApplication(appInfo, queueFunc)
+	queue, err = pr.placeApplication(appInfo, queueFunc, nil)
 	if queue != "root.root.unknown.unknown" || err != nil {
 		t.Errorf("provided rule placed app in incorrect queue '%s', err %v", queue, err)
 	}
@@ -54,9 +54,10 @@ func (rr *recoveryRule) initialise(_ configs.PlacementRule) error {
 	return nil
 }
 
-func (rr *recoveryRule) placeApplication(app *objects.Application, _ func(string) *objects.Queue) (string, error) {
+func (rr *recoveryRule) placeApplication(app *objects.Application, _ func(string) *objects.Queue, trace *ruleTrace) (string, error) {
 	// only forced applications should resolve to the recovery queue
 	if !app.IsCreateForced() {
+		trace.skip(dao.PlacementSkipNotForced)
 		return "", nil
 	}
 
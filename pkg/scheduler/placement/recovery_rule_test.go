@@ -53,14 +53,14 @@ func TestRecoveryRulePlace(t *testing.T) {
 	app := newApplication("app1", "default", "ignored", user, tags, nil, "")
 
 	var queue string
-	queue, err = rr.placeApplication(app, queueFunc)
+	queue, err = rr.placeApplication(app, queueFunc, nil)
 	if queue != "" || err != nil {
 		t.Errorf("recovery rule did not bypass non-forced application, resolved queue '%s', err %v ", queue, err)
 	}
 
 	tags[siCommon.AppTagCreateForce] = "true"
 	app = newApplication("app1", "default", "ignored", user, tags, nil, "")
-	queue, err = rr.placeApplication(app, queueFunc)
+	queue, err = rr.placeApplication(app, queueFunc, nil)
 	if queue != common.RecoveryQueueFull || err != nil {
 		t.Errorf("recovery rule did not place forced application into recovery queue, resolved queue '%s', err %v ", queue, err)
 	}
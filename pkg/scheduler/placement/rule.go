@@ -40,7 +40,8 @@ type rule interface {
 	// Execute the rule and return the queue getName the application is placed in.
 	// Returns the fully qualified queue getName if the rule finds a queue or an empty string if the rule did not match.
 	// The error must only be set if there is a failure while executing the rule not if the rule did not match.
-	placeApplication(app *objects.Application, queueFn func(string) *objects.Queue) (string, error)
+	// If the rule did not match the reason is recorded in the trace, the trace is nil unless this is a dry run.
+	placeApplication(app *objects.Application, queueFn func(string) *objects.Queue, trace *ruleTrace) (string, error)
 
 	// Return the getName of the rule which is defined in the rule.
 	// The basicRule provides a "unnamed rule" implementation.
@@ -144,3 +145,23 @@ func normalise(name string) string {
 func replaceDot(name string) string {
 	return strings.ReplaceAll(name, configs.DOT, configs.DotReplace)
 }
+
+// ruleTrace records why a rule did not return a queue during a dry run placement.
+// A nil trace is ignored. The first reason recorded wins: a parent rule that does not match is the cause of the
+// child rule not matching.
+type ruleTrace struct {
+	reason string
+}
+
+func (t *ruleTrace) skip(reason string) {
+	if t != nil && t.reason == "" {
+		t.reason = reason
+	}
+}
+
+func (t *ruleTrace) getReason() string {
+	if t == nil {
+		return ""
+	}
+	return t.reason
+}
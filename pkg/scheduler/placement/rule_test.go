@@ -71,27 +71,27 @@ func TestPlaceApp(t *testing.T) {
 	nr, err := newRule(conf)
 	assert.NilError(t, err, "unexpected rule initialisation error")
 	// place application that should fail
-	_, err = nr.placeApplication(nil, nil)
+	_, err = nr.placeApplication(nil, nil, nil)
 	if err == nil {
 		t.Error("test rule place application did not fail as expected")
 	}
 	var queue string
 	// place application that should not fail and return "test"
-	queue, err = nr.placeApplication(&objects.Application{}, nil)
+	queue, err = nr.placeApplication(&objects.Application{}, nil, nil)
 	if err != nil || queue != "test" {
 		t.Errorf("test rule place application did not fail, err: %v, ", err)
 	}
 	// place application that should not fail and return the queue in the object
 	app := &objects.Application{}
 	app.SetQueuePath("passedin")
-	queue, err = nr.placeApplication(app, nil)
+	queue, err = nr.placeApplication(app, nil, nil)
 	if err != nil || queue != "passedin" {
 		t.Errorf("test rule place application did not fail, err: %v, ", err)
 	}
 	// place application that should not fail and return the queue in the object
 	app = &objects.Application{}
 	app.SetQueuePath("user.name")
-	queue, err = nr.placeApplication(app, nil)
+	queue, err = nr.placeApplication(app, nil, nil)
 	if err != nil || queue != "user_dot_name" {
 		t.Errorf("test rule place application did not fail, err: %v, ", err)
 	}
@@ -75,10 +75,11 @@ func (tr *tagRule) initialise(conf configs.PlacementRule) error {
 	return err
 }
 
-func (tr *tagRule) placeApplication(app *objects.Application, queueFn func(string) *objects.Queue) (string, error) {
+func (tr *tagRule) placeApplication(app *objects.Application, queueFn func(string) *objects.Queue, trace *ruleTrace) (string, error) {
 	// if the tag is not present we can skipp all other processing
 	tagVal := app.GetTag(tr.tagName)
 	if tagVal == "" {
+		trace.skip(dao.PlacementSkipTagMissing)
 		return "", nil
 	}
 	// before anything run the filter
@@ -87,6 +88,7 @@ func (tr *tagRule) placeApplication(app *objects.Application, queueFn func(strin
 			zap.String("application", app.ApplicationID),
 			zap.Any("user", app.GetUser()),
 			zap.String("tagName", tr.tagName))
+		trace.skip(dao.PlacementSkipFilterDenied)
 		return "", nil
 	}
 	var parentName string
@@ -108,7 +110,7 @@ func (tr *tagRule) placeApplication(app *objects.Application, queueFn func(strin
 		}
 		// run the parent rule if set
 		if tr.parent != nil {
-			parentName, err = tr.parent.placeApplication(app, queueFn)
+			parentName, err = tr.parent.placeApplication(app, queueFn, trace)
 			// failed parent rule, fail this rule
 			if err != nil {
 				return "", err
@@ -141,6 +143,7 @@ func (tr *tagRule) placeApplication(app *objects.Application, queueFn func(strin
 	queue := queueFn(queueName)
 	// if we cannot create the queue it must exist, rule does not match otherwise
 	if !tr.create && queue == nil {
+		trace.skip(dao.PlacementSkipQueueNotExist)
 		return "", nil
 	}
 	log.Log(log.SchedApplication).Info("Tag rule application placed",
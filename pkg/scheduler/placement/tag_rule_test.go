@@ -82,7 +82,7 @@ func TestTagRulePlace(t *testing.T) {
 	tags := make(map[string]string)
 	appInfo := newApplication("app1", "default", "ignored", user, tags, nil, "")
 	var queue string
-	queue, err = tr.placeApplication(appInfo, queueFunc)
+	queue, err = tr.placeApplication(appInfo, queueFunc, nil)
 	if queue != "" || err != nil {
 		t.Errorf("tag rule failed with no tag value '%s', err %v", queue, err)
 	}
@@ -90,7 +90,7 @@ func TestTagRulePlace(t *testing.T) {
 	// tag queue that exists directly in hierarchy
 	tags = map[string]string{"label1": "testqueue"}
 	appInfo = newApplication("app1", "default", "ignored", user, tags, nil, "")
-	queue, err = tr.placeApplication(appInfo, queueFunc)
+	queue, err = tr.placeApplication(appInfo, queueFunc, nil)
 	if queue != "root.testqueue" || err != nil {
 		t.Errorf("tag rule failed to place queue in correct queue '%s', err %v", queue, err)
 	}
@@ -98,7 +98,7 @@ func TestTagRulePlace(t *testing.T) {
 	// tag invalid queue
 	tags = map[string]string{"label1": "test!queue"}
 	appInfo = newApplication("app1", "default", "ignored", user, tags, nil, "")
-	_, err = tr.placeApplication(appInfo, queueFunc)
+	_, err = tr.placeApplication(appInfo, queueFunc, nil)
 	if err == nil {
 		t.Errorf("tag rule should have failed to place app, err %v", err)
 	}
@@ -106,7 +106,7 @@ func TestTagRulePlace(t *testing.T) {
 	// tag queue that does not exists
 	tags = map[string]string{"label1": "unknown"}
 	appInfo = newApplication("app1", "default", "ignored", user, tags, nil, "")
-	queue, err = tr.placeApplication(appInfo, queueFunc)
+	queue, err = tr.placeApplication(appInfo, queueFunc, nil)
 	if queue != "" || err != nil {
 		t.Errorf("tag rule placed in queue that does not exists '%s', err %v", queue, err)
 	}
@@ -114,7 +114,7 @@ func TestTagRulePlace(t *testing.T) {
 	// tag queue fully qualified
 	tags = map[string]string{"label1": "root.testparent.testchild"}
 	appInfo = newApplication("app1", "default", "ignored", user, tags, nil, "")
-	queue, err = tr.placeApplication(appInfo, queueFunc)
+	queue, err = tr.placeApplication(appInfo, queueFunc, nil)
 	if queue != "root.testparent.testchild" || err != nil {
 		t.Errorf("tag rule did fail with qualified queue '%s', error %v", queue, err)
 	}
@@ -122,7 +122,7 @@ func TestTagRulePlace(t *testing.T) {
 	// tag invalid queue fully qualified
 	tags = map[string]string{"label1": "root.testparent.test!child"}
 	appInfo = newApplication("app1", "default", "ignored", user, tags, nil, "")
-	_, err = tr.placeApplication(appInfo, queueFunc)
+	_, err = tr.placeApplication(appInfo, queueFunc, nil)
 	if err == nil {
 		t.Errorf("tag rule should have failed with fully qualified invalid queue, error %v", err)
 	}
@@ -130,7 +130,7 @@ func TestTagRulePlace(t *testing.T) {
 	// tag queue references recovery
 	tags = map[string]string{"label1": common.RecoveryQueueFull}
 	appInfo = newApplication("app1", "default", "ignored", user, tags, nil, "")
-	queue, err = tr.placeApplication(appInfo, queueFunc)
+	queue, err = tr.placeApplication(appInfo, queueFunc, nil)
 	if queue != "" || err != nil {
 		t.Errorf("tag rule failed with explicit recovery queue: queue '%s', error %v", queue, err)
 	}
@@ -150,20 +150,20 @@ func TestTagRulePlace(t *testing.T) {
 	}
 	tags = map[string]string{"label1": "testchild"}
 	appInfo = newApplication("app1", "default", "ignored", user, tags, nil, "")
-	queue, err = tr.placeApplication(appInfo, queueFunc)
+	queue, err = tr.placeApplication(appInfo, queueFunc, nil)
 	if queue != "" || err != nil {
 		t.Errorf("tag rule with parent queue should have failed value not set '%s', error %v", queue, err)
 	}
 	tags = map[string]string{"label1": "testchild", "label2": "testparent"}
 	appInfo = newApplication("app1", "default", "ignored", user, tags, nil, "")
-	queue, err = tr.placeApplication(appInfo, queueFunc)
+	queue, err = tr.placeApplication(appInfo, queueFunc, nil)
 	if queue != "root.testparent.testchild" || err != nil {
 		t.Errorf("tag rule with parent queue incorrect queue '%s', error %v", queue, err)
 	}
 
 	tags = map[string]string{"label1": "testchild", "label2": "testp!arent"}
 	appInfo = newApplication("app1", "default", "ignored", user, tags, nil, "")
-	_, err = tr.placeApplication(appInfo, queueFunc)
+	_, err = tr.placeApplication(appInfo, queueFunc, nil)
 	if err == nil {
 		t.Errorf("tag rule with parent queue should have failed, error %v", err)
 	}
@@ -181,7 +181,7 @@ func TestTagRulePlace(t *testing.T) {
 		t.Errorf("tag rule create failed with parent rule and qualified value, err %v", err)
 	}
 	appInfo = newApplication("app1", "default", "ignored", user, tags, nil, "")
-	queue, err = tr.placeApplication(appInfo, queueFunc)
+	queue, err = tr.placeApplication(appInfo, queueFunc, nil)
 	if queue != "" || err != nil {
 		t.Errorf("tag rule with deny filter type should got empty queue, err nil")
 	}
@@ -216,7 +216,7 @@ func TestTagRuleParent(t *testing.T) {
 	tags := map[string]string{"label1": "testchild", "label2": "testparent"}
 	appInfo := newApplication("app1", "default", "unknown", user, tags, nil, "")
 	var queue string
-	queue, err = ur.placeApplication(appInfo, queueFunc)
+	queue, err = ur.placeApplication(appInfo, queueFunc, nil)
 	if queue != "" || err != nil {
 		t.Errorf("tag rule placed app in incorrect queue '%s', err %v", queue, err)
 	}
@@ -239,7 +239,7 @@ func TestTagRuleParent(t *testing.T) {
 
 	tags = map[string]string{"label1": "testchild", "label2": "testparentnew"}
 	appInfo = newApplication("app1", "default", "unknown", user, tags, nil, "")
-	queue, err = ur.placeApplication(appInfo, queueFunc)
+	queue, err = ur.placeApplication(appInfo, queueFunc, nil)
 	if queue != "" || err != nil {
 		t.Errorf("tag rule placed app in incorrect queue '%s', err %v", queue, err)
 	}
@@ -259,7 +259,7 @@ func TestTagRuleParent(t *testing.T) {
 	if err != nil || ur == nil {
 		t.Errorf("tag rule create failed with queue name, err %v", err)
 	}
-	queue, err = ur.placeApplication(appInfo, queueFunc)
+	queue, err = ur.placeApplication(appInfo, queueFunc, nil)
 	if queue != nameParentChild || err != nil {
 		t.Errorf("user rule with non existing parent queue should create '%s', error %v", queue, err)
 	}
@@ -280,7 +280,7 @@ func TestTagRuleParent(t *testing.T) {
 	}
 
 	appInfo = newApplication("app1", "default", "unknown", user, tags, nil, "")
-	queue, err = ur.placeApplication(appInfo, queueFunc)
+	queue, err = ur.placeApplication(appInfo, queueFunc, nil)
 	if queue != "" || err == nil {
 		t.Errorf("tag rule placed app in incorrect queue '%s', err %v", queue, err)
 	}
@@ -304,7 +304,7 @@ func TestTagRuleParent(t *testing.T) {
 	}
 
 	appInfo = newApplication("app1", "default", "unknown", user, tags, nil, "")
-	queue, err = ur.placeApplication(appInfo, queueFunc)
+	queue, err = ur.placeApplication(appInfo, queueFunc, nil)
 	if queue != "" || err == nil {
 		t.Errorf("tag rule placed app in incorrect queue '%s', err %v", queue, err)
 	}
@@ -329,7 +329,7 @@ func TestTagRuleParent(t *testing.T) {
 		t.Errorf("tag rule create failed, err %v", err)
 	}
 	appInfo = newApplication("app1", "default", "unknown", user, tags, nil, "")
-	queue, err = ur.placeApplication(appInfo, queueFunc)
+	queue, err = ur.placeApplication(appInfo, queueFunc, nil)
 	if queue != "root.root.testparentnew.testparentnew" || err != nil {
 		t.Errorf("tag rule placed app in incorrect queue '%s', err %v", queue, err)
 	}
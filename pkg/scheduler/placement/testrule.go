@@ -66,7 +66,7 @@ func (tr *testRule) initialise(conf configs.PlacementRule) error {
 }
 
 // Simple test rule that just checks the app passed in and returns fixed queue names.
-func (tr *testRule) placeApplication(app *objects.Application, queueFn func(string) *objects.Queue) (string, error) {
+func (tr *testRule) placeApplication(app *objects.Application, queueFn func(string) *objects.Queue, _ *ruleTrace) (string, error) {
 	if app == nil {
 		return "", fmt.Errorf("nil app passed in")
 	}
@@ -141,14 +141,14 @@ partitions:
 	}
 	appInfo := newApplication("app1", "default", "testchild", user, tags, nil, "")
 	var queue string
-	queue, err = pr.placeApplication(appInfo, queueFunc)
+	queue, err = pr.placeApplication(appInfo, queueFunc, nil)
 	if queue != "testchild" || err != nil {
 		t.Errorf("test rule placed app in incorrect queue '%s', err %v", queue, err)
 	}
 
 	// invalid queueName
 	appInfo = newApplication("app1", "default", "test$child", user, tags, nil, "")
-	queue, err = pr.placeApplication(appInfo, queueFunc)
+	queue, err = pr.placeApplication(appInfo, queueFunc, nil)
 	if queue != "" || err == nil {
 		t.Errorf("invalid queueName should got empty queueName")
 	}
@@ -66,13 +66,14 @@ func (ur *userRule) initialise(conf configs.PlacementRule) error {
 	return err
 }
 
-func (ur *userRule) placeApplication(app *objects.Application, queueFn func(string) *objects.Queue) (string, error) {
+func (ur *userRule) placeApplication(app *objects.Application, queueFn func(string) *objects.Queue, trace *ruleTrace) (string, error) {
 	// before anything run the filter
 	userName := app.GetUser().User
 	if !ur.filter.allowUser(app.GetUser()) {
 		log.Log(log.SchedApplication).Debug("User rule filtered",
 			zap.String("application", app.ApplicationID),
 			zap.Any("user", app.GetUser()))
+		trace.skip(dao.PlacementSkipFilterDenied)
 		return "", nil
 	}
 	childQueueName := replaceDot(userName)
@@ -83,7 +84,7 @@ func (ur *userRule) placeApplication(app *objects.Application, queueFn func(stri
 	var err error
 	// run the parent rule if set
 	if ur.parent != nil {
-		parentName, err = ur.parent.placeApplication(app, queueFn)
+		parentName, err = ur.parent.placeApplication(app, queueFn, trace)
 		// failed parent rule, fail this rule
 		if err != nil {
 			return "", err
@@ -115,6 +116,7 @@ func (ur *userRule) placeApplication(app *objects.Application, queueFn func(stri
 	queue := queueFn(queueName)
 	// if we cannot create the queue it must exist, rule does not match otherwise
 	if !ur.create && queue == nil {
+		trace.skip(dao.PlacementSkipQueueNotExist)
 		return "", nil
 	}
 	log.Log(log.SchedApplication).Info("User rule application placed",
@@ -73,12 +73,12 @@ partitions:
 			appInfo := newApplication("app1", "default", "ignored", tt.user, tags, nil, "")
 			var queue string
 			if tt.nilError {
-				queue, err = ur.placeApplication(appInfo, queueFunc)
+				queue, err = ur.placeApplication(appInfo, queueFunc, nil)
 				if queue != tt.expectedQueue || err != nil {
 					t.Errorf("user rule failed to place queue in correct queue '%s', err %v", queue, err)
 				}
 			} else {
-				_, err = ur.placeApplication(appInfo, queueFunc)
+				_, err = ur.placeApplication(appInfo, queueFunc, nil)
 				if err == nil {
 					t.Errorf("user rule should have failed to place queue, err %v", err)
 				}
@@ -115,7 +115,7 @@ func TestUserRuleParent(t *testing.T) {
 
 	appInfo := newApplication("app1", "default", "unknown", user, tags, nil, "")
 	var queue string
-	queue, err = ur.placeApplication(appInfo, queueFunc)
+	queue, err = ur.placeApplication(appInfo, queueFunc, nil)
 	if queue != "" || err != nil {
 		t.Errorf("user rule placed app in incorrect queue '%s', err %v", queue, err)
 	}
@@ -136,7 +136,7 @@ func TestUserRuleParent(t *testing.T) {
 	}
 
 	appInfo = newApplication("app1", "default", "unknown", user, tags, nil, "")
-	queue, err = ur.placeApplication(appInfo, queueFunc)
+	queue, err = ur.placeApplication(appInfo, queueFunc, nil)
 	if queue != "" || err != nil {
 		t.Errorf("user rule placed app in incorrect queue '%s', err %v", queue, err)
 	}
@@ -155,7 +155,7 @@ func TestUserRuleParent(t *testing.T) {
 	if err != nil || ur == nil {
 		t.Errorf("user rule create failed with queue name, err %v", err)
 	}
-	queue, err = ur.placeApplication(appInfo, queueFunc)
+	queue, err = ur.placeApplication(appInfo, queueFunc, nil)
 	if queue != nameParentChild || err != nil {
 		t.Errorf("user rule with non existing parent queue should create '%s', error %v", queue, err)
 	}
@@ -165,7 +165,7 @@ func TestUserRuleParent(t *testing.T) {
 		Groups: []string{},
 	}
 	appInfo1 := newApplication("app1", "default", "unknown", user1, tags, nil, "")
-	_, err = ur.placeApplication(appInfo1, queueFunc)
+	_, err = ur.placeApplication(appInfo1, queueFunc, nil)
 	if err == nil {
 		t.Errorf("user rule with non existing parent queue and invalid child queue should have failed, error %v", err)
 	}
@@ -185,7 +185,7 @@ func TestUserRuleParent(t *testing.T) {
 	}
 
 	appInfo = newApplication("app1", "default", "unknown", user, tags, nil, "")
-	queue, err = ur.placeApplication(appInfo, queueFunc)
+	queue, err = ur.placeApplication(appInfo, queueFunc, nil)
 	if queue != "" || err == nil {
 		t.Errorf("user rule placed app in incorrect queue '%s', err %v", queue, err)
 	}
@@ -208,7 +208,7 @@ func TestUserRuleParent(t *testing.T) {
 		t.Errorf("user rule create failed, err %v", err)
 	}
 	appInfo = newApplication("app1", "default", "unknown", user, tags, nil, "")
-	queue, err = ur.placeApplication(appInfo, queueFunc)
+	queue, err = ur.placeApplication(appInfo, queueFunc, nil)
 	if queue != "" || err == nil {
 		t.Errorf("user rule placed app in incorrect queue '%s', err %v", queue, err)
 	}
@@ -227,7 +227,7 @@ func TestUserRuleParent(t *testing.T) {
 		t.Errorf("user rule create failed, err %v", err)
 	}
 	appInfo = newApplication("app1", "default", "unknown", user, tags, nil, "")
-	queue, err = ur.placeApplication(appInfo, queueFunc)
+	queue, err = ur.placeApplication(appInfo, queueFunc, nil)
 	if queue != "root.root.testchild" || err != nil {
 		t.Errorf("user rule placed app in incorrect queue '%s', err %v", queue, err)
 	}
@@ -31,11 +31,14 @@ const (
 	FifoSortPolicy             SortPolicy = iota // first in first out, submit time
 	FairSortPolicy                               // fair based on usage
 	deprecatedStateAwarePolicy                   // deprecated: now alias for FIFO
+	DrfSortPolicy                                // dominant resource fairness based on partition capacity
+	UsageHistorySortPolicy                       // fair based on the decayed usage over time
+	UserFairSortPolicy                           // round-robin between users based on their usage in the queue
 	Undefined                                    // not initialised or parsing failed
 )
 
 func (s SortPolicy) String() string {
-	return [...]string{"fifo", "fair", "stateaware", "undefined"}[s]
+	return [...]string{"fifo", "fair", "stateaware", "drf", "history", "userfair", "undefined"}[s]
 }
 
 func SortPolicyFromString(str string) (SortPolicy, error) {
@@ -45,6 +48,12 @@ func SortPolicyFromString(str string) (SortPolicy, error) {
 		return FifoSortPolicy, nil
 	case FairSortPolicy.String():
 		return FairSortPolicy, nil
+	case DrfSortPolicy.String():
+		return DrfSortPolicy, nil
+	case UsageHistorySortPolicy.String():
+		return UsageHistorySortPolicy, nil
+	case UserFairSortPolicy.String():
+		return UserFairSortPolicy, nil
 	case deprecatedStateAwarePolicy.String():
 		log.Log(log.Deprecation).Warn("Sort policy 'stateaware' is deprecated; using 'fifo' instead")
 		return FifoSortPolicy, nil
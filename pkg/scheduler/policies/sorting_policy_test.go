@@ -32,6 +32,9 @@ func TestAppFromString(t *testing.T) {
 		{"EmptyString", "", FifoSortPolicy, false},
 		{"FifoString", "fifo", FifoSortPolicy, false},
 		{"FairString", "fair", FairSortPolicy, false},
+		{"DrfString", "drf", DrfSortPolicy, false},
+		{"HistoryString", "history", UsageHistorySortPolicy, false},
+		{"UserFairString", "userfair", UserFairSortPolicy, false},
 		{"StatusString", "stateaware", FifoSortPolicy, false},
 		{"UnknownString", "unknown", Undefined, true},
 	}
@@ -56,6 +59,9 @@ func TestAppToString(t *testing.T) {
 	}{
 		{"FifoString", FifoSortPolicy, "fifo"},
 		{"FairString", FairSortPolicy, "fair"},
+		{"DrfString", DrfSortPolicy, "drf"},
+		{"HistoryString", UsageHistorySortPolicy, "history"},
+		{"UserFairString", UserFairSortPolicy, "userfair"},
 		{"StatusString", deprecatedStateAwarePolicy, "stateaware"},
 		{"DefaultString", Undefined, "undefined"},
 		{"NoneString", someSP, "fifo"},
@@ -24,14 +24,18 @@ import (
 
 	"go.uber.org/zap"
 
+	"github.com/G-Research/yunikorn-core/pkg/common/configs"
 	"github.com/G-Research/yunikorn-core/pkg/common/resources"
 	"github.com/G-Research/yunikorn-core/pkg/handler"
 	"github.com/G-Research/yunikorn-core/pkg/log"
+	"github.com/G-Research/yunikorn-core/pkg/metrics"
 	"github.com/G-Research/yunikorn-core/pkg/plugins"
 	"github.com/G-Research/yunikorn-core/pkg/rmproxy/rmevent"
 	"github.com/G-Research/yunikorn-scheduler-interface/lib/go/si"
 )
 
+const usageHalfLifeCallback = "usage-half-life"
+
 // Main Scheduler service that starts the needed sub services
 type Scheduler struct {
 	clusterContext  *ClusterContext  // main context
@@ -67,12 +71,33 @@ func (s *Scheduler) StartService(handlers handler.EventHandlers, manualSchedule
 	s.healthChecker = NewHealthChecker(s.clusterContext)
 	s.healthChecker.Start()
 
+	// half-life of the usage history of queues and users
+	updateUsageHalfLife()
+	configs.AddConfigMapCallback(usageHalfLifeCallback, updateUsageHalfLife)
+
 	if !manualSchedule {
 		go s.internalSchedule()
 		go s.internalInspectOutstandingRequests()
 	}
 }
 
+// updateUsageHalfLife sets the half-life of the usage history from the configuration.
+func updateUsageHalfLife() {
+	halfLife := resources.DefaultUsageHalfLife
+	if value, ok := configs.GetConfigMap()[configs.CMFairnessUsageHalfLife]; ok {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			log.Log(log.Scheduler).Warn("Failed to parse configuration value, using the default",
+				zap.String("key", configs.CMFairnessUsageHalfLife),
+				zap.String("value", value),
+				zap.Stringer("default", halfLife))
+		} else {
+			halfLife = parsed
+		}
+	}
+	resources.SetUsageHalfLife(halfLife)
+}
+
 // Internal start scheduling service
 func (s *Scheduler) internalSchedule() {
 	for {
@@ -114,11 +139,13 @@ func (s *Scheduler) HandleEvent(ev interface{}) {
 func enqueueAndCheckFull(queue chan interface{}, ev interface{}) {
 	select {
 	case queue <- ev:
+		metrics.GetSchedulerMetrics().SetRMEventQueueDepth(metrics.RMEventQueueScheduler, len(queue))
 		log.Log(log.Scheduler).Debug("enqueued event",
 			zap.Stringer("eventType", reflect.TypeOf(ev)),
 			zap.Any("event", ev),
 			zap.Int("currentQueueSize", len(queue)))
 	default:
+		metrics.GetSchedulerMetrics().IncRMEventsDropped(metrics.RMEventQueueScheduler)
 		log.Log(log.Scheduler).DPanic("failed to enqueue event",
 			zap.Stringer("event", reflect.TypeOf(ev)))
 	}
@@ -128,6 +155,7 @@ func (s *Scheduler) handleRMEvent() {
 	for {
 		select {
 		case ev := <-s.pendingEvents:
+			metrics.GetSchedulerMetrics().SetRMEventQueueDepth(metrics.RMEventQueueScheduler, len(s.pendingEvents))
 			switch v := ev.(type) {
 			case *rmevent.RMUpdateAllocationEvent:
 				s.clusterContext.handleRMUpdateAllocationEvent(v)
@@ -0,0 +1,248 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package ugm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/G-Research/yunikorn-core/pkg/common"
+	"github.com/G-Research/yunikorn-core/pkg/common/configs"
+	"github.com/G-Research/yunikorn-core/pkg/common/resources"
+	"github.com/G-Research/yunikorn-core/pkg/common/security"
+)
+
+// LimitViolation describes a single user or group limit that blocks an application.
+// Either User or Group is set depending on the tracker that imposes the limit.
+// For a resource limit ResourceTypes contains the resource types that do not fit, for an application limit
+// MaxApplications contains the configured maximum.
+type LimitViolation struct {
+	User            string
+	Group           string
+	QueuePath       string
+	ResourceTypes   []string
+	MaxApplications uint64
+}
+
+// ExplainCanRunApp returns the user and group limits that prevent the application from running in the queue.
+// The user limits are returned first, each ordered from the leaf queue to the root. An empty slice is returned
+// when CanRunApp would return true. Read only: no trackers are created or linked.
+func (m *Manager) ExplainCanRunApp(queuePath, applicationID string, user security.UserGroup) []*LimitViolation {
+	hierarchy := strings.Split(queuePath, configs.DOT)
+	userTracker := m.lookupUserTracker(user.User)
+	violations := userTracker.explainCanRunApp(hierarchy, applicationID)
+	if groupTracker := m.lookupGroupTrackerForApp(userTracker, queuePath, applicationID, user); groupTracker != nil {
+		violations = append(violations, groupTracker.explainCanRunApp(hierarchy, applicationID)...)
+	}
+	return violations
+}
+
+// ExplainHeadroom returns the user and group limits that prevent the request from fitting in the headroom of the
+// application. The user limits are returned first, each ordered from the leaf queue to the root. An empty slice is
+// returned when the request fits in the headroom returned by Headroom. Read only: no trackers are created or linked.
+func (m *Manager) ExplainHeadroom(queuePath, applicationID string, user security.UserGroup, request *resources.Resource) []*LimitViolation {
+	hierarchy := strings.Split(queuePath, configs.DOT)
+	userTracker := m.lookupUserTracker(user.User)
+	violations := userTracker.explainHeadroom(hierarchy, request)
+	if groupTracker := m.lookupGroupTrackerForApp(userTracker, queuePath, applicationID, user); groupTracker != nil {
+		violations = append(violations, groupTracker.explainHeadroom(hierarchy, request)...)
+	}
+	return violations
+}
+
+// explainMove returns the first user or group limit that the application would violate after the move, nil if the
+// move fits. The queues above the closest common parent of both paths already hold the usage and the application
+// of the user, and of the group if the group does not change, and are not checked for them.
+func (m *Manager) explainMove(userTracker *UserTracker, fromPath, toPath, applicationID string, usage *resources.Resource, user security.UserGroup) *LimitViolation {
+	hierarchy := strings.Split(toPath, configs.DOT)
+	shared := commonQueuePath(fromPath, toPath)
+	violations := userTracker.explainHeadroom(hierarchy, usage)
+	violations = append(violations, userTracker.explainCanRunApp(hierarchy, applicationID)...)
+	violations = belowQueuePath(violations, shared)
+	targetGroup := m.ensureGroup(user, toPath)
+	if targetGroup != common.Empty {
+		if groupTracker := m.GetGroupTracker(targetGroup); groupTracker != nil {
+			groupViolations := groupTracker.explainHeadroom(hierarchy, usage)
+			groupViolations = append(groupViolations, groupTracker.explainCanRunApp(hierarchy, applicationID)...)
+			if targetGroup == userTracker.getGroupForApp(applicationID) {
+				groupViolations = belowQueuePath(groupViolations, shared)
+			}
+			violations = append(violations, groupViolations...)
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	return violations[0]
+}
+
+// commonQueuePath returns the path of the closest queue that is a parent of, or the same as, both queues.
+func commonQueuePath(first, second string) string {
+	firstHierarchy := strings.Split(first, configs.DOT)
+	secondHierarchy := strings.Split(second, configs.DOT)
+	var shared []string
+	for i := 0; i < len(firstHierarchy) && i < len(secondHierarchy) && firstHierarchy[i] == secondHierarchy[i]; i++ {
+		shared = append(shared, firstHierarchy[i])
+	}
+	return strings.Join(shared, configs.DOT)
+}
+
+// belowQueuePath filters out the violations of the queue path and its parents.
+func belowQueuePath(violations []*LimitViolation, queuePath string) []*LimitViolation {
+	var below []*LimitViolation
+	for _, v := range violations {
+		if queuePath == v.QueuePath || strings.HasPrefix(queuePath, v.QueuePath+configs.DOT) {
+			continue
+		}
+		below = append(below, v)
+	}
+	return below
+}
+
+// moveError returns the error for an application that cannot be moved into the queue because of the violation.
+func (v *LimitViolation) moveError(applicationID, queuePath string) error {
+	tracked := "user " + v.User
+	if v.Group != common.Empty {
+		tracked = "group " + v.Group
+	}
+	if len(v.ResourceTypes) > 0 {
+		return fmt.Errorf("application %s cannot be moved to queue %s: usage of %s would exceed the maximum resources of queue %s for %s",
+			applicationID, queuePath, tracked, v.QueuePath, strings.Join(v.ResourceTypes, ", "))
+	}
+	return fmt.Errorf("application %s cannot be moved to queue %s: %s would exceed the maximum applications (%d) of queue %s",
+		applicationID, queuePath, tracked, v.MaxApplications, v.QueuePath)
+}
+
+// lookupUserTracker returns the tracker of the user. For a user without a tracker a new tracker is returned that is
+// not added to the manager: it has the same wildcard limits as the tracker that would be created for the user.
+func (m *Manager) lookupUserTracker(user string) *UserTracker {
+	if userTracker := m.GetUserTracker(user); userTracker != nil {
+		return userTracker
+	}
+	return newUserTracker(user, m.events)
+}
+
+// lookupGroupTrackerForApp returns the group tracker linked to the application, or the tracker of the group the
+// application would be linked to if that has not happened yet. Returns nil if the application is not tracked as part
+// of a group. The group trackers with limits are created by the configuration, a missing tracker has no limits.
+func (m *Manager) lookupGroupTrackerForApp(userTracker *UserTracker, queuePath, applicationID string, user security.UserGroup) *GroupTracker {
+	var appGroup string
+	if userTracker.hasGroupForApp(applicationID) {
+		appGroup = userTracker.getGroupForApp(applicationID)
+	} else {
+		appGroup = m.ensureGroup(user, queuePath)
+	}
+	if appGroup == common.Empty {
+		return nil
+	}
+	return m.GetGroupTracker(appGroup)
+}
+
+func (ut *UserTracker) explainCanRunApp(hierarchy []string, applicationID string) []*LimitViolation {
+	ut.RLock()
+	defer ut.RUnlock()
+	violations := ut.queueTracker.explainCanRunApp(hierarchy, applicationID, user)
+	for _, v := range violations {
+		v.User = ut.userName
+	}
+	return violations
+}
+
+func (ut *UserTracker) explainHeadroom(hierarchy []string, request *resources.Resource) []*LimitViolation {
+	ut.RLock()
+	defer ut.RUnlock()
+	violations := ut.queueTracker.explainHeadroom(hierarchy, request, user)
+	for _, v := range violations {
+		v.User = ut.userName
+	}
+	return violations
+}
+
+func (gt *GroupTracker) explainCanRunApp(hierarchy []string, applicationID string) []*LimitViolation {
+	gt.RLock()
+	defer gt.RUnlock()
+	violations := gt.queueTracker.explainCanRunApp(hierarchy, applicationID, group)
+	for _, v := range violations {
+		v.Group = gt.groupName
+	}
+	return violations
+}
+
+func (gt *GroupTracker) explainHeadroom(hierarchy []string, request *resources.Resource) []*LimitViolation {
+	gt.RLock()
+	defer gt.RUnlock()
+	violations := gt.queueTracker.explainHeadroom(hierarchy, request, group)
+	for _, v := range violations {
+		v.Group = gt.groupName
+	}
+	return violations
+}
+
+// explainCanRunApp follows the same path as canRunApp but does not stop at the first limit that is reached.
+// Note: Lock free call. The RLock of the linked tracker (UserTracker and GroupTracker) should be held before calling this function.
+func (qt *QueueTracker) explainCanRunApp(hierarchy []string, applicationID string, trackType trackingType) []*LimitViolation {
+	var violations []*LimitViolation
+	if len(hierarchy) > 1 {
+		childName := hierarchy[1]
+		child := qt.childQueueTrackers[childName]
+		if child == nil {
+			// not tracked: a new tracker, that is not linked, has the limits the queue would get
+			child = newQueueTracker(qt.queuePath, childName, trackType)
+		}
+		violations = child.explainCanRunApp(hierarchy[1:], applicationID, trackType)
+	}
+	if qt.runningApplications[applicationID] {
+		return violations
+	}
+	running := len(qt.runningApplications) + 1
+	if qt.maxRunningApps != 0 && running > int(qt.maxRunningApps) {
+		violations = append(violations, &LimitViolation{
+			QueuePath:       qt.queuePath,
+			MaxApplications: qt.maxRunningApps,
+		})
+	}
+	return violations
+}
+
+// explainHeadroom follows the same path as headroom and returns each queue for which the request does not fit in the
+// remaining resources.
+// Note: Lock free call. The RLock of the linked tracker (UserTracker and GroupTracker) should be held before calling this function.
+func (qt *QueueTracker) explainHeadroom(hierarchy []string, request *resources.Resource, trackType trackingType) []*LimitViolation {
+	var violations []*LimitViolation
+	if len(hierarchy) > 1 {
+		childName := hierarchy[1]
+		child := qt.childQueueTrackers[childName]
+		if child == nil {
+			// not tracked: a new tracker, that is not linked, has the limits the queue would get
+			child = newQueueTracker(qt.queuePath, childName, trackType)
+		}
+		violations = child.explainHeadroom(hierarchy[1:], request, trackType)
+	}
+	if resources.IsZero(qt.maxResources) {
+		return violations
+	}
+	headroom := resources.SubOnlyExisting(qt.maxResources, qt.resourceUsage)
+	if notFit := headroom.NotFitInMaxUndef(request); len(notFit) > 0 {
+		violations = append(violations, &LimitViolation{
+			QueuePath:     qt.queuePath,
+			ResourceTypes: notFit,
+		})
+	}
+	return violations
+}
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -48,6 +49,7 @@ type Manager struct {
 	configuredGroups          map[string][]string                // Hold groups for all configured queue paths.
 	userLimits                map[string]map[string]*LimitConfig // Holds queue path * user limit config
 	groupLimits               map[string]map[string]*LimitConfig // Holds queue path * group limit config
+	userUsageHistory          map[string]*resources.DecayedUsage // Usage history of users without a tracker
 	events                    *ugmEvents
 	locking.RWMutex
 }
@@ -58,6 +60,7 @@ func newManager() *Manager {
 		groupTrackers:             make(map[string]*GroupTracker),
 		userWildCardLimitsConfig:  make(map[string]*LimitConfig),
 		groupWildCardLimitsConfig: make(map[string]*LimitConfig),
+		userUsageHistory:          make(map[string]*resources.DecayedUsage),
 		events:                    newUGMEvents(events.GetEventSystem()),
 	}
 	return manager
@@ -159,7 +162,7 @@ func (m *Manager) DecreaseTrackedResource(queuePath, applicationID string, usage
 	if userTracker.decreaseTrackedResource(queuePath, applicationID, usage, removeApp) {
 		log.Log(log.SchedUGM).Info("Removing user from manager",
 			zap.String("user", user.User))
-		delete(m.userTrackers, user.User)
+		m.removeUserTracker(userTracker)
 	}
 	// if the app did not have a group we're done otherwise update the groupTracker
 	if appGroup == common.Empty {
@@ -188,6 +191,27 @@ func (m *Manager) DecreaseTrackedResource(queuePath, applicationID string, usage
 	}
 }
 
+// MoveTrackedResource moves the resource usage of the application from one queue path to another for the user and
+// group of the application, used when an application is moved between queues. The group of the application is
+// resolved again for the new queue path. Nothing changes if the application is not tracked.
+// The move fails without changes if the usage does not fit the headroom, or the application does not fit the
+// maximum applications, of the user or group in the queues that the application is moved into.
+func (m *Manager) MoveTrackedResource(fromPath, toPath, applicationID string, usage *resources.Resource, user security.UserGroup) error {
+	userTracker := m.GetUserTracker(user.User)
+	if userTracker == nil || !userTracker.hasGroupForApp(applicationID) {
+		return nil
+	}
+	if usage == nil {
+		usage = resources.NewResource()
+	}
+	if violation := m.explainMove(userTracker, fromPath, toPath, applicationID, usage, user); violation != nil {
+		return violation.moveError(applicationID, toPath)
+	}
+	m.DecreaseTrackedResource(fromPath, applicationID, usage, user, true)
+	m.IncreaseTrackedResource(toPath, applicationID, usage, user)
+	return nil
+}
+
 func (m *Manager) GetUsersResources() []*UserTracker {
 	m.RLock()
 	defer m.RUnlock()
@@ -630,10 +654,55 @@ func (m *Manager) getUserTracker(user string) *UserTracker {
 	log.Log(log.SchedUGM).Info("User tracker doesn't exists. Creating user tracker.",
 		zap.String("user", user))
 	userTracker := newUserTracker(user, m.events)
+	// continue the usage history of the user if it has not decayed yet
+	if history, ok := m.userUsageHistory[user]; ok {
+		userTracker.usageHistory = history
+		delete(m.userUsageHistory, user)
+	}
 	m.userTrackers[user] = userTracker
 	return userTracker
 }
 
+// removeUserTracker removes the tracker of a user without usage. The usage history of the user is kept until it has
+// decayed, the history of other removed users that has decayed is dropped.
+func (m *Manager) removeUserTracker(ut *UserTracker) {
+	m.Lock()
+	defer m.Unlock()
+	now := time.Now()
+	for userName, history := range m.userUsageHistory {
+		if history.IsNegligible(now) {
+			delete(m.userUsageHistory, userName)
+		}
+	}
+	delete(m.userTrackers, ut.userName)
+	ut.RLock()
+	defer ut.RUnlock()
+	if !ut.usageHistory.IsNegligible(now) {
+		m.userUsageHistory[ut.userName] = ut.usageHistory
+	}
+}
+
+// GetUserDecayedUsage returns the total usage of the user integrated over time with decay, at the time passed in.
+// The history is available while the user has usage and after that until it has decayed.
+func (m *Manager) GetUserDecayedUsage(user string, now time.Time) *resources.Resource {
+	m.RLock()
+	defer m.RUnlock()
+	if ut := m.userTrackers[user]; ut != nil {
+		return ut.getDecayedUsage(now)
+	}
+	return m.userUsageHistory[user].Value(now)
+}
+
+// GetUserQueueUsage returns the resources used by the user in the queue, nil if the user has no usage in the queue.
+func (m *Manager) GetUserQueueUsage(user, queuePath string) *resources.Resource {
+	m.RLock()
+	defer m.RUnlock()
+	if ut := m.userTrackers[user]; ut != nil {
+		return ut.getQueueUsage(strings.Split(queuePath, configs.DOT))
+	}
+	return nil
+}
+
 func (m *Manager) getUserWildCardLimitsConfig(queuePath string) *LimitConfig {
 	if config, ok := m.userWildCardLimitsConfig[queuePath]; ok {
 		return config
@@ -690,6 +759,7 @@ func (m *Manager) ClearUserTrackers() {
 	m.Lock()
 	defer m.Unlock()
 	m.userTrackers = make(map[string]*UserTracker)
+	m.userUsageHistory = make(map[string]*resources.DecayedUsage)
 }
 
 // ClearGroupTrackers only for tests
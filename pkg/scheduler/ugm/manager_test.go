@@ -23,6 +23,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"gotest.tools/v3/assert"
 
@@ -248,6 +249,126 @@ func TestAddRemoveUserAndGroups(t *testing.T) {
 	assert.Assert(t, manager.GetGroupTracker(user.Groups[0]) == nil)
 }
 
+func TestMoveTrackedResource(t *testing.T) {
+	user := security.UserGroup{User: "test", Groups: []string{"test"}}
+	usage := resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 5})
+	manager := GetUserManager()
+	manager.ClearUserTrackers()
+	manager.ClearGroupTrackers()
+	defer manager.ClearUserTrackers()
+
+	// not tracked: nothing changes
+	assert.NilError(t, manager.MoveTrackedResource(queuePath1, queuePath2, TestApp1, usage, user))
+	assert.Assert(t, manager.GetUserTracker(user.User) == nil, "user should not be tracked")
+
+	manager.IncreaseTrackedResource(queuePath1, TestApp1, usage, user)
+	assert.NilError(t, manager.MoveTrackedResource(queuePath1, queuePath2, TestApp1, usage, user))
+	userTracker := manager.GetUserTracker(user.User)
+	assert.Assert(t, userTracker != nil, "user should be tracked")
+	assert.Assert(t, !userTracker.queueTracker.IsQueuePathTrackedCompletely(strings.Split(queuePath1, configs.DOT)), "source should not be tracked")
+	assert.Assert(t, userTracker.queueTracker.IsQueuePathTrackedCompletely(strings.Split(queuePath2, configs.DOT)), "target should be tracked")
+	assertUGM(t, user, usage, 1)
+
+	manager.DecreaseTrackedResource(queuePath2, TestApp1, usage, user, true)
+	assert.Assert(t, manager.GetUserTracker(user.User) == nil, "user should have been removed")
+}
+
+func TestMoveTrackedResourceLimits(t *testing.T) {
+	setupUGM()
+	defer setupUGM()
+	manager := GetUserManager()
+	conf := createConfigWithLimits([]configs.Limit{
+		createLimit([]string{"user1"}, nil, mediumResource, 2),
+		createLimit(nil, []string{"group1"}, largeResource, 1),
+	})
+	assert.NilError(t, manager.UpdateConfig(conf.Queues[0], "root"))
+	user := security.UserGroup{User: "user1", Groups: []string{"group1"}}
+	tiny, err := resources.NewResourceFromConf(tinyResource)
+	assert.NilError(t, err, "failed to create resource")
+	medium, err := resources.NewResourceFromConf(mediumResource)
+	assert.NilError(t, err, "failed to create resource")
+
+	// the limits of the common parent already include the application
+	manager.IncreaseTrackedResource("root.parent.a", TestApp1, tiny, user)
+	assert.NilError(t, manager.MoveTrackedResource("root.parent.a", "root.parent.b", TestApp1, tiny, user))
+	assert.Assert(t, manager.GetUserQueueUsage(user.User, "root.parent.a") == nil, "source should not have usage")
+	assert.Assert(t, resources.Equals(tiny, manager.GetUserQueueUsage(user.User, "root.parent.b")), "unexpected target usage")
+	assert.Equal(t, manager.GetUserTracker(user.User).getGroupForApp(TestApp1), "group1", "unexpected group")
+
+	// user resource limit: nothing changes
+	manager.IncreaseTrackedResource("root.other", TestApp2, medium, user)
+	err = manager.MoveTrackedResource("root.other", "root.parent.c", TestApp2, medium, user)
+	assert.ErrorContains(t, err, "usage of user user1 would exceed the maximum resources of queue root.parent")
+	assert.Assert(t, resources.Equals(medium, manager.GetUserQueueUsage(user.User, "root.other")), "source usage should not have changed")
+	assert.Assert(t, manager.GetUserQueueUsage(user.User, "root.parent.c") == nil, "target should not have usage")
+
+	// group application limit: the group is resolved for the target queue
+	manager.IncreaseTrackedResource("root.other", TestApp3, tiny, user)
+	err = manager.MoveTrackedResource("root.other", "root.parent.c", TestApp3, tiny, user)
+	assert.ErrorContains(t, err, "group group1 would exceed the maximum applications (1) of queue root.parent")
+	assert.Assert(t, resources.Equals(resources.Add(medium, tiny), manager.GetUserQueueUsage(user.User, "root.other")), "source usage should not have changed")
+	assert.Equal(t, manager.GetUserTracker(user.User).getGroupForApp(TestApp3), "", "group should not have changed")
+}
+
+func TestGetUserQueueUsage(t *testing.T) {
+	user := security.UserGroup{User: "test", Groups: []string{"test"}}
+	usage := resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 5})
+	manager := GetUserManager()
+	manager.ClearUserTrackers()
+	manager.ClearGroupTrackers()
+	defer manager.ClearUserTrackers()
+
+	assert.Assert(t, manager.GetUserQueueUsage(user.User, queuePathLeaf) == nil, "user should not have usage")
+	manager.IncreaseTrackedResource(queuePathLeaf, TestApp1, usage, user)
+	assert.Assert(t, resources.Equals(usage, manager.GetUserQueueUsage(user.User, queuePathLeaf)), "unexpected leaf usage")
+	assert.Assert(t, resources.Equals(usage, manager.GetUserQueueUsage(user.User, queuePathParent)), "unexpected parent usage")
+	assert.Assert(t, manager.GetUserQueueUsage(user.User, "root.other") == nil, "queue should not be tracked")
+
+	// a copy is returned
+	manager.GetUserQueueUsage(user.User, queuePathLeaf).AddTo(usage)
+	assert.Assert(t, resources.Equals(usage, manager.GetUserQueueUsage(user.User, queuePathLeaf)), "usage should not have changed")
+	manager.DecreaseTrackedResource(queuePathLeaf, TestApp1, usage, user, true)
+	assert.Assert(t, manager.GetUserQueueUsage(user.User, queuePathLeaf) == nil, "user should have been removed")
+}
+
+func TestUserDecayedUsage(t *testing.T) {
+	resources.SetUsageHalfLife(0)
+	defer resources.SetUsageHalfLife(resources.DefaultUsageHalfLife)
+	user := security.UserGroup{User: "test", Groups: []string{"test"}}
+	usage := resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 5})
+	manager := GetUserManager()
+	manager.ClearUserTrackers()
+	manager.ClearGroupTrackers()
+	defer manager.ClearUserTrackers()
+
+	assert.Assert(t, resources.IsZero(manager.GetUserDecayedUsage(user.User, time.Now())), "unknown user should not have history")
+	manager.IncreaseTrackedResource(queuePath1, TestApp1, usage, user)
+	userTracker := manager.GetUserTracker(user.User)
+	assert.Assert(t, userTracker != nil, "user should be tracked")
+	// start the usage a minute ago
+	userTracker.usageHistory.Update(time.Now().Add(-time.Minute), usage)
+	expected := resources.NewResourceFromMap(map[string]resources.Quantity{"vcore": 300})
+	assert.Assert(t, resources.Equals(expected, manager.GetUserDecayedUsage(user.User, time.Now())), "unexpected decayed usage")
+	assert.DeepEqual(t, map[string]int64{"vcore": 300}, userTracker.GetUserResourceUsageDAOInfo().DecayedUsage)
+
+	// the history survives the removal of the tracker
+	manager.DecreaseTrackedResource(queuePath1, TestApp1, usage, user, true)
+	assert.Assert(t, manager.GetUserTracker(user.User) == nil, "user should have been removed")
+	later := time.Now().Add(time.Hour)
+	assert.Assert(t, resources.Equals(expected, manager.GetUserDecayedUsage(user.User, later)), "history should be kept")
+
+	// and is picked up again by a new tracker
+	manager.IncreaseTrackedResource(queuePath1, TestApp1, usage, user)
+	assert.Assert(t, resources.StrictlyGreaterThanOrEquals(manager.GetUserDecayedUsage(user.User, time.Now()), expected), "history should be restored")
+	manager.DecreaseTrackedResource(queuePath1, TestApp1, usage, user, true)
+
+	// decayed history is dropped when a tracker is removed
+	resources.SetUsageHalfLife(time.Nanosecond)
+	manager.IncreaseTrackedResource(queuePath1, TestApp1, usage, user)
+	manager.DecreaseTrackedResource(queuePath1, TestApp1, usage, user, true)
+	assert.Equal(t, 0, len(manager.userUsageHistory), "decayed history should have been dropped")
+}
+
 func TestUpdateConfig(t *testing.T) {
 	setupUGM()
 	// Queue setup:
@@ -1003,6 +1124,51 @@ func TestSeparateUserGroupHeadroom(t *testing.T) {
 	}
 }
 
+func TestExplainLimits(t *testing.T) {
+	setupUGM()
+	manager := GetUserManager()
+	conf := createConfigWithLimits([]configs.Limit{
+		createLimit([]string{"user1"}, nil, mediumResource, 1),
+		createLimit(nil, []string{"group1"}, mediumResourceWithMemOnly, 1),
+	})
+	assert.NilError(t, manager.UpdateConfig(conf.Queues[0], "root"))
+	user := security.UserGroup{User: "user1", Groups: []string{"group1"}}
+
+	// nothing tracked: nothing blocks
+	request := resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 30, "vcores": 10})
+	assert.Equal(t, len(manager.ExplainCanRunApp(queuePathParent, TestApp1, user)), 0, "no application limit should block")
+	assert.Equal(t, len(manager.ExplainHeadroom(queuePathParent, TestApp1, user, request)), 0, "no resource limit should block")
+
+	usage, err := resources.NewResourceFromConf(tinyResource)
+	assert.NilError(t, err, "failed to create resource")
+	manager.IncreaseTrackedResource(queuePathParent, TestApp1, usage, user)
+
+	// running application is not blocked by the application limit
+	assert.Equal(t, len(manager.ExplainCanRunApp(queuePathParent, TestApp1, user)), 0, "running application should not be blocked")
+	violations := manager.ExplainCanRunApp(queuePathParent, TestApp2, user)
+	assert.Equal(t, len(violations), 2, "expected user and group application limit")
+	assert.DeepEqual(t, violations[0], &LimitViolation{User: "user1", QueuePath: queuePathParent, MaxApplications: 1})
+	assert.DeepEqual(t, violations[1], &LimitViolation{Group: "group1", QueuePath: queuePathParent, MaxApplications: 1})
+
+	// only the memory does not fit: user headroom is 25/25, group headroom is 25 memory
+	violations = manager.ExplainHeadroom(queuePathParent, TestApp1, user, request)
+	assert.Equal(t, len(violations), 2, "expected user and group resource limit")
+	assert.DeepEqual(t, violations[0], &LimitViolation{User: "user1", QueuePath: queuePathParent, ResourceTypes: []string{"memory"}})
+	assert.DeepEqual(t, violations[1], &LimitViolation{Group: "group1", QueuePath: queuePathParent, ResourceTypes: []string{"memory"}})
+	assert.Equal(t, manager.Headroom(queuePathParent, TestApp1, user).FitInMaxUndef(request), false, "explain should match headroom")
+
+	// explain is read only: no trackers are created or linked
+	assert.Assert(t, !manager.GetUserTracker(user.User).hasGroupForApp(TestApp2), "application should not be linked to a group")
+	other := security.UserGroup{User: "other", Groups: []string{"group1"}}
+	violations = manager.ExplainCanRunApp(queuePathLeaf, TestApp2, other)
+	assert.Equal(t, len(violations), 1, "expected group application limit")
+	assert.DeepEqual(t, violations[0], &LimitViolation{Group: "group1", QueuePath: queuePathParent, MaxApplications: 1})
+	assert.Equal(t, len(manager.ExplainHeadroom(queuePathLeaf, TestApp2, other, request)), 1, "expected group resource limit")
+	assert.Assert(t, manager.GetUserTracker(other.User) == nil, "user tracker should not have been created")
+	assert.Assert(t, !manager.GetGroupTracker("group1").queueTracker.IsQueuePathTrackedCompletely(strings.Split(queuePathLeaf, configs.DOT)),
+		"leaf queue tracker should not have been created")
+}
+
 func TestUserGroupLimit(t *testing.T) { //nolint:funlen
 	testCases := []struct {
 		name                          string
@@ -230,6 +230,21 @@ func (qt *QueueTracker) headroom(hierarchy []string, trackType trackingType) *re
 	return resources.ComponentWiseMin(headroom, childHeadroom)
 }
 
+// getResourceUsage returns a copy of the resource usage of the queue at the end of the hierarchy, nil if the queue is
+// not tracked.
+// Note: Lock free call. The RLock of the linked tracker (UserTracker and GroupTracker) should be held before calling this function.
+func (qt *QueueTracker) getResourceUsage(hierarchy []string) *resources.Resource {
+	// depth first: all the way to the leaf, ignore if not exists
+	if len(hierarchy) > 1 {
+		child := qt.childQueueTrackers[hierarchy[1]]
+		if child == nil {
+			return nil
+		}
+		return child.getResourceUsage(hierarchy[1:])
+	}
+	return qt.resourceUsage.Clone()
+}
+
 // Note: Lock free call. The RLock of the linked tracker (UserTracker and GroupTracker) should be held before calling this function.
 func (qt *QueueTracker) getResourceUsageDAOInfo(parentQueuePath string) *dao.ResourceUsageDAOInfo {
 	if qt == nil {
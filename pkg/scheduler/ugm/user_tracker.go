@@ -20,6 +20,7 @@ package ugm
 
 import (
 	"strings"
+	"time"
 
 	"github.com/G-Research/yunikorn-core/pkg/common"
 	"github.com/G-Research/yunikorn-core/pkg/common/configs"
@@ -36,7 +37,8 @@ type UserTracker struct {
 	// Hence, group tracker object may vary for same user running different applications linked through this map with key as application id
 	// and group tracker object as value.
 	appGroupTrackers map[string]*GroupTracker
-	queueTracker     *QueueTracker // Holds the actual resource usage of queue path where application runs
+	queueTracker     *QueueTracker           // Holds the actual resource usage of queue path where application runs
+	usageHistory     *resources.DecayedUsage // Total resource usage of the user integrated over time with decay
 	events           *ugmEvents
 
 	locking.RWMutex
@@ -48,6 +50,7 @@ func newUserTracker(userName string, ugmEvents *ugmEvents) *UserTracker {
 		userName:         userName,
 		appGroupTrackers: make(map[string]*GroupTracker),
 		queueTracker:     queueTracker,
+		usageHistory:     resources.NewDecayedUsage(),
 		events:           ugmEvents,
 	}
 	return userTracker
@@ -59,6 +62,7 @@ func (ut *UserTracker) increaseTrackedResource(queuePath string, applicationID s
 	ut.events.sendIncResourceUsageForUser(ut.userName, queuePath, usage)
 	hierarchy := strings.Split(queuePath, configs.DOT)
 	ut.queueTracker.increaseTrackedResource(hierarchy, applicationID, user, usage)
+	ut.usageHistory.Update(time.Now(), ut.queueTracker.resourceUsage)
 }
 
 func (ut *UserTracker) decreaseTrackedResource(queuePath string, applicationID string, usage *resources.Resource, removeApp bool) bool {
@@ -73,7 +77,9 @@ func (ut *UserTracker) decreaseTrackedResource(queuePath string, applicationID s
 		}
 		delete(ut.appGroupTrackers, applicationID)
 	}
-	return ut.queueTracker.decreaseTrackedResource(strings.Split(queuePath, configs.DOT), applicationID, usage, removeApp)
+	removeQT := ut.queueTracker.decreaseTrackedResource(strings.Split(queuePath, configs.DOT), applicationID, usage, removeApp)
+	ut.usageHistory.Update(time.Now(), ut.queueTracker.resourceUsage)
+	return removeQT
 }
 
 func (ut *UserTracker) hasGroupForApp(applicationID string) bool {
@@ -128,6 +134,13 @@ func (ut *UserTracker) headroom(hierarchy []string) *resources.Resource {
 	return ut.queueTracker.headroom(hierarchy, user)
 }
 
+// getQueueUsage returns the resource usage of the user in the queue, nil if the queue is not tracked for the user.
+func (ut *UserTracker) getQueueUsage(hierarchy []string) *resources.Resource {
+	ut.RLock()
+	defer ut.RUnlock()
+	return ut.queueTracker.getResourceUsage(hierarchy)
+}
+
 func (ut *UserTracker) GetUserResourceUsageDAOInfo() *dao.UserResourceUsageDAOInfo {
 	ut.RLock()
 	defer ut.RUnlock()
@@ -141,9 +154,17 @@ func (ut *UserTracker) GetUserResourceUsageDAOInfo() *dao.UserResourceUsageDAOIn
 		}
 	}
 	userResourceUsage.Queues = ut.queueTracker.getResourceUsageDAOInfo(common.Empty)
+	userResourceUsage.DecayedUsage = ut.usageHistory.Value(time.Now()).DAOMap()
 	return userResourceUsage
 }
 
+// getDecayedUsage returns the total usage of the user integrated over time with decay, at the time passed in.
+func (ut *UserTracker) getDecayedUsage(now time.Time) *resources.Resource {
+	ut.RLock()
+	defer ut.RUnlock()
+	return ut.usageHistory.Value(now)
+}
+
 func (ut *UserTracker) IsQueuePathTrackedCompletely(hierarchy []string) bool {
 	ut.RLock()
 	defer ut.RUnlock()
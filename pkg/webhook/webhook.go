@@ -0,0 +1,358 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"go.uber.org/zap"
+
+	"github.com/G-Research/yunikorn-core/pkg/common"
+	"github.com/G-Research/yunikorn-core/pkg/common/configs"
+	"github.com/G-Research/yunikorn-core/pkg/locking"
+	"github.com/G-Research/yunikorn-core/pkg/log"
+)
+
+const (
+	// SignatureHeader carries "sha256=" followed by the hex encoded HMAC-SHA256 of "<timestamp>.<body>".
+	SignatureHeader = "X-Yunikorn-Signature"
+	// TimestampHeader carries the unix time in seconds of the delivery attempt.
+	TimestampHeader = "X-Yunikorn-Timestamp"
+	// DeliveryIDHeader carries the ID of the delivery, the same for all attempts of a delivery.
+	DeliveryIDHeader = "X-Yunikorn-Delivery"
+
+	signaturePrefix = "sha256="
+	callbackName    = "webhook-notifier"
+	workerCount     = 4
+)
+
+var (
+	initialBackoff = 500 * time.Millisecond
+	maxBackoff     = 30 * time.Second
+)
+
+var notifier = &Notifier{}
+
+// notifierConfig the settings of the notifier, read from the "webhook." configuration.
+type notifierConfig struct {
+	secret      string
+	maxRetries  uint64
+	timeout     time.Duration
+	queueSize   uint64
+	deliveryLog string
+}
+
+type delivery struct {
+	id      string
+	url     string
+	subject string
+	body    []byte
+}
+
+// DeliveryLogEntry is one delivery attempt as written to the delivery log.
+type DeliveryLogEntry struct {
+	Time       time.Time `json:"time"`
+	DeliveryID string    `json:"deliveryID"`
+	URL        string    `json:"url"`
+	Subject    string    `json:"subject"`
+	Attempt    int       `json:"attempt"`
+	StatusCode int       `json:"statusCode,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Delivered  bool      `json:"delivered"`
+}
+
+// Notifier POSTs JSON payloads to webhook URLs. Deliveries are queued and sent by a small pool of workers so the
+// scheduler is never blocked. A failed delivery is retried with exponential backoff, every attempt is written to
+// the delivery log if one is configured.
+type Notifier struct {
+	config  atomic.Pointer[notifierConfig]
+	queue   chan *delivery
+	stop    chan struct{}
+	workers sync.WaitGroup
+	running bool
+
+	logFile *os.File
+	logPath string
+	logLock locking.Mutex
+
+	locking.RWMutex
+}
+
+// GetNotifier returns the webhook notifier.
+func GetNotifier() *Notifier {
+	return notifier
+}
+
+// StartService starts the delivery workers. Notifications sent before the service is started are dropped.
+func (n *Notifier) StartService() {
+	n.Lock()
+	defer n.Unlock()
+	if n.running {
+		return
+	}
+	config := getNotifierConfig()
+	n.config.Store(config)
+	n.queue = make(chan *delivery, config.queueSize)
+	n.stop = make(chan struct{})
+	for i := 0; i < workerCount; i++ {
+		n.workers.Add(1)
+		go n.run()
+	}
+	n.running = true
+	configs.AddConfigMapCallback(callbackName, func() {
+		n.config.Store(getNotifierConfig())
+	})
+	log.Log(log.Webhook).Info("Started webhook notifier")
+}
+
+// Stop stops the delivery workers. Queued deliveries are not sent.
+func (n *Notifier) Stop() {
+	n.Lock()
+	defer n.Unlock()
+	if !n.running {
+		return
+	}
+	configs.RemoveConfigMapCallback(callbackName)
+	close(n.stop)
+	n.workers.Wait()
+	n.running = false
+	n.logLock.Lock()
+	n.closeLog()
+	n.logLock.Unlock()
+	log.Log(log.Webhook).Info("Stopped webhook notifier")
+}
+
+// Notify queues the payload for delivery to each URL. The subject identifies the notification in the delivery log.
+// The notification is dropped if the notifier is not running or the queue is full.
+func (n *Notifier) Notify(urls []string, subject string, payload interface{}) {
+	if len(urls) == 0 {
+		return
+	}
+	n.RLock()
+	defer n.RUnlock()
+	if !n.running {
+		return
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Log(log.Webhook).Warn("Cannot encode webhook payload",
+			zap.String("subject", subject),
+			zap.Error(err))
+		return
+	}
+	for _, url := range urls {
+		d := &delivery{
+			id:      ulid.Make().String(),
+			url:     url,
+			subject: subject,
+			body:    body,
+		}
+		select {
+		case n.queue <- d:
+		default:
+			log.Log(log.Webhook).Warn("Webhook queue full, notification dropped",
+				zap.String("url", url),
+				zap.String("subject", subject))
+			n.writeLog(&DeliveryLogEntry{
+				Time:       time.Now(),
+				DeliveryID: d.id,
+				URL:        url,
+				Subject:    subject,
+				Error:      "queue full",
+			})
+		}
+	}
+}
+
+func (n *Notifier) run() {
+	defer n.workers.Done()
+	for {
+		select {
+		case <-n.stop:
+			return
+		case d := <-n.queue:
+			n.deliver(d)
+		}
+	}
+}
+
+// deliver sends the delivery, retrying with exponential backoff until the maximum number of retries is reached
+// or the notifier is stopped.
+func (n *Notifier) deliver(d *delivery) {
+	backoff := initialBackoff
+	for attempt := 1; ; attempt++ {
+		config := n.config.Load()
+		status, err := send(d, config)
+		entry := &DeliveryLogEntry{
+			Time:       time.Now(),
+			DeliveryID: d.id,
+			URL:        d.url,
+			Subject:    d.subject,
+			Attempt:    attempt,
+			StatusCode: status,
+			Delivered:  err == nil,
+		}
+		if err != nil {
+			entry.Error = err.Error()
+		}
+		n.writeLog(entry)
+		if err == nil {
+			return
+		}
+		if uint64(attempt) > config.maxRetries {
+			log.Log(log.Webhook).Warn("Webhook delivery failed",
+				zap.String("url", d.url),
+				zap.String("subject", d.subject),
+				zap.Int("attempts", attempt),
+				zap.Error(err))
+			return
+		}
+		select {
+		case <-time.After(backoff):
+		case <-n.stop:
+			return
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// send POSTs the body once and returns the response status.
+func send(d *delivery, config *notifierConfig) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, d.url, bytes.NewReader(d.body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(DeliveryIDHeader, d.id)
+	req.Header.Set(TimestampHeader, timestamp)
+	if config.secret != "" {
+		req.Header.Set(SignatureHeader, Sign(config.secret, timestamp, d.body))
+	}
+	client := &http.Client{Timeout: config.timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// drain the body so that the connection can be reused
+	_, _ = io.Copy(io.Discard, resp.Body) //nolint:errcheck
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the signature header value for the timestamp and body.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature header value of a received payload, for use by webhook receivers.
+func Verify(secret, timestamp string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// writeLog appends the entry to the delivery log, the log file is (re)opened when the configured path changes.
+func (n *Notifier) writeLog(entry *DeliveryLogEntry) {
+	n.logLock.Lock()
+	defer n.logLock.Unlock()
+	path := n.config.Load().deliveryLog
+	if path != n.logPath {
+		n.closeLog()
+		if path != "" {
+			file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+			if err != nil {
+				log.Log(log.Webhook).Warn("Cannot open webhook delivery log",
+					zap.String("file", path),
+					zap.Error(err))
+				return
+			}
+			n.logFile = file
+		}
+		n.logPath = path
+	}
+	if n.logFile == nil {
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if _, err = n.logFile.Write(append(data, '\n')); err != nil {
+		log.Log(log.Webhook).Warn("Cannot write webhook delivery log",
+			zap.String("file", n.logPath),
+			zap.Error(err))
+	}
+}
+
+// closeLog closes the delivery log, must be called holding the log lock.
+func (n *Notifier) closeLog() {
+	if n.logFile != nil {
+		if err := n.logFile.Close(); err != nil {
+			log.Log(log.Webhook).Warn("Cannot close webhook delivery log", zap.Error(err))
+		}
+	}
+	n.logFile = nil
+	n.logPath = ""
+}
+
+// getNotifierConfig reads the notifier settings from the configuration.
+func getNotifierConfig() *notifierConfig {
+	configMap := configs.GetConfigMap()
+	config := &notifierConfig{
+		secret:      configMap[configs.CMWebhookSecret],
+		maxRetries:  common.GetConfigurationUint(configMap, configs.CMWebhookMaxRetries, configs.DefaultWebhookMaxRetries),
+		timeout:     configs.DefaultWebhookTimeout,
+		queueSize:   common.GetConfigurationUint(configMap, configs.CMWebhookQueueSize, configs.DefaultWebhookQueueSize),
+		deliveryLog: configMap[configs.CMWebhookDeliveryLog],
+	}
+	if value, ok := configMap[configs.CMWebhookTimeout]; ok {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			log.Log(log.Webhook).Warn("Failed to parse configuration value",
+				zap.String("key", configs.CMWebhookTimeout),
+				zap.String("value", value),
+				zap.Error(err))
+		} else {
+			config.timeout = timeout
+		}
+	}
+	if config.queueSize == 0 {
+		config.queueSize = configs.DefaultWebhookQueueSize
+	}
+	return config
+}
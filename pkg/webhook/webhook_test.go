@@ -0,0 +1,255 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package webhook
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/G-Research/yunikorn-core/pkg/common"
+	"github.com/G-Research/yunikorn-core/pkg/common/configs"
+)
+
+// testReceiver records the requests and fails the first failures requests.
+type testReceiver struct {
+	bodies   [][]byte
+	headers  []http.Header
+	failures int
+	calls    int
+	sync.Mutex
+}
+
+func (r *testReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.Lock()
+	defer r.Unlock()
+	r.calls++
+	if r.calls <= r.failures {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	r.bodies = append(r.bodies, body)
+	r.headers = append(r.headers, req.Header.Clone())
+}
+
+func (r *testReceiver) received() int {
+	r.Lock()
+	defer r.Unlock()
+	return len(r.bodies)
+}
+
+func startNotifier(t *testing.T, configMap map[string]string) *Notifier {
+	original := configs.GetConfigMap()
+	backoff := initialBackoff
+	initialBackoff = time.Millisecond
+	configs.SetConfigMap(configMap)
+	n := &Notifier{}
+	n.StartService()
+	t.Cleanup(func() {
+		n.Stop()
+		configs.SetConfigMap(original)
+		initialBackoff = backoff
+	})
+	return n
+}
+
+func readDeliveryLog(t *testing.T, path string) []DeliveryLogEntry {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	assert.NilError(t, err)
+	defer file.Close()
+	var entries []DeliveryLogEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry DeliveryLogEntry
+		assert.NilError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"applicationID":"app-1"}`)
+	signature := Sign("secret", "1700000000", body)
+	assert.Assert(t, Verify("secret", "1700000000", body, signature))
+	assert.Assert(t, !Verify("other", "1700000000", body, signature), "wrong secret should not verify")
+	assert.Assert(t, !Verify("secret", "1700000001", body, signature), "wrong timestamp should not verify")
+	assert.Assert(t, !Verify("secret", "1700000000", []byte(`{}`), signature), "wrong body should not verify")
+	assert.Equal(t, "sha256=", signature[:7])
+}
+
+func TestNotify(t *testing.T) {
+	receiver := &testReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+	logPath := filepath.Join(t.TempDir(), "deliveries.jsonl")
+	n := startNotifier(t, map[string]string{
+		configs.CMWebhookSecret:      "secret",
+		configs.CMWebhookDeliveryLog: logPath,
+	})
+
+	n.Notify([]string{server.URL + "/a", server.URL + "/b"}, "app-1/Running", map[string]string{"state": "Running"})
+	err := common.WaitForCondition(time.Millisecond, time.Second, func() bool {
+		return receiver.received() == 2
+	})
+	assert.NilError(t, err, "webhooks not called")
+	receiver.Lock()
+	for i, body := range receiver.bodies {
+		assert.Equal(t, `{"state":"Running"}`, string(body))
+		header := receiver.headers[i]
+		assert.Equal(t, "application/json", header.Get("Content-Type"))
+		assert.Assert(t, header.Get(DeliveryIDHeader) != "")
+		assert.Assert(t, Verify("secret", header.Get(TimestampHeader), body, header.Get(SignatureHeader)), "invalid signature")
+	}
+	receiver.Unlock()
+
+	err = common.WaitForCondition(time.Millisecond, time.Second, func() bool {
+		return len(readDeliveryLog(t, logPath)) == 2
+	})
+	assert.NilError(t, err, "deliveries not logged")
+	for _, entry := range readDeliveryLog(t, logPath) {
+		assert.Equal(t, "app-1/Running", entry.Subject)
+		assert.Equal(t, 1, entry.Attempt)
+		assert.Equal(t, http.StatusOK, entry.StatusCode)
+		assert.Assert(t, entry.Delivered)
+	}
+}
+
+func TestNotify_Unsigned(t *testing.T) {
+	receiver := &testReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+	n := startNotifier(t, map[string]string{})
+
+	n.Notify([]string{server.URL}, "app-1/Running", map[string]string{})
+	err := common.WaitForCondition(time.Millisecond, time.Second, func() bool {
+		return receiver.received() == 1
+	})
+	assert.NilError(t, err, "webhook not called")
+	receiver.Lock()
+	defer receiver.Unlock()
+	assert.Equal(t, "", receiver.headers[0].Get(SignatureHeader))
+}
+
+func TestNotify_Retry(t *testing.T) {
+	receiver := &testReceiver{failures: 2}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+	logPath := filepath.Join(t.TempDir(), "deliveries.jsonl")
+	n := startNotifier(t, map[string]string{
+		configs.CMWebhookMaxRetries:  "2",
+		configs.CMWebhookDeliveryLog: logPath,
+	})
+
+	n.Notify([]string{server.URL}, "app-1/Completed", map[string]string{})
+	err := common.WaitForCondition(time.Millisecond, time.Second, func() bool {
+		return len(readDeliveryLog(t, logPath)) == 3
+	})
+	assert.NilError(t, err, "delivery attempts not logged")
+	assert.Equal(t, 1, receiver.received())
+	entries := readDeliveryLog(t, logPath)
+	assert.Equal(t, 3, len(entries))
+	for i, entry := range entries {
+		assert.Equal(t, i+1, entry.Attempt)
+		assert.Equal(t, entries[0].DeliveryID, entry.DeliveryID)
+	}
+	assert.Equal(t, http.StatusInternalServerError, entries[0].StatusCode)
+	assert.Equal(t, "webhook returned status 500", entries[0].Error)
+	assert.Assert(t, !entries[1].Delivered)
+	assert.Assert(t, entries[2].Delivered)
+}
+
+func TestNotify_MaxRetries(t *testing.T) {
+	receiver := &testReceiver{failures: 10}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+	logPath := filepath.Join(t.TempDir(), "deliveries.jsonl")
+	n := startNotifier(t, map[string]string{
+		configs.CMWebhookMaxRetries:  "1",
+		configs.CMWebhookDeliveryLog: logPath,
+	})
+
+	n.Notify([]string{server.URL}, "app-1/Failed", map[string]string{})
+	err := common.WaitForCondition(time.Millisecond, time.Second, func() bool {
+		return len(readDeliveryLog(t, logPath)) == 2
+	})
+	assert.NilError(t, err, "delivery attempts not logged")
+	// give a possible third attempt the time to show up
+	time.Sleep(20 * time.Millisecond)
+	receiver.Lock()
+	assert.Equal(t, 2, receiver.calls)
+	receiver.Unlock()
+}
+
+func TestNotify_NotRunning(t *testing.T) {
+	receiver := &testReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+	n := &Notifier{}
+	n.Notify([]string{server.URL}, "app-1/Running", map[string]string{})
+	n.Stop()
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, 0, receiver.received())
+}
+
+func TestGetNotifierConfig(t *testing.T) {
+	original := configs.GetConfigMap()
+	defer configs.SetConfigMap(original)
+
+	configs.SetConfigMap(map[string]string{})
+	config := getNotifierConfig()
+	assert.Equal(t, "", config.secret)
+	assert.Equal(t, configs.DefaultWebhookMaxRetries, config.maxRetries)
+	assert.Equal(t, configs.DefaultWebhookTimeout, config.timeout)
+	assert.Equal(t, configs.DefaultWebhookQueueSize, config.queueSize)
+	assert.Equal(t, "", config.deliveryLog)
+
+	configs.SetConfigMap(map[string]string{
+		configs.CMWebhookSecret:      "secret",
+		configs.CMWebhookMaxRetries:  "0",
+		configs.CMWebhookTimeout:     "2s",
+		configs.CMWebhookQueueSize:   "0",
+		configs.CMWebhookDeliveryLog: "/tmp/deliveries.jsonl",
+	})
+	config = getNotifierConfig()
+	assert.Equal(t, "secret", config.secret)
+	assert.Equal(t, uint64(0), config.maxRetries)
+	assert.Equal(t, 2*time.Second, config.timeout)
+	assert.Equal(t, configs.DefaultWebhookQueueSize, config.queueSize)
+	assert.Equal(t, "/tmp/deliveries.jsonl", config.deliveryLog)
+
+	configs.SetConfigMap(map[string]string{configs.CMWebhookTimeout: "x"})
+	assert.Equal(t, configs.DefaultWebhookTimeout, getNotifierConfig().timeout)
+}
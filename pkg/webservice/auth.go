@@ -0,0 +1,406 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package webservice
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+
+	"github.com/G-Research/yunikorn-core/pkg/common"
+	"github.com/G-Research/yunikorn-core/pkg/common/configs"
+	"github.com/G-Research/yunikorn-core/pkg/common/security"
+	"github.com/G-Research/yunikorn-core/pkg/locking"
+	"github.com/G-Research/yunikorn-core/pkg/log"
+	"github.com/G-Research/yunikorn-core/pkg/scheduler"
+	"github.com/G-Research/yunikorn-core/pkg/scheduler/objects"
+)
+
+const (
+	AuthenticationRequired = "Authentication required"
+	AccessDenied           = "Access denied"
+)
+
+// Authenticator returns the identity of the caller of a request. A nil identity without an error means that the
+// request has no credentials for the authenticator: the next authenticator is tried. Invalid credentials return an
+// error and fail the request.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*security.UserGroup, error)
+}
+
+// authorizer checks the access of the authenticated user to a route. Queues and applications that do not exist are
+// allowed: the handler returns the not found response. A partition that does not exist is denied.
+type authorizer func(r *http.Request, user security.UserGroup) bool
+
+type requestUserKey struct{}
+
+// authenticators used when authentication is enabled, nil if authentication is disabled
+var authenticators atomic.Pointer[[]Authenticator]
+
+// registeredAuthenticators are added by the code embedding the scheduler, used after the configured authenticators
+var registeredAuthenticators []Authenticator
+var registeredLock locking.Mutex
+
+// publicRoutes are served without authentication, keyed on method and pattern
+var publicRoutes = map[string]bool{
+	"GET /ws/v1/scheduler/healthcheck": true,
+}
+
+// routeAuthorizers check the access to the routes keyed on method and pattern, routes that are not listed are open
+// to all authenticated users. The pprof routes are added on init.
+var routeAuthorizers = map[string]authorizer{
+	"GET /ws/v1/fullstatedump": isAdmin,
+	"GET /ws/v1/stack":         isAdmin,
+	"PUT /ws/v1/config":        isAdmin,
+
+	"GET /ws/v1/events/batch":  isAdmin,
+	"GET /ws/v1/events/stream": isAdmin,
+
+	"GET /ws/v1/partition/:partition/queues":                                isPartitionAdmin,
+	"GET /ws/v1/partition/:partition/nodes":                                 isPartitionAdmin,
+	"GET /ws/v1/partition/:partition/node/:node":                            isPartitionAdmin,
+	"GET /ws/v1/partition/:partition/queue/:queue":                          hasQueueAccess,
+	"GET /ws/v1/partition/:partition/queue/:queue/applications":             hasQueueAccess,
+	"GET /ws/v1/partition/:partition/queue/:queue/applications/:state":      hasQueueAccess,
+	"GET /ws/v1/partition/:partition/queue/:queue/application/:application": hasQueueAccess,
+	"GET /ws/v1/partition/:partition/application/:application":              hasApplicationAccess,
+	"GET /ws/v1/partition/:partition/application/:application/explain":      hasApplicationAccess,
+
+	"GET /ws/v1/queue/:id":       hasObjectAccess,
+	"GET /ws/v1/application/:id": hasObjectAccess,
+	"GET /ws/v1/objects/:id":     hasObjectAccess,
+	"GET /ws/v1/node/:id":        hasObjectAccess,
+
+	"GET /ws/v1/partition/:partition/usage/users":        isPartitionAdmin,
+	"GET /ws/v1/partition/:partition/usage/groups":       isPartitionAdmin,
+	"GET /ws/v1/partition/:partition/usage/user/:user":   isUserOrPartitionAdmin,
+	"GET /ws/v1/partition/:partition/usage/group/:group": isGroupMemberOrPartitionAdmin,
+
+	"POST /ws/v1/partition/:partition/node/:node/cordon":   isPartitionAdmin,
+	"POST /ws/v1/partition/:partition/node/:node/uncordon": isPartitionAdmin,
+	"POST /ws/v1/partition/:partition/node/:node/drain":    isPartitionAdmin,
+}
+
+func init() {
+	for _, webRoute := range webRoutes {
+		if strings.HasPrefix(webRoute.Pattern, "/debug/pprof/") {
+			routeAuthorizers[routeKey(webRoute)] = isAdmin
+		}
+	}
+}
+
+func routeKey(webRoute route) string {
+	return webRoute.Method + " " + webRoute.Pattern
+}
+
+// authHandler authenticates the request and checks the access to the route before calling the handler.
+// The authenticated user is added to the request context.
+func authHandler(webRoute route) http.Handler {
+	key := routeKey(webRoute)
+	public := publicRoutes[key]
+	authorize := routeAuthorizers[key]
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := authenticators.Load()
+		if current == nil || public {
+			webRoute.HandlerFunc.ServeHTTP(w, r)
+			return
+		}
+		user, err := authenticate(*current, r)
+		if err != nil || user == nil {
+			log.Log(log.REST).Info("REST request not authenticated",
+				zap.String("uri", r.RequestURI),
+				zap.String("remoteAddr", r.RemoteAddr),
+				zap.Error(err))
+			writeHeaders(w)
+			w.Header().Set("WWW-Authenticate", `Bearer realm="yunikorn"`)
+			buildJSONErrorResponse(w, AuthenticationRequired, http.StatusUnauthorized)
+			return
+		}
+		r = r.WithContext(context.WithValue(r.Context(), requestUserKey{}, *user))
+		if authorize != nil && !authorize(r, *user) {
+			log.Log(log.REST).Info("REST request denied",
+				zap.String("uri", r.RequestURI),
+				zap.String("user", user.User))
+			writeHeaders(w)
+			buildJSONErrorResponse(w, AccessDenied, http.StatusForbidden)
+			return
+		}
+		webRoute.HandlerFunc.ServeHTTP(w, r)
+	})
+}
+
+// authenticate returns the identity from the first authenticator that finds credentials in the request.
+func authenticate(current []Authenticator, r *http.Request) (*security.UserGroup, error) {
+	for _, authenticator := range current {
+		user, err := authenticator.Authenticate(r)
+		if err != nil || user != nil {
+			return user, err
+		}
+	}
+	return nil, nil
+}
+
+// RegisterAuthenticator adds an authenticator that is used, after the configured authenticators, when
+// authentication is enabled.
+func RegisterAuthenticator(authenticator Authenticator) {
+	registeredLock.Lock()
+	registeredAuthenticators = append(registeredAuthenticators, authenticator)
+	registeredLock.Unlock()
+	loadAuthConfig()
+}
+
+// getRequestUser returns the authenticated user of the request, false if authentication is disabled.
+func getRequestUser(r *http.Request) (security.UserGroup, bool) {
+	user, ok := r.Context().Value(requestUserKey{}).(security.UserGroup)
+	return user, ok
+}
+
+// loadAuthConfig replaces the authenticators based on the configuration. The token file is read on every
+// configuration update. A token file that cannot be read leaves no valid tokens.
+func loadAuthConfig() {
+	configMap := configs.GetConfigMap()
+	if !common.GetConfigurationBool(configMap, configs.CMRESTAuthEnabled, configs.DefaultRESTAuthEnabled) {
+		if authenticators.Swap(nil) != nil {
+			log.Log(log.REST).Info("REST API authentication disabled")
+		}
+		return
+	}
+	current := make([]Authenticator, 0)
+	if tokenFile := configMap[configs.CMRESTAuthTokenFile]; tokenFile != "" {
+		tokens, err := newTokenAuthenticator(tokenFile)
+		if err != nil {
+			log.Log(log.REST).Error("Failed to load the REST API bearer tokens",
+				zap.String("file", tokenFile),
+				zap.Error(err))
+			tokens = &TokenAuthenticator{}
+		}
+		current = append(current, tokens)
+	}
+	if common.GetConfigurationBool(configMap, configs.CMRESTAuthClientCert, configs.DefaultRESTAuthClientCert) {
+		current = append(current, &ClientCertAuthenticator{})
+	}
+	registeredLock.Lock()
+	current = append(current, registeredAuthenticators...)
+	registeredLock.Unlock()
+	if len(current) == 0 {
+		log.Log(log.REST).Warn("REST API authentication enabled without authenticators, all requests are rejected")
+	}
+	log.Log(log.REST).Info("REST API authentication enabled",
+		zap.Int("authenticators", len(current)))
+	authenticators.Store(&current)
+}
+
+// TokenAuthenticator authenticates requests with a static bearer token in the Authorization header.
+type TokenAuthenticator struct {
+	users map[[sha256.Size]byte]security.UserGroup // users keyed on the hash of the token
+}
+
+// newTokenAuthenticator reads the tokens from the file: one "token,user[,group...]" entry per line. Empty lines and
+// lines starting with # are ignored.
+func newTokenAuthenticator(path string) (*TokenAuthenticator, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	ta := &TokenAuthenticator{
+		users: make(map[[sha256.Size]byte]security.UserGroup),
+	}
+	scanner := bufio.NewScanner(file)
+	line := 0
+	for scanner.Scan() {
+		line++
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		fields := strings.Split(entry, ",")
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		if len(fields) < 2 || fields[0] == "" || fields[1] == "" {
+			return nil, fmt.Errorf("line %d: expected token,user[,group...]", line)
+		}
+		ta.users[sha256.Sum256([]byte(fields[0]))] = security.UserGroup{
+			User:   fields[1],
+			Groups: slices.DeleteFunc(fields[2:], func(group string) bool { return group == "" }),
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	return ta, nil
+}
+
+// Authenticate returns the user of the bearer token, an unknown token is an error.
+func (ta *TokenAuthenticator) Authenticate(r *http.Request) (*security.UserGroup, error) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return nil, nil
+	}
+	user, ok := ta.users[sha256.Sum256([]byte(strings.TrimSpace(token)))]
+	if !ok {
+		return nil, errors.New("invalid bearer token")
+	}
+	return &user, nil
+}
+
+// ClientCertAuthenticator authenticates requests with a verified TLS client certificate. The common name of the
+// subject is the user, the organisations are the groups.
+type ClientCertAuthenticator struct{}
+
+// Authenticate returns the user of the client certificate, a certificate without a common name is an error.
+func (ca *ClientCertAuthenticator) Authenticate(r *http.Request) (*security.UserGroup, error) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, nil
+	}
+	subject := r.TLS.VerifiedChains[0][0].Subject
+	if subject.CommonName == "" {
+		return nil, errors.New("client certificate has no common name")
+	}
+	return &security.UserGroup{
+		User:   subject.CommonName,
+		Groups: slices.Clone(subject.Organization),
+	}, nil
+}
+
+// isAdmin allows the user if it has admin access to the root queue of all partitions.
+func isAdmin(_ *http.Request, user security.UserGroup) bool {
+	partitions := schedulerContext.Load().GetPartitionMapClone()
+	if len(partitions) == 0 {
+		return false
+	}
+	for _, partition := range partitions {
+		if !checkPartitionAdmin(partition, user) {
+			return false
+		}
+	}
+	return true
+}
+
+// isPartitionAdmin allows the user if it has admin access to the root queue of the partition of the request.
+func isPartitionAdmin(r *http.Request, user security.UserGroup) bool {
+	partition := getRequestPartition(r)
+	return partition != nil && checkPartitionAdmin(partition, user)
+}
+
+func checkPartitionAdmin(partition *scheduler.PartitionContext, user security.UserGroup) bool {
+	root := partition.GetQueue(configs.RootQueue)
+	return root != nil && root.CheckAdminAccess(user)
+}
+
+// isUserOrPartitionAdmin allows users to read their own usage.
+func isUserOrPartitionAdmin(r *http.Request, user security.UserGroup) bool {
+	name, err := url.QueryUnescape(getRequestParam(r, "user"))
+	return err == nil && name == user.User || isPartitionAdmin(r, user)
+}
+
+// isGroupMemberOrPartitionAdmin allows group members to read the usage of the group.
+func isGroupMemberOrPartitionAdmin(r *http.Request, user security.UserGroup) bool {
+	name, err := url.QueryUnescape(getRequestParam(r, "group"))
+	return err == nil && slices.Contains(user.Groups, name) || isPartitionAdmin(r, user)
+}
+
+// hasQueueAccess allows the user if it has submit or admin access to the queue of the request.
+func hasQueueAccess(r *http.Request, user security.UserGroup) bool {
+	partition := getRequestPartition(r)
+	if partition == nil {
+		return false
+	}
+	queueName, err := url.QueryUnescape(getRequestParam(r, "queue"))
+	if err != nil {
+		return false
+	}
+	queue := partition.GetQueue(queueName)
+	return queue == nil || queue.CheckSubmitAccess(user)
+}
+
+// hasApplicationAccess allows the user if it has access to the queue of the application of the request.
+func hasApplicationAccess(r *http.Request, user security.UserGroup) bool {
+	partition := getRequestPartition(r)
+	if partition == nil {
+		return false
+	}
+	app := partition.GetApplication(getRequestParam(r, "application"))
+	return app == nil || checkApplicationAccess(partition, app, user)
+}
+
+// hasObjectAccess allows the user access to the queue or application with the ID of the request. Nodes carry the
+// allocations of all queues and are only accessible for partition admins. Partitions are open to all users.
+func hasObjectAccess(r *http.Request, user security.UserGroup) bool {
+	partition, object := schedulerContext.Load().GetObjectByID(getRequestParam(r, "id"))
+	switch obj := object.(type) {
+	case *objects.Queue:
+		return obj.CheckSubmitAccess(user)
+	case *objects.Application:
+		return checkApplicationAccess(partition, obj, user)
+	case *objects.Node:
+		return checkPartitionAdmin(partition, user)
+	default:
+		return true
+	}
+}
+
+// checkApplicationAccess checks the access to the queue of the application. The queue of a completed application
+// is looked up on its path, an application without a queue is only accessible for partition admins.
+func checkApplicationAccess(partition *scheduler.PartitionContext, app *objects.Application, user security.UserGroup) bool {
+	queue := app.GetQueue()
+	if queue == nil {
+		queue = partition.GetQueue(app.GetQueuePath())
+	}
+	if queue == nil {
+		return checkPartitionAdmin(partition, user)
+	}
+	return queue.CheckSubmitAccess(user)
+}
+
+// filterApplications returns the applications the authenticated user of the request has access to, all
+// applications if authentication is disabled.
+func filterApplications(r *http.Request, partition *scheduler.PartitionContext, apps []*objects.Application) []*objects.Application {
+	user, ok := getRequestUser(r)
+	if !ok {
+		return apps
+	}
+	filtered := make([]*objects.Application, 0, len(apps))
+	for _, app := range apps {
+		if checkApplicationAccess(partition, app, user) {
+			filtered = append(filtered, app)
+		}
+	}
+	return filtered
+}
+
+func getRequestPartition(r *http.Request) *scheduler.PartitionContext {
+	return schedulerContext.Load().GetPartitionWithoutClusterID(getRequestParam(r, "partition"))
+}
+
+func getRequestParam(r *http.Request, name string) string {
+	return httprouter.ParamsFromContext(r.Context()).ByName(name)
+}
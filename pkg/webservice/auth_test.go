@@ -0,0 +1,243 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package webservice
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/G-Research/yunikorn-core/pkg/common/configs"
+	"github.com/G-Research/yunikorn-core/pkg/common/security"
+	"github.com/G-Research/yunikorn-core/pkg/scheduler/objects"
+	"github.com/G-Research/yunikorn-scheduler-interface/lib/go/si"
+)
+
+const configACLs = `
+partitions:
+  - name: default
+    queues:
+      - name: root
+        adminacl: admin
+        queues:
+          - name: a
+            submitacl: alice
+          - name: b
+            submitacl: " devs"
+`
+
+const testTokens = `
+# token,user[,group...]
+admin-token,admin
+alice-token,alice
+bob-token, bob, devs
+carol-token,carol
+`
+
+func writeTokenFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "tokens")
+	assert.NilError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func enableAuth(t *testing.T, configMap map[string]string) {
+	original := configs.GetConfigMap()
+	configMap[configs.CMRESTAuthEnabled] = "true"
+	configs.SetConfigMap(configMap)
+	t.Cleanup(func() {
+		configs.SetConfigMap(original)
+	})
+}
+
+func TestTokenAuthenticator(t *testing.T) {
+	ta, err := newTokenAuthenticator(writeTokenFile(t, testTokens))
+	assert.NilError(t, err)
+	req, err := http.NewRequest("GET", "/ws/v1/clusters", nil)
+	assert.NilError(t, err)
+
+	user, err := ta.Authenticate(req)
+	assert.NilError(t, err, "no credentials should not fail")
+	assert.Assert(t, user == nil, "no credentials should not return a user")
+
+	req.Header.Set("Authorization", "Bearer bob-token")
+	user, err = ta.Authenticate(req)
+	assert.NilError(t, err)
+	assert.Equal(t, "bob", user.User)
+	assert.DeepEqual(t, []string{"devs"}, user.Groups)
+
+	req.Header.Set("Authorization", "Bearer unknown")
+	_, err = ta.Authenticate(req)
+	assert.Error(t, err, "invalid bearer token")
+
+	_, err = newTokenAuthenticator(writeTokenFile(t, "token-only\n"))
+	assert.Error(t, err, "line 1: expected token,user[,group...]")
+	_, err = newTokenAuthenticator(filepath.Join(t.TempDir(), "missing"))
+	assert.Assert(t, os.IsNotExist(err), "expected a missing file error")
+}
+
+func TestClientCertAuthenticator(t *testing.T) {
+	ca := &ClientCertAuthenticator{}
+	req, err := http.NewRequest("GET", "/ws/v1/clusters", nil)
+	assert.NilError(t, err)
+	user, err := ca.Authenticate(req)
+	assert.NilError(t, err, "plain HTTP should not fail")
+	assert.Assert(t, user == nil, "plain HTTP should not return a user")
+
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "alice", Organization: []string{"devs", "ops"}}}
+	req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+	user, err = ca.Authenticate(req)
+	assert.NilError(t, err)
+	assert.Equal(t, "alice", user.User)
+	assert.DeepEqual(t, []string{"devs", "ops"}, user.Groups)
+
+	cert.Subject.CommonName = ""
+	_, err = ca.Authenticate(req)
+	assert.Error(t, err, "client certificate has no common name")
+}
+
+func TestLoadAuthConfig(t *testing.T) {
+	assert.Assert(t, authenticators.Load() == nil, "authentication should be disabled by default")
+	enableAuth(t, map[string]string{
+		configs.CMRESTAuthTokenFile:  writeTokenFile(t, testTokens),
+		configs.CMRESTAuthClientCert: "true",
+	})
+	current := authenticators.Load()
+	assert.Assert(t, current != nil, "authentication should be enabled")
+	assert.Equal(t, 2, len(*current))
+
+	// unreadable token file: tokens are rejected
+	configs.SetConfigMap(map[string]string{
+		configs.CMRESTAuthEnabled:   "true",
+		configs.CMRESTAuthTokenFile: filepath.Join(t.TempDir(), "missing"),
+	})
+	current = authenticators.Load()
+	assert.Assert(t, current != nil, "authentication should be enabled")
+	assert.Equal(t, 1, len(*current))
+
+	configs.SetConfigMap(map[string]string{})
+	assert.Assert(t, authenticators.Load() == nil, "authentication should be disabled")
+}
+
+func TestAuthHandler(t *testing.T) {
+	partition := setup(t, configACLs, 1)
+	app := newApplication("app-1", partition.Name, "root.a", rmID, security.UserGroup{User: "alice"})
+	assert.NilError(t, partition.AddApplication(app), "failed to add application")
+	node := objects.NewNode(&si.NodeInfo{NodeID: "node-1", SchedulableResource: &si.Resource{Resources: map[string]*si.Quantity{"vcore": {Value: 10}}}})
+	assert.NilError(t, partition.AddNode(node), "failed to add node")
+	NewWebApp(schedulerContext.Load(), nil)
+	router := newRouter()
+
+	tests := map[string]struct {
+		url      string
+		token    string
+		expected int
+		total    string // X-Total-Count of a list response
+	}{
+		"public":              {url: "/ws/v1/scheduler/healthcheck", expected: http.StatusNotFound},
+		"no token":            {url: "/ws/v1/clusters", expected: http.StatusUnauthorized},
+		"invalid token":       {url: "/ws/v1/clusters", token: "unknown", expected: http.StatusUnauthorized},
+		"authenticated":       {url: "/ws/v1/clusters", token: "carol-token", expected: http.StatusOK},
+		"queue submit user":   {url: "/ws/v1/partition/default/queue/root.a", token: "alice-token", expected: http.StatusOK},
+		"queue submit group":  {url: "/ws/v1/partition/default/queue/root.b", token: "bob-token", expected: http.StatusOK},
+		"queue admin":         {url: "/ws/v1/partition/default/queue/root.a", token: "admin-token", expected: http.StatusOK},
+		"queue denied":        {url: "/ws/v1/partition/default/queue/root.a", token: "bob-token", expected: http.StatusForbidden},
+		"queue not found":     {url: "/ws/v1/partition/default/queue/root.c", token: "carol-token", expected: http.StatusNotFound},
+		"application allowed": {url: "/ws/v1/partition/default/application/app-1", token: "alice-token", expected: http.StatusOK},
+		"application denied":  {url: "/ws/v1/partition/default/application/app-1", token: "bob-token", expected: http.StatusForbidden},
+		"object allowed":      {url: "/ws/v1/application/" + app.ID, token: "alice-token", expected: http.StatusOK},
+		"object denied":       {url: "/ws/v1/application/" + app.ID, token: "carol-token", expected: http.StatusForbidden},
+		"pprof admin":         {url: "/debug/pprof/cmdline", token: "admin-token", expected: http.StatusOK},
+		"pprof denied":        {url: "/debug/pprof/cmdline", token: "alice-token", expected: http.StatusForbidden},
+		"state dump denied":   {url: "/ws/v1/fullstatedump", token: "alice-token", expected: http.StatusForbidden},
+		"users admin":         {url: "/ws/v1/partition/default/usage/users", token: "admin-token", expected: http.StatusOK},
+		"users denied":        {url: "/ws/v1/partition/default/usage/users", token: "alice-token", expected: http.StatusForbidden},
+		"own usage":           {url: "/ws/v1/partition/default/usage/user/alice", token: "alice-token", expected: http.StatusNotFound},
+		"other user usage":    {url: "/ws/v1/partition/default/usage/user/alice", token: "carol-token", expected: http.StatusForbidden},
+		"own group usage":     {url: "/ws/v1/partition/default/usage/group/devs", token: "bob-token", expected: http.StatusNotFound},
+		"other group usage":   {url: "/ws/v1/partition/default/usage/group/devs", token: "carol-token", expected: http.StatusForbidden},
+		"partition not found": {url: "/ws/v1/partition/unknown/queue/root.a", token: "alice-token", expected: http.StatusForbidden},
+		"invalid queue name":  {url: "/ws/v1/partition/default/queue/root.%25zz", token: "alice-token", expected: http.StatusForbidden},
+		"queues admin":        {url: "/ws/v1/partition/default/queues", token: "admin-token", expected: http.StatusOK},
+		"queues denied":       {url: "/ws/v1/partition/default/queues", token: "alice-token", expected: http.StatusForbidden},
+		"nodes admin":         {url: "/ws/v1/partition/default/nodes", token: "admin-token", expected: http.StatusOK},
+		"nodes denied":        {url: "/ws/v1/partition/default/nodes", token: "alice-token", expected: http.StatusForbidden},
+		"node denied":         {url: "/ws/v1/partition/default/node/node-1", token: "alice-token", expected: http.StatusForbidden},
+		"node object admin":   {url: "/ws/v1/node/" + node.ID, token: "admin-token", expected: http.StatusOK},
+		"node object denied":  {url: "/ws/v1/node/" + node.ID, token: "alice-token", expected: http.StatusForbidden},
+		"events denied":       {url: "/ws/v1/events/batch", token: "alice-token", expected: http.StatusForbidden},
+		"stream denied":       {url: "/ws/v1/events/stream", token: "alice-token", expected: http.StatusForbidden},
+		"apps of submit user": {url: "/ws/v1/partition/default/applications/active", token: "alice-token", expected: http.StatusOK, total: "1"},
+		"apps of admin":       {url: "/ws/v1/partition/default/applications/active", token: "admin-token", expected: http.StatusOK, total: "1"},
+		"apps filtered":       {url: "/ws/v1/partition/default/applications/active", token: "bob-token", expected: http.StatusOK, total: "0"},
+	}
+
+	// disabled: everything is allowed
+	req, err := http.NewRequest("GET", "/ws/v1/partition/default/usage/users", nil)
+	assert.NilError(t, err)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	enableAuth(t, map[string]string{configs.CMRESTAuthTokenFile: writeTokenFile(t, testTokens)})
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", test.url, nil)
+			assert.NilError(t, err)
+			if test.token != "" {
+				req.Header.Set("Authorization", "Bearer "+test.token)
+			}
+			resp := httptest.NewRecorder()
+			router.ServeHTTP(resp, req)
+			assert.Equal(t, test.expected, resp.Code, resp.Body.String())
+			if test.total != "" {
+				assert.Equal(t, test.total, resp.Header().Get(TotalCountHeader))
+			}
+			if test.expected == http.StatusUnauthorized {
+				assert.Equal(t, `Bearer realm="yunikorn"`, resp.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestGetRequestUser(t *testing.T) {
+	setup(t, configDefault, 1)
+	var user security.UserGroup
+	var authenticated bool
+	handler := authHandler(route{"Test", "GET", "/test", func(_ http.ResponseWriter, r *http.Request) {
+		user, authenticated = getRequestUser(r)
+	}})
+	req, err := http.NewRequest("GET", "/test", nil)
+	assert.NilError(t, err)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Assert(t, !authenticated, "user should not be set when authentication is disabled")
+
+	enableAuth(t, map[string]string{configs.CMRESTAuthTokenFile: writeTokenFile(t, testTokens)})
+	req.Header.Set("Authorization", "Bearer bob-token")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Assert(t, authenticated, "user should be set")
+	assert.Equal(t, "bob", user.User)
+	assert.DeepEqual(t, []string{"devs"}, user.Groups)
+}
@@ -0,0 +1,65 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dao
+
+// Blocking reason codes returned by the application explain endpoint.
+const (
+	ReasonQueueMaxApplications = "QueueMaxApplications"
+	ReasonUserMaxApplications  = "UserMaxApplications"
+	ReasonGroupMaxApplications = "GroupMaxApplications"
+	ReasonUserQuota            = "UserQuota"
+	ReasonGroupQuota           = "GroupQuota"
+	ReasonQueueQuota           = "QueueQuota"
+	ReasonClusterCapacity      = "ClusterCapacity"
+	ReasonReserved             = "Reserved"
+	ReasonAllocationLog        = "AllocationLog"
+)
+
+// ApplicationExplainDAOInfo lists the reasons why the pending asks of an application are not scheduled.
+type ApplicationExplainDAOInfo struct {
+	ID            string               `json:"id"`            // no omitempty, id should not be empty
+	ApplicationID string               `json:"applicationID"` // no omitempty, application id should not be empty
+	Partition     string               `json:"partition"`     // no omitempty, partition should not be empty
+	QueueName     string               `json:"queueName"`     // no omitempty, queue name should not be empty
+	State         string               `json:"applicationState,omitempty"`
+	PendingAsks   []*AskExplainDAOInfo `json:"pendingAsks"`
+}
+
+// AskExplainDAOInfo contains the ordered blocking reasons for a single pending ask.
+// The reasons follow the order in which the scheduler checks the ask.
+type AskExplainDAOInfo struct {
+	AllocationKey    string                   `json:"allocationKey"` // no omitempty, allocation key should not be empty
+	ResourcePerAlloc map[string]int64         `json:"resource,omitempty"`
+	RequiredNodeID   string                   `json:"requiredNodeId,omitempty"`
+	Reasons          []*BlockingReasonDAOInfo `json:"reasons"`
+}
+
+// BlockingReasonDAOInfo describes one reason an ask cannot be scheduled and which object imposes the limit.
+type BlockingReasonDAOInfo struct {
+	Reason          string   `json:"reason"` // no omitempty, reason should not be empty
+	Message         string   `json:"message,omitempty"`
+	ResourceTypes   []string `json:"resourceTypes,omitempty"`
+	Queue           string   `json:"queue,omitempty"`
+	User            string   `json:"user,omitempty"`
+	Group           string   `json:"group,omitempty"`
+	NodeID          string   `json:"nodeID,omitempty"`
+	MaxApplications uint64   `json:"maxApplications,omitempty"`
+	Count           int32    `json:"count,omitempty"`
+	LastOccurrence  int64    `json:"lastOccurrence,omitempty"`
+}
@@ -67,3 +67,13 @@ type PlaceholderDAOInfo struct {
 	Replaced      int64            `json:"replaced,omitempty"`
 	TimedOut      int64            `json:"timedout,omitempty"`
 }
+
+// ApplicationActionRequest the optional body of a fail or kill request.
+type ApplicationActionRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// ApplicationMoveRequest the body of a move request.
+type ApplicationMoveRequest struct {
+	Queue string `json:"queue"` // fully qualified path of the target leaf queue
+}
@@ -21,8 +21,9 @@ package dao
 import "github.com/G-Research/yunikorn-core/pkg/common/configs"
 
 type ValidateConfResponse struct {
-	Allowed bool   `json:"allowed"` // no omitempty, a false value gives a quick way to understand the result.
-	Reason  string `json:"reason,omitempty"`
+	Allowed bool                            `json:"allowed"` // no omitempty, a false value gives a quick way to understand the result.
+	Reason  string                          `json:"reason,omitempty"`
+	Impact  []*PartitionConfigImpactDAOInfo `json:"impact,omitempty"`
 }
 
 type ConfigDAOInfo struct {
@@ -31,3 +32,52 @@ type ConfigDAOInfo struct {
 	DeadlockDetectionEnabled bool
 	DeadlockTimeoutSeconds   int
 }
+
+// Changes reported by the config impact analysis.
+const (
+	ConfigChangeAdded   = "added"
+	ConfigChangeRemoved = "removed"
+	ConfigChangeDrained = "drained"
+	ConfigChangeUpdated = "updated"
+)
+
+// Violations reported by the config impact analysis.
+const (
+	ViolationQueueMaxApplications = "QueueMaxApplications"
+	ViolationQueueMaxResources    = "QueueMaxResources"
+	ViolationUserMaxApplications  = "UserMaxApplications"
+	ViolationUserMaxResources     = "UserMaxResources"
+	ViolationGroupMaxApplications = "GroupMaxApplications"
+	ViolationGroupMaxResources    = "GroupMaxResources"
+)
+
+// PartitionConfigImpactDAOInfo describes the difference between the running and the validated configuration for a
+// partition and the live applications, users and groups that would violate the new limits.
+type PartitionConfigImpactDAOInfo struct {
+	Partition  string                      `json:"partition"` // no omitempty, partition name should not be empty
+	Change     string                      `json:"change,omitempty"`
+	Queues     []*QueueConfigChangeDAOInfo `json:"queues,omitempty"`
+	Violations []*ConfigViolationDAOInfo   `json:"violations,omitempty"`
+}
+
+type QueueConfigChangeDAOInfo struct {
+	QueuePath string                      `json:"queuePath"` // no omitempty, queue path should not be empty
+	Change    string                      `json:"change"`    // no omitempty, change should not be empty
+	Fields    []*ConfigFieldChangeDAOInfo `json:"fields,omitempty"`
+}
+
+type ConfigFieldChangeDAOInfo struct {
+	Field string      `json:"field"` // no omitempty, field should not be empty
+	Old   interface{} `json:"old,omitempty"`
+	New   interface{} `json:"new,omitempty"`
+}
+
+type ConfigViolationDAOInfo struct {
+	Violation           string   `json:"violation"` // no omitempty, violation should not be empty
+	QueuePath           string   `json:"queuePath"` // no omitempty, queue path should not be empty
+	User                string   `json:"user,omitempty"`
+	Group               string   `json:"group,omitempty"`
+	MaxApplications     uint64   `json:"maxApplications,omitempty"`
+	RunningApplications []string `json:"runningApplications,omitempty"`
+	ResourceTypes       []string `json:"resourceTypes,omitempty"`
+}
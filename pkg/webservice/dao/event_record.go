@@ -28,3 +28,17 @@ type EventRecordDAO struct {
 	HighestID    uint64
 	EventRecords []*si.EventRecord
 }
+
+// EventStreamRecordDAO an event record sent on the event stream. The event ID can be used to resume the stream.
+type EventStreamRecordDAO struct {
+	*si.EventRecord
+	EventID uint64 `json:"eventID"` // no omitempty, 0 is a valid ID
+}
+
+// EventStreamGapDAO marker sent on a resumed event stream when the requested event ID is no longer available.
+// Events from RequestedID up to, but not including, LowestID were lost.
+type EventStreamGapDAO struct {
+	Gap         bool   `json:"gap"`
+	RequestedID uint64 `json:"requestedID"`
+	LowestID    uint64 `json:"lowestID"`
+}
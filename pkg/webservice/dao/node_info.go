@@ -40,4 +40,12 @@ type NodeDAOInfo struct {
 	Schedulable        bool                        `json:"schedulable"` // no omitempty, a false value gives a quick way to understand whether a node is schedulable.
 	IsReserved         bool                        `json:"isReserved"`  // no omitempty, a false value gives a quick way to understand whether a node is reserved.
 	Reservations       []string                    `json:"reservations,omitempty"`
+	CordonedBy         string                      `json:"cordonedBy,omitempty"`
+	CordonReason       string                      `json:"cordonReason,omitempty"`
+}
+
+// NodeActionRequest the optional body of a cordon, uncordon or drain request.
+type NodeActionRequest struct {
+	Reason             string `json:"reason,omitempty"`
+	GracePeriodSeconds *int64 `json:"gracePeriodSeconds,omitempty"` // drain only, the configured default if not set
 }
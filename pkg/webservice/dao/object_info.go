@@ -0,0 +1,36 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package dao
+
+const (
+	ObjectTypePartition   = "partition"
+	ObjectTypeQueue       = "queue"
+	ObjectTypeApplication = "application"
+	ObjectTypeNode        = "node"
+)
+
+// ObjectDAOInfo wraps any scheduler object found by its ID.
+// The Object is the same DAO as returned by the name based endpoint for the object type.
+type ObjectDAOInfo struct {
+	ID          string      `json:"id"`           // no omitempty, id should not be empty
+	Type        string      `json:"type"`         // no omitempty, type should not be empty
+	Partition   string      `json:"partition"`    // no omitempty, partition name should not be empty
+	PartitionID string      `json:"partition_id"` // no omitempty, partition id should not be empty
+	Object      interface{} `json:"object"`
+}
@@ -47,6 +47,8 @@ type PartitionQueueDAOInfo struct {
 	AbsUsedCapacity        map[string]int64        `json:"absUsedCapacity,omitempty"`
 	MaxRunningApps         uint64                  `json:"maxRunningApps,omitempty"`
 	RunningApps            uint64                  `json:"runningApps,omitempty"`
-	CurrentPriority        int32                   `json:"currentPriority"` // no omitempty, as the current priority value may be 0, which is a valid priority level
+	CurrentPriority        int32                   `json:"currentPriority"`        // no omitempty, as the current priority value may be 0, which is a valid priority level
+	Weight                 float64                 `json:"weight"`                 // no omitempty, weight used for fair sharing with the sibling queues
+	DecayedUsage           map[string]int64        `json:"decayedUsage,omitempty"` // allocated resources over time with decay, in resource-seconds
 	AllocatingAcceptedApps []string                `json:"allocatingAcceptedApps,omitempty"`
 }
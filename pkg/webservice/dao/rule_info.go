@@ -37,3 +37,41 @@ type RuleDAO struct {
 	Filter     *FilterDAO        `json:"filter,omitempty"`
 	ParentRule *RuleDAO          `json:"parentRule,omitempty"`
 }
+
+// Reasons a placement rule did not place the application, reported by a placement dry run.
+const (
+	PlacementSkipFilterDenied  = "FilterDenied"
+	PlacementSkipTagMissing    = "TagMissing"
+	PlacementSkipNoQueue       = "NoQueueProvided"
+	PlacementSkipQueueNotExist = "QueueNotExist"
+	PlacementSkipParentQueue   = "ParentQueue"
+	PlacementSkipSubmitDenied  = "SubmitACLDenied"
+	PlacementSkipNotForced     = "NotForced"
+)
+
+// PlacementDryRunRequest describes the application to run through the placement rules.
+type PlacementDryRunRequest struct {
+	ApplicationID string            `json:"applicationID,omitempty"`
+	User          string            `json:"user"` // no omitempty, user must exist
+	Groups        []string          `json:"groups,omitempty"`
+	Tags          map[string]string `json:"tags,omitempty"`
+	Queue         string            `json:"queue,omitempty"`
+}
+
+// PlacementDryRunDAOInfo is the outcome of running an application through the placement rules without placing it.
+type PlacementDryRunDAOInfo struct {
+	Partition   string                        `json:"partition"` // no omitempty, partition name should not be empty
+	Queue       string                        `json:"queue,omitempty"`
+	Rule        string                        `json:"rule,omitempty"`
+	QueueExists bool                          `json:"queueExists"`
+	Error       string                        `json:"error,omitempty"`
+	Rules       []*PlacementRuleResultDAOInfo `json:"rules"`
+}
+
+// PlacementRuleResultDAOInfo is the result of a single rule, in the order the rules were executed.
+type PlacementRuleResultDAOInfo struct {
+	Name       string `json:"name"` // no omitempty, name must exist
+	Queue      string `json:"queue,omitempty"`
+	Matched    bool   `json:"matched"`
+	SkipReason string `json:"skipReason,omitempty"`
+}
@@ -28,4 +28,5 @@ type HealthCheckInfo struct {
 	Succeeded        bool   // no omitempty, a false value gives a quick way to understand the result.
 	Description      string `json:",omitempty"`
 	DiagnosisMessage string `json:",omitempty"`
+	Warning          bool   `json:",omitempty"` // a failed warning check does not make the scheduler unhealthy
 }
@@ -21,9 +21,10 @@ package dao
 import "github.com/G-Research/yunikorn-core/pkg/common/resources"
 
 type UserResourceUsageDAOInfo struct {
-	UserName string                `json:"userName"` // no omitempty, user name should not be empty
-	Groups   map[string]string     `json:"groups,omitempty"`
-	Queues   *ResourceUsageDAOInfo `json:"queues,omitempty"`
+	UserName     string                `json:"userName"` // no omitempty, user name should not be empty
+	Groups       map[string]string     `json:"groups,omitempty"`
+	Queues       *ResourceUsageDAOInfo `json:"queues,omitempty"`
+	DecayedUsage map[string]int64      `json:"decayedUsage,omitempty"` // total usage over time with decay, in resource-seconds
 }
 
 type GroupResourceUsageDAOInfo struct {
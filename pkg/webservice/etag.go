@@ -0,0 +1,116 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package webservice
+
+import (
+	"fmt"
+	"hash/fnv"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/G-Research/yunikorn-core/pkg/common/configs"
+	"github.com/G-Research/yunikorn-core/pkg/scheduler"
+	"github.com/G-Research/yunikorn-core/pkg/webservice/dao"
+)
+
+// checkNotModified sets the ETag built from the parts on the response. If the ETag matches the If-None-Match
+// header of the request a 304 Not Modified is written and true is returned: the handler must not write a body.
+// The parts must be read before the response is built, a change made while building is then picked up by the
+// next request.
+func checkNotModified(w http.ResponseWriter, r *http.Request, parts ...string) bool {
+	etag := buildETag(parts...)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	if !matchesETag(r.Header.Get("If-None-Match"), etag) {
+		return false
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// buildETag returns a weak ETag for the parts: the JSON encoding of unchanged objects is equivalent but not
+// guaranteed to be byte for byte the same.
+func buildETag(parts ...string) string {
+	hash := fnv.New64a()
+	for _, part := range parts {
+		// the separator prevents different parts from producing the same input
+		_, _ = hash.Write([]byte(part))
+		_, _ = hash.Write([]byte{0})
+	}
+	return fmt.Sprintf("W/\"%x\"", hash.Sum64())
+}
+
+// matchesETag checks the If-None-Match header value against the ETag using the weak comparison.
+func matchesETag(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	opaque := strings.TrimPrefix(etag, "W/")
+	for _, value := range strings.Split(ifNoneMatch, ",") {
+		value = strings.TrimSpace(value)
+		if value == "*" || strings.TrimPrefix(value, "W/") == opaque {
+			return true
+		}
+	}
+	return false
+}
+
+// getPartitionETagParts returns the ETag parts of the partition. The ID changes when the partition is recreated,
+// the generation on every change of the partition objects.
+func getPartitionETagParts(partition *scheduler.PartitionContext) []string {
+	return []string{partition.ID, strconv.FormatUint(partition.GetGeneration(), 10)}
+}
+
+// getStateDumpETagParts returns the ETag parts of the full state dump: all partitions, the configuration and the
+// internal metrics history. The RM diagnostics and the event stream details are not tracked.
+func getStateDumpETagParts(partitions map[string]*scheduler.PartitionContext) []string {
+	parts := make([]string, 0)
+	for _, name := range slices.Sorted(maps.Keys(partitions)) {
+		parts = append(parts, name)
+		parts = append(parts, getPartitionETagParts(partitions[name])...)
+	}
+	if conf := configs.ConfigContext.Get(schedulerContext.Load().GetPolicyGroup()); conf != nil {
+		parts = append(parts, conf.Checksum)
+	}
+	configMap := configs.GetConfigMap()
+	for _, key := range slices.Sorted(maps.Keys(configMap)) {
+		parts = append(parts, key+"="+configMap[key])
+	}
+	if imHistory != nil {
+		records := imHistory.GetRecords()
+		if len(records) > 0 && records[len(records)-1] != nil {
+			last := records[len(records)-1]
+			parts = append(parts, strconv.FormatInt(last.Timestamp.UnixNano(), 10))
+		}
+	}
+	return parts
+}
+
+// clearDecayedUsage removes the decayed usage from the queue and its children. The decayed usage changes with time,
+// without a change of the partition generation, and is not covered by the ETag. The single queue response, which has
+// no ETag, returns the decayed usage.
+func clearDecayedUsage(queue *dao.PartitionQueueDAOInfo) {
+	queue.DecayedUsage = nil
+	for i := range queue.Children {
+		clearDecayedUsage(&queue.Children[i])
+	}
+}
@@ -0,0 +1,73 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package webservice
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/G-Research/yunikorn-core/pkg/webservice/dao"
+)
+
+func TestBuildETag(t *testing.T) {
+	etag := buildETag("id", "1")
+	assert.Equal(t, etag, buildETag("id", "1"))
+	assert.Assert(t, etag != buildETag("id", "2"), "generation not part of the ETag")
+	assert.Assert(t, buildETag("ab", "c") != buildETag("a", "bc"), "parts not separated")
+	assert.Equal(t, `W/"`, etag[:3])
+}
+
+func TestMatchesETag(t *testing.T) {
+	etag := buildETag("id", "1")
+	opaque := etag[2:]
+	tests := map[string]struct {
+		ifNoneMatch string
+		expected    bool
+	}{
+		"empty":    {ifNoneMatch: "", expected: false},
+		"same":     {ifNoneMatch: etag, expected: true},
+		"strong":   {ifNoneMatch: opaque, expected: true},
+		"other":    {ifNoneMatch: buildETag("id", "2"), expected: false},
+		"list":     {ifNoneMatch: buildETag("id", "2") + ", " + etag, expected: true},
+		"wildcard": {ifNoneMatch: "*", expected: true},
+		"unquoted": {ifNoneMatch: opaque[1 : len(opaque)-1], expected: false},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, matchesETag(test.ifNoneMatch, etag))
+		})
+	}
+}
+
+func TestClearDecayedUsage(t *testing.T) {
+	usage := map[string]int64{"vcore": 10}
+	queue := dao.PartitionQueueDAOInfo{
+		DecayedUsage: usage,
+		Children: []dao.PartitionQueueDAOInfo{
+			{DecayedUsage: usage, Children: []dao.PartitionQueueDAOInfo{{DecayedUsage: usage}}},
+			{DecayedUsage: usage},
+		},
+	}
+	clearDecayedUsage(&queue)
+	assert.Assert(t, queue.DecayedUsage == nil, "root decayed usage not cleared")
+	assert.Assert(t, queue.Children[0].DecayedUsage == nil, "child decayed usage not cleared")
+	assert.Assert(t, queue.Children[0].Children[0].DecayedUsage == nil, "grandchild decayed usage not cleared")
+	assert.Assert(t, queue.Children[1].DecayedUsage == nil, "child decayed usage not cleared")
+}
@@ -20,12 +20,15 @@ package webservice
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"math"
 	"net/http"
 	"net/url"
 	"runtime"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -33,6 +36,7 @@ import (
 	"time"
 
 	"github.com/julienschmidt/httprouter"
+	"github.com/oklog/ulid/v2"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
 	"gopkg.in/yaml.v3"
@@ -40,6 +44,7 @@ import (
 	"github.com/G-Research/yunikorn-core/pkg/common"
 	"github.com/G-Research/yunikorn-core/pkg/common/configs"
 	"github.com/G-Research/yunikorn-core/pkg/common/resources"
+	"github.com/G-Research/yunikorn-core/pkg/common/security"
 	"github.com/G-Research/yunikorn-core/pkg/events"
 	"github.com/G-Research/yunikorn-core/pkg/locking"
 	"github.com/G-Research/yunikorn-core/pkg/log"
@@ -50,6 +55,7 @@ import (
 	"github.com/G-Research/yunikorn-core/pkg/scheduler/objects"
 	"github.com/G-Research/yunikorn-core/pkg/scheduler/ugm"
 	"github.com/G-Research/yunikorn-core/pkg/webservice/dao"
+	"github.com/G-Research/yunikorn-scheduler-interface/lib/go/si"
 )
 
 const (
@@ -62,10 +68,23 @@ const (
 	GroupDoesNotExists       = "Group not found"
 	ApplicationDoesNotExists = "Application not found"
 	NodeDoesNotExists        = "Node not found"
+	MissingConfigChecksum    = "Base configuration checksum is required"
+	NoActivePartitions       = "No active partitions, make sure the RM is registered"
+	InvalidObjectID          = "Invalid object ID"
+	ObjectDoesNotExists      = "Object not found"
+	MissingPlacementUser     = "User is required for a placement dry run"
+	InvalidEventType         = "Invalid event type"
+	InvalidEventChangeType   = "Invalid event change type"
+	InvalidEventChangeDetail = "Invalid event change detail"
+	InvalidLastEventID       = "Invalid Last-Event-ID header"
+	InvalidGracePeriod       = "Grace period must not be negative"
+	MissingTargetQueue       = "Target queue is required"
 
 	AppStateActive    = "active"
 	AppStateRejected  = "rejected"
 	AppStateCompleted = "completed"
+
+	LastEventIDHeader = "Last-Event-ID"
 )
 
 var (
@@ -93,6 +112,8 @@ func init() {
 
 	streamingLimiter = NewStreamingLimiter()
 
+	configs.AddConfigMapCallback("rest-auth", loadAuthConfig)
+
 	configs.AddConfigMapCallback("rest-response-size", func() {
 		newSize := common.GetConfigurationUint(configs.GetConfigMap(), configs.CMRESTResponseSize, configs.DefaultRESTResponseSize)
 		if newSize == 0 {
@@ -153,8 +174,9 @@ func validateQueue(queuePath string) error {
 func validateConf(w http.ResponseWriter, r *http.Request) {
 	writeHeaders(w)
 	requestBytes, err := io.ReadAll(r.Body)
+	var conf *configs.SchedulerConfig
 	if err == nil {
-		_, err = configs.LoadSchedulerConfigFromByteArray(requestBytes)
+		conf, err = configs.LoadSchedulerConfigFromByteArray(requestBytes)
 	}
 	var result dao.ValidateConfResponse
 	if err != nil {
@@ -162,6 +184,10 @@ func validateConf(w http.ResponseWriter, r *http.Request) {
 		result.Reason = err.Error()
 	} else {
 		result.Allowed = true
+		// report the impact of the change against the running scheduler
+		if ctx := schedulerContext.Load(); ctx != nil {
+			result.Impact = ctx.GetConfigImpact(conf)
+		}
 	}
 	if err = json.NewEncoder(w).Encode(result); err != nil {
 		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
@@ -172,8 +198,8 @@ func writeHeaders(w http.ResponseWriter) {
 	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("Access-Control-Allow-Credentials", "true")
-	w.Header().Set("Access-Control-Allow-Methods", "GET,POST,HEAD,OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "X-Requested-With,Content-Type,Accept,Origin")
+	w.Header().Set("Access-Control-Allow-Methods", "GET,POST,PUT,HEAD,OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "X-Requested-With,Content-Type,Accept,Origin,If-None-Match")
 }
 
 func buildJSONErrorResponse(w http.ResponseWriter, detail string, code int) {
@@ -400,6 +426,7 @@ func getAllocationAsksDAO(asks []*objects.Allocation) []*dao.AllocationAskDAOInf
 }
 
 func getNodeDAO(node *objects.Node) *dao.NodeDAOInfo {
+	cordonedBy, cordonReason := node.GetCordonReason()
 	return &dao.NodeDAOInfo{
 		ID:                 node.ID,
 		NodeID:             node.NodeID,
@@ -417,6 +444,8 @@ func getNodeDAO(node *objects.Node) *dao.NodeDAOInfo {
 		Schedulable:        node.IsSchedulable(),
 		IsReserved:         node.IsReserved(),
 		Reservations:       node.GetReservationKeys(),
+		CordonedBy:         cordonedBy,
+		CordonReason:       cordonReason,
 	}
 }
 
@@ -640,6 +669,61 @@ func getClusterConfigDAO() *dao.ConfigDAOInfo {
 	return &conf
 }
 
+// updateConfig replaces the scheduler configuration with the one passed in the request body.
+// The caller must pass the checksum of the configuration the change is based on, either in the If-Match
+// header or as the checksum field in the configuration. The update is rejected if the active configuration
+// has changed since.
+func updateConfig(w http.ResponseWriter, r *http.Request) {
+	writeHeaders(w)
+	requestBytes, err := io.ReadAll(r.Body)
+	if err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	conf, err := configs.ParseAndValidateConfig(requestBytes)
+	if err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	baseChecksum := strings.Trim(r.Header.Get("If-Match"), "\"")
+	if baseChecksum == "" {
+		baseChecksum = conf.Checksum
+	}
+	if baseChecksum == "" {
+		buildJSONErrorResponse(w, MissingConfigChecksum, http.StatusBadRequest)
+		return
+	}
+	rmID := getRMID()
+	if rmID == "" {
+		buildJSONErrorResponse(w, NoActivePartitions, http.StatusBadRequest)
+		return
+	}
+	err = schedulerContext.Load().CheckAndUpdateRMSchedulerConfig(rmID, baseChecksum, requestBytes)
+	if err != nil {
+		if errors.Is(err, scheduler.ErrConfigChecksumMismatch) {
+			buildJSONErrorResponse(w, err.Error(), http.StatusConflict)
+			return
+		}
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	log.Log(log.REST).Info("Scheduler configuration updated via REST",
+		zap.String("rmID", rmID),
+		zap.String("baseChecksum", baseChecksum))
+	if err = json.NewEncoder(w).Encode(getClusterConfigDAO()); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// getRMID returns the ID of the RM that owns the partitions, or an empty string if there are no partitions.
+// The core is only ever registered with a single RM, all partitions share the same RM ID.
+func getRMID() string {
+	for _, partition := range schedulerContext.Load().GetPartitionMapClone() {
+		return partition.RmID
+	}
+	return ""
+}
+
 func checkHealthStatus(w http.ResponseWriter, r *http.Request) {
 	writeHeaders(w)
 
@@ -684,7 +768,11 @@ func getPartitionQueues(w http.ResponseWriter, r *http.Request) {
 	var partitionQueuesDAOInfo dao.PartitionQueueDAOInfo
 	partition := schedulerContext.Load().GetPartitionWithoutClusterID(partitionName)
 	if partition != nil {
+		if checkNotModified(w, r, getPartitionETagParts(partition)...) {
+			return
+		}
 		partitionQueuesDAOInfo = partition.GetPartitionQueues()
+		clearDecayedUsage(&partitionQueuesDAOInfo)
 	} else {
 		buildJSONErrorResponse(w, PartitionDoesNotExists, http.StatusNotFound)
 		return
@@ -736,13 +824,16 @@ func getPartitionNodes(w http.ResponseWriter, r *http.Request) {
 		buildJSONErrorResponse(w, MissingParamsName, http.StatusBadRequest)
 		return
 	}
+	opts, err := parseListOptions(r.URL.Query(), nodeSorters)
+	if err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	partition := vars.ByName("partition")
 	partitionContext := schedulerContext.Load().GetPartitionWithoutClusterID(partition)
 	if partitionContext != nil {
-		nodesDao := getNodesDAO(partitionContext.GetNodes())
-		if err := json.NewEncoder(w).Encode(nodesDao); err != nil {
-			buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
-		}
+		nodes := pageList(w, partitionContext.GetNodes(), opts, nodeSorters)
+		writeList(w, getNodesDAO(nodes), opts)
 	} else {
 		buildJSONErrorResponse(w, PartitionDoesNotExists, http.StatusNotFound)
 	}
@@ -773,6 +864,121 @@ func getPartitionNode(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func cordonNode(w http.ResponseWriter, r *http.Request) {
+	partitionContext, node, request, user, ok := getNodeActionRequest(w, r)
+	if !ok {
+		return
+	}
+	schedulerContext.Load().CordonNode(partitionContext, node, user, request.Reason)
+	if err := json.NewEncoder(w).Encode(getNodeDAO(node)); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func uncordonNode(w http.ResponseWriter, r *http.Request) {
+	partitionContext, node, request, user, ok := getNodeActionRequest(w, r)
+	if !ok {
+		return
+	}
+	schedulerContext.Load().UncordonNode(partitionContext, node, user, request.Reason)
+	if err := json.NewEncoder(w).Encode(getNodeDAO(node)); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// drainNode cordons the node and asks the RM to release the allocations on the node after the grace period.
+func drainNode(w http.ResponseWriter, r *http.Request) {
+	partitionContext, node, request, user, ok := getNodeActionRequest(w, r)
+	if !ok {
+		return
+	}
+	gracePeriod := getNodeDrainGracePeriod()
+	if request.GracePeriodSeconds != nil {
+		if *request.GracePeriodSeconds < 0 {
+			buildJSONErrorResponse(w, InvalidGracePeriod, http.StatusBadRequest)
+			return
+		}
+		gracePeriod = time.Duration(*request.GracePeriodSeconds) * time.Second
+	}
+	schedulerContext.Load().DrainNode(partitionContext, node, user, request.Reason, gracePeriod)
+	if err := json.NewEncoder(w).Encode(getNodeDAO(node)); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// getNodeActionRequest returns the partition, node, optional request body and the operator of a node action.
+// The error response is written if the boolean return value is false.
+func getNodeActionRequest(w http.ResponseWriter, r *http.Request) (*scheduler.PartitionContext, *objects.Node, *dao.NodeActionRequest, string, bool) {
+	writeHeaders(w)
+	vars := httprouter.ParamsFromContext(r.Context())
+	if vars == nil {
+		buildJSONErrorResponse(w, MissingParamsName, http.StatusBadRequest)
+		return nil, nil, nil, "", false
+	}
+	partitionContext := schedulerContext.Load().GetPartitionWithoutClusterID(vars.ByName("partition"))
+	if partitionContext == nil {
+		buildJSONErrorResponse(w, PartitionDoesNotExists, http.StatusNotFound)
+		return nil, nil, nil, "", false
+	}
+	node := partitionContext.GetNode(vars.ByName("node"))
+	if node == nil {
+		buildJSONErrorResponse(w, NodeDoesNotExists, http.StatusNotFound)
+		return nil, nil, nil, "", false
+	}
+	request := &dao.NodeActionRequest{}
+	if !decodeActionRequest(w, r, request) {
+		return nil, nil, nil, "", false
+	}
+	_, operator := getActionUser(r)
+	return partitionContext, node, request, operator, true
+}
+
+// decodeActionRequest decodes the optional body of an action request into the request.
+// The error response is written if the return value is false.
+func decodeActionRequest(w http.ResponseWriter, r *http.Request, request interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil && !errors.Is(err, io.EOF) {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// getActionUser returns the authenticated user of an action request, nil if authentication is disabled, and the
+// name of the operator used in events and messages.
+func getActionUser(r *http.Request) (*security.UserGroup, string) {
+	if user, ok := getRequestUser(r); ok {
+		return &user, user.User
+	}
+	// without authentication the operator is unknown
+	return nil, "anonymous"
+}
+
+// getActionMessage returns the message of an action: what happened, by whom and why.
+func getActionMessage(action, operator, reason string) string {
+	message := action + " by " + operator
+	if reason != "" {
+		message += ": " + reason
+	}
+	return message
+}
+
+// getNodeDrainGracePeriod returns the configured grace period of a drain.
+func getNodeDrainGracePeriod() time.Duration {
+	value, ok := configs.GetConfigMap()[configs.CMRESTNodeDrainGracePeriod]
+	if !ok {
+		return configs.DefaultRESTNodeDrainGracePeriod
+	}
+	gracePeriod, err := time.ParseDuration(value)
+	if err != nil || gracePeriod < 0 {
+		log.Log(log.REST).Warn("Failed to parse configuration value",
+			zap.String("key", configs.CMRESTNodeDrainGracePeriod),
+			zap.String("value", value),
+			zap.Error(err))
+		return configs.DefaultRESTNodeDrainGracePeriod
+	}
+	return gracePeriod
+}
+
 func getQueueApplications(w http.ResponseWriter, r *http.Request) {
 	writeHeaders(w)
 	vars := httprouter.ParamsFromContext(r.Context())
@@ -792,6 +998,11 @@ func getQueueApplications(w http.ResponseWriter, r *http.Request) {
 		buildJSONErrorResponse(w, queueErr.Error(), http.StatusBadRequest)
 		return
 	}
+	opts, err := parseListOptions(r.URL.Query(), appSorters)
+	if err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	partitionContext := schedulerContext.Load().GetPartitionWithoutClusterID(partition)
 	if partitionContext == nil {
 		buildJSONErrorResponse(w, PartitionDoesNotExists, http.StatusNotFound)
@@ -803,15 +1014,18 @@ func getQueueApplications(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	appsDao := make([]*dao.ApplicationDAOInfo, 0)
-	for _, app := range queue.GetCopyOfApps() {
-		summary := app.GetApplicationSummary(partitionContext.RmID)
-		appsDao = append(appsDao, getApplicationDAO(app, summary))
-	}
+	apps := pageList(w, slices.Collect(maps.Values(queue.GetCopyOfApps())), opts, appSorters)
+	writeList(w, getApplicationsPageDAO(apps, partitionContext.RmID), opts)
+}
 
-	if err := json.NewEncoder(w).Encode(appsDao); err != nil {
-		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+// getApplicationsPageDAO returns the DAO objects of the applications including the application summary.
+func getApplicationsPageDAO(apps []*objects.Application, rmID string) []*dao.ApplicationDAOInfo {
+	appsDao := make([]*dao.ApplicationDAOInfo, 0, len(apps))
+	for _, app := range apps {
+		summary := app.GetApplicationSummary(rmID)
+		appsDao = append(appsDao, getApplicationDAO(app, summary))
 	}
+	return appsDao
 }
 
 func getPartitionApplicationsByState(w http.ResponseWriter, r *http.Request) {
@@ -823,6 +1037,11 @@ func getPartitionApplicationsByState(w http.ResponseWriter, r *http.Request) {
 	}
 	partition := vars.ByName("partition")
 	appState := strings.ToLower(vars.ByName("state"))
+	opts, err := parseListOptions(r.URL.Query(), appSorters)
+	if err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	partitionContext := schedulerContext.Load().GetPartitionWithoutClusterID(partition)
 	if partitionContext == nil {
@@ -853,14 +1072,9 @@ func getPartitionApplicationsByState(w http.ResponseWriter, r *http.Request) {
 		buildJSONErrorResponse(w, fmt.Sprintf("Only following application states are allowed: %s, %s, %s", AppStateActive, AppStateRejected, AppStateCompleted), http.StatusBadRequest)
 		return
 	}
-	appsDao := make([]*dao.ApplicationDAOInfo, 0, len(appList))
-	for _, app := range appList {
-		summary := app.GetApplicationSummary(partitionContext.RmID)
-		appsDao = append(appsDao, getApplicationDAO(app, summary))
-	}
-	if err := json.NewEncoder(w).Encode(appsDao); err != nil {
-		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
-	}
+	appList = filterApplications(r, partitionContext, appList)
+	appList = pageList(w, appList, opts, appSorters)
+	writeList(w, getApplicationsPageDAO(appList, partitionContext.RmID), opts)
 }
 
 func getApplication(w http.ResponseWriter, r *http.Request) {
@@ -911,6 +1125,232 @@ func getApplication(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// getApplicationExplain returns the reasons why the pending asks of the application are not scheduled.
+func getApplicationExplain(w http.ResponseWriter, r *http.Request) {
+	writeHeaders(w)
+	vars := httprouter.ParamsFromContext(r.Context())
+	if vars == nil {
+		buildJSONErrorResponse(w, MissingParamsName, http.StatusBadRequest)
+		return
+	}
+	partitionContext := schedulerContext.Load().GetPartitionWithoutClusterID(vars.ByName("partition"))
+	if partitionContext == nil {
+		buildJSONErrorResponse(w, PartitionDoesNotExists, http.StatusNotFound)
+		return
+	}
+	app := partitionContext.GetApplication(vars.ByName("application"))
+	if app == nil {
+		buildJSONErrorResponse(w, ApplicationDoesNotExists, http.StatusNotFound)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(app.Explain()); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// failApplication fails the application, the RM is asked to release the allocations of the application.
+func failApplication(w http.ResponseWriter, r *http.Request) {
+	partitionContext, app, ok := getApplicationActionTarget(w, r)
+	if !ok {
+		return
+	}
+	request := &dao.ApplicationActionRequest{}
+	if !decodeActionRequest(w, r, request) {
+		return
+	}
+	user, operator := getActionUser(r)
+	message := getActionMessage("Application "+app.ApplicationID+" failed", operator, request.Reason)
+	err := schedulerContext.Load().FailApplication(partitionContext, app, user, message)
+	writeApplicationActionResponse(w, partitionContext, app, err)
+}
+
+// killApplication fails the application and removes its allocations without waiting for the RM.
+func killApplication(w http.ResponseWriter, r *http.Request) {
+	partitionContext, app, ok := getApplicationActionTarget(w, r)
+	if !ok {
+		return
+	}
+	request := &dao.ApplicationActionRequest{}
+	if !decodeActionRequest(w, r, request) {
+		return
+	}
+	user, operator := getActionUser(r)
+	message := getActionMessage("Application "+app.ApplicationID+" killed", operator, request.Reason)
+	err := schedulerContext.Load().KillApplication(partitionContext, app, user, message)
+	writeApplicationActionResponse(w, partitionContext, app, err)
+}
+
+// moveApplication moves the application to another leaf queue of the partition.
+func moveApplication(w http.ResponseWriter, r *http.Request) {
+	partitionContext, app, ok := getApplicationActionTarget(w, r)
+	if !ok {
+		return
+	}
+	request := &dao.ApplicationMoveRequest{}
+	if !decodeActionRequest(w, r, request) {
+		return
+	}
+	if request.Queue == "" {
+		buildJSONErrorResponse(w, MissingTargetQueue, http.StatusBadRequest)
+		return
+	}
+	if err := validateQueue(request.Queue); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	user, _ := getActionUser(r)
+	err := schedulerContext.Load().MoveApplication(partitionContext, app, request.Queue, user)
+	writeApplicationActionResponse(w, partitionContext, app, err)
+}
+
+// getApplicationActionTarget returns the partition and application of an application action.
+// The error response is written if the boolean return value is false.
+func getApplicationActionTarget(w http.ResponseWriter, r *http.Request) (*scheduler.PartitionContext, *objects.Application, bool) {
+	writeHeaders(w)
+	vars := httprouter.ParamsFromContext(r.Context())
+	if vars == nil {
+		buildJSONErrorResponse(w, MissingParamsName, http.StatusBadRequest)
+		return nil, nil, false
+	}
+	partitionContext := schedulerContext.Load().GetPartitionWithoutClusterID(vars.ByName("partition"))
+	if partitionContext == nil {
+		buildJSONErrorResponse(w, PartitionDoesNotExists, http.StatusNotFound)
+		return nil, nil, false
+	}
+	app := partitionContext.GetApplication(vars.ByName("application"))
+	if app == nil {
+		buildJSONErrorResponse(w, ApplicationDoesNotExists, http.StatusNotFound)
+		return nil, nil, false
+	}
+	return partitionContext, app, true
+}
+
+// writeApplicationActionResponse writes the application, or the error of the action. A user without admin access
+// to the queues is denied access, all other errors are caused by the request.
+func writeApplicationActionResponse(w http.ResponseWriter, partitionContext *scheduler.PartitionContext, app *objects.Application, err error) {
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, scheduler.ErrQueueAdminAccess) {
+			status = http.StatusForbidden
+		}
+		buildJSONErrorResponse(w, err.Error(), status)
+		return
+	}
+	appDao := getApplicationDAO(app, app.GetApplicationSummary(partitionContext.RmID))
+	if err = json.NewEncoder(w).Encode(appDao); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// getObjectByID returns the partition, queue, application or node with the ID from the request.
+func getObjectByID(w http.ResponseWriter, r *http.Request) {
+	writeHeaders(w)
+	partitionContext, object, ok := lookupObjectByID(w, r)
+	if !ok {
+		return
+	}
+	objectDao := &dao.ObjectDAOInfo{
+		Partition:   common.GetPartitionNameWithoutClusterID(partitionContext.Name),
+		PartitionID: partitionContext.ID,
+	}
+	switch obj := object.(type) {
+	case *scheduler.PartitionContext:
+		objectDao.ID = obj.ID
+		objectDao.Type = dao.ObjectTypePartition
+		objectDao.Object = getPartitionInfoDAO(map[string]*scheduler.PartitionContext{obj.Name: obj})[0]
+	case *objects.Queue:
+		objectDao.ID = obj.ID
+		objectDao.Type = dao.ObjectTypeQueue
+		objectDao.Object = obj.GetPartitionQueueDAOInfo(r.URL.Query().Has("subtree"))
+	case *objects.Application:
+		objectDao.ID = obj.ID
+		objectDao.Type = dao.ObjectTypeApplication
+		objectDao.Object = getApplicationDAO(obj, obj.GetApplicationSummary(partitionContext.RmID))
+	case *objects.Node:
+		objectDao.ID = obj.ID
+		objectDao.Type = dao.ObjectTypeNode
+		objectDao.Object = getNodeDAO(obj)
+	}
+	if err := json.NewEncoder(w).Encode(objectDao); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// getQueueByID returns the queue with the ID from the request, the response is the same as for getPartitionQueue.
+func getQueueByID(w http.ResponseWriter, r *http.Request) {
+	writeHeaders(w)
+	_, object, ok := lookupObjectByID(w, r)
+	if !ok {
+		return
+	}
+	queue, ok := object.(*objects.Queue)
+	if !ok {
+		buildJSONErrorResponse(w, QueueDoesNotExists, http.StatusNotFound)
+		return
+	}
+	queueDao := queue.GetPartitionQueueDAOInfo(r.URL.Query().Has("subtree"))
+	if err := json.NewEncoder(w).Encode(queueDao); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// getApplicationByID returns the application with the ID from the request, the response is the same as for
+// getApplication.
+func getApplicationByID(w http.ResponseWriter, r *http.Request) {
+	writeHeaders(w)
+	partitionContext, object, ok := lookupObjectByID(w, r)
+	if !ok {
+		return
+	}
+	app, ok := object.(*objects.Application)
+	if !ok {
+		buildJSONErrorResponse(w, ApplicationDoesNotExists, http.StatusNotFound)
+		return
+	}
+	appDao := getApplicationDAO(app, app.GetApplicationSummary(partitionContext.RmID))
+	if err := json.NewEncoder(w).Encode(appDao); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// getNodeByID returns the node with the ID from the request, the response is the same as for getPartitionNode.
+func getNodeByID(w http.ResponseWriter, r *http.Request) {
+	writeHeaders(w)
+	_, object, ok := lookupObjectByID(w, r)
+	if !ok {
+		return
+	}
+	node, ok := object.(*objects.Node)
+	if !ok {
+		buildJSONErrorResponse(w, NodeDoesNotExists, http.StatusNotFound)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(getNodeDAO(node)); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// lookupObjectByID resolves the id parameter from the request against the live scheduler objects.
+// The error response has been written if the lookup was not successful.
+func lookupObjectByID(w http.ResponseWriter, r *http.Request) (*scheduler.PartitionContext, interface{}, bool) {
+	vars := httprouter.ParamsFromContext(r.Context())
+	if vars == nil {
+		buildJSONErrorResponse(w, MissingParamsName, http.StatusBadRequest)
+		return nil, nil, false
+	}
+	id := vars.ByName("id")
+	if _, err := ulid.ParseStrict(id); err != nil {
+		buildJSONErrorResponse(w, InvalidObjectID, http.StatusBadRequest)
+		return nil, nil, false
+	}
+	partitionContext, object := schedulerContext.Load().GetObjectByID(id)
+	if object == nil {
+		buildJSONErrorResponse(w, ObjectDoesNotExists, http.StatusNotFound)
+		return nil, nil, false
+	}
+	return partitionContext, object, true
+}
+
 func getPartitionRules(w http.ResponseWriter, r *http.Request) {
 	writeHeaders(w)
 	vars := httprouter.ParamsFromContext(r.Context())
@@ -930,6 +1370,34 @@ func getPartitionRules(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// placementDryRun runs the application described in the request body through the placement rules of the partition.
+// Nothing is created or changed in the partition.
+func placementDryRun(w http.ResponseWriter, r *http.Request) {
+	writeHeaders(w)
+	vars := httprouter.ParamsFromContext(r.Context())
+	if vars == nil {
+		buildJSONErrorResponse(w, MissingParamsName, http.StatusBadRequest)
+		return
+	}
+	partitionContext := schedulerContext.Load().GetPartitionWithoutClusterID(vars.ByName("partition"))
+	if partitionContext == nil {
+		buildJSONErrorResponse(w, PartitionDoesNotExists, http.StatusNotFound)
+		return
+	}
+	var request dao.PlacementDryRunRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if request.User == "" {
+		buildJSONErrorResponse(w, MissingPlacementUser, http.StatusBadRequest)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(partitionContext.PlacementDryRun(&request)); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
 func getQueueApplicationsByState(w http.ResponseWriter, r *http.Request) {
 	writeHeaders(w)
 	vars := httprouter.ParamsFromContext(r.Context())
@@ -1110,7 +1578,9 @@ func getPartitionQueuesDAO(lists map[string]*scheduler.PartitionContext) []dao.P
 	result := make([]dao.PartitionQueueDAOInfo, 0, len(lists))
 
 	for _, partition := range lists {
-		result = append(result, partition.GetPartitionQueues())
+		queues := partition.GetPartitionQueues()
+		clearDecayedUsage(&queues)
+		result = append(result, queues)
 	}
 
 	return result
@@ -1169,17 +1639,20 @@ func getMetrics(w http.ResponseWriter, r *http.Request) {
 	promhttp.Handler().ServeHTTP(w, r)
 }
 
-func getUsersResourceUsage(w http.ResponseWriter, _ *http.Request) {
+func getUsersResourceUsage(w http.ResponseWriter, r *http.Request) {
 	writeHeaders(w)
+	opts, err := parseListOptions(r.URL.Query(), userSorters)
+	if err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	userManager := ugm.GetUserManager()
 	usersResources := userManager.GetUsersResources()
 	result := make([]*dao.UserResourceUsageDAOInfo, len(usersResources))
 	for i, tracker := range usersResources {
 		result[i] = tracker.GetUserResourceUsageDAOInfo()
 	}
-	if err := json.NewEncoder(w).Encode(result); err != nil {
-		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
-	}
+	writeList(w, pageList(w, result, opts, userSorters), opts)
 }
 
 func getUserResourceUsage(w http.ResponseWriter, r *http.Request) {
@@ -1287,7 +1760,16 @@ func getEvents(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	filter, err := getEventFilter(r)
+	if err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// the filter is applied to the range of "count" events starting at "start": paging through the
+	// history using the ID range works the same with or without a filter
 	records, lowestID, highestID := eventSystem.GetEventsFromID(start, count)
+	records = filter.Filter(records)
 	eventDao := dao.EventRecordDAO{
 		InstanceUUID: schedulerContext.Load().GetUUID(),
 		LowestID:     lowestID,
@@ -1299,6 +1781,83 @@ func getEvents(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// getStreamStart returns the event ID a stream must resume from. The "start" query parameter is the ID of the first
+// event to send. Without it the "Last-Event-ID" header is used, which is the ID of the last event the client received.
+// The boolean return value is false if the request does not resume a previous stream.
+func getStreamStart(r *http.Request) (uint64, bool, error) {
+	if startStr := r.URL.Query().Get("start"); startStr != "" {
+		start, err := strconv.ParseUint(startStr, 10, 64)
+		if err != nil {
+			return 0, false, err
+		}
+		return start, true, nil
+	}
+	if lastStr := r.Header.Get(LastEventIDHeader); lastStr != "" {
+		last, err := strconv.ParseUint(lastStr, 10, 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("%s: %s", InvalidLastEventID, lastStr)
+		}
+		return last + 1, true, nil
+	}
+	return 0, false, nil
+}
+
+// getEventFilter builds the event filter from the query parameters of the request.
+// Every filter parameter can be repeated or contain a comma separated list of values.
+// Returns nil if the request does not contain any filter parameters.
+func getEventFilter(r *http.Request) (*events.EventFilter, error) {
+	query := r.URL.Query()
+	filter := &events.EventFilter{
+		ObjectIDs:    getQueryValues(query, "objectID"),
+		ReferenceIDs: getQueryValues(query, "referenceID"),
+		Partitions:   getQueryValues(query, "partition"),
+	}
+	for _, value := range getQueryValues(query, "type") {
+		eventType, ok := si.EventRecord_Type_value[strings.ToUpper(value)]
+		if !ok {
+			return nil, fmt.Errorf("%s: %s", InvalidEventType, value)
+		}
+		filter.Types = append(filter.Types, si.EventRecord_Type(eventType))
+	}
+	for _, value := range getQueryValues(query, "changeType") {
+		changeType, ok := si.EventRecord_ChangeType_value[strings.ToUpper(value)]
+		if !ok {
+			return nil, fmt.Errorf("%s: %s", InvalidEventChangeType, value)
+		}
+		filter.ChangeTypes = append(filter.ChangeTypes, si.EventRecord_ChangeType(changeType))
+	}
+	for _, value := range getQueryValues(query, "changeDetail") {
+		changeDetail, ok := si.EventRecord_ChangeDetail_value[strings.ToUpper(value)]
+		if !ok {
+			return nil, fmt.Errorf("%s: %s", InvalidEventChangeDetail, value)
+		}
+		filter.ChangeDetails = append(filter.ChangeDetails, si.EventRecord_ChangeDetail(changeDetail))
+	}
+	if filter.IsEmpty() {
+		return nil, nil
+	}
+	if len(filter.Partitions) != 0 {
+		if ctx := schedulerContext.Load(); ctx != nil {
+			filter.PartitionMatcher = ctx.IsEventInPartition
+		}
+	}
+	return filter, nil
+}
+
+// getQueryValues returns all values of a repeatable query parameter, splitting comma separated values.
+// Empty values are dropped.
+func getQueryValues(query url.Values, key string) []string {
+	var values []string
+	for _, param := range query[key] {
+		for _, value := range strings.Split(param, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
 func getStream(w http.ResponseWriter, r *http.Request) {
 	writeHeaders(w)
 	eventSystem := events.GetEventSystem()
@@ -1329,6 +1888,18 @@ func getStream(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	start, resume, err := getStreamStart(r)
+	if err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter, err := getEventFilter(r)
+	if err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	rc := http.NewResponseController(w)
 	// make sure both deadlines can be set
 	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
@@ -1342,7 +1913,12 @@ func getStream(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	enc := json.NewEncoder(w)
-	stream := eventSystem.CreateEventStream(r.Host, count)
+	var stream *events.EventStream
+	if resume {
+		stream = eventSystem.CreateEventStreamFromID(r.Host, start, filter)
+	} else {
+		stream = eventSystem.CreateEventStream(r.Host, count, filter)
+	}
 	defer eventSystem.RemoveStream(stream)
 
 	if err := enc.Encode(dao.YunikornID{
@@ -1351,6 +1927,17 @@ func getStream(w http.ResponseWriter, r *http.Request) {
 		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	// tell the client about lost events before sending any of the replayed events
+	if stream.Gap != nil {
+		if err := enc.Encode(dao.EventStreamGapDAO{
+			Gap:         true,
+			RequestedID: stream.Gap.RequestedID,
+			LowestID:    stream.Gap.LowestID,
+		}); err != nil {
+			buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
 	f.Flush()
 
 	// Reading events in an infinite loop until either the client disconnects or Yunikorn closes the channel.
@@ -1379,7 +1966,10 @@ func getStream(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 
-			if err := enc.Encode(e); err != nil {
+			if err := enc.Encode(dao.EventStreamRecordDAO{
+				EventRecord: e.Event,
+				EventID:     e.ID,
+			}); err != nil {
 				log.Log(log.REST).Error("Marshalling error",
 					zap.String("host", r.Host))
 				buildJSONErrorResponse(w, err.Error(), http.StatusOK) // status code is 200 at this point, cannot be changed
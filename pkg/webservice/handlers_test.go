@@ -387,6 +387,37 @@ func TestValidateConf(t *testing.T) {
 	}
 }
 
+func TestValidateConfImpact(t *testing.T) {
+	partition := setup(t, configDefault, 1)
+	addApp(t, "app-1", partition, "root.noapps", false)
+	const newConf = `
+partitions:
+  - name: default
+    queues:
+      - name: root
+        submitacl: "*"
+        queues:
+          - name: default
+            submitacl: "testuser"
+          - name: added
+`
+	req, err := http.NewRequest("POST", "/ws/v1/validate-conf", strings.NewReader(newConf))
+	assert.NilError(t, err, httpRequestError)
+	resp := &MockResponseWriter{}
+	validateConf(resp, req)
+	var vcr dao.ValidateConfResponse
+	err = json.Unmarshal(resp.outputBytes, &vcr)
+	assert.NilError(t, err, unmarshalError)
+	assert.Assert(t, vcr.Allowed, "config should be valid")
+	assert.Equal(t, len(vcr.Impact), 1, "expected impact on the default partition")
+	assert.Equal(t, vcr.Impact[0].Change, dao.ConfigChangeUpdated)
+	assert.DeepEqual(t, vcr.Impact[0].Queues, []*dao.QueueConfigChangeDAOInfo{
+		{QueuePath: "root.default", Change: dao.ConfigChangeUpdated, Fields: []*dao.ConfigFieldChangeDAOInfo{{Field: "submitACL", Old: "", New: "testuser"}}},
+		{QueuePath: "root.added", Change: dao.ConfigChangeAdded},
+		{QueuePath: "root.noapps", Change: dao.ConfigChangeDrained},
+	})
+}
+
 func TestUserGroupLimits(t *testing.T) {
 	confTests := []struct {
 		content          string
@@ -609,6 +640,52 @@ func TestGetConfigJSON(t *testing.T) {
 	configs.SetConfigMap(map[string]string{})
 }
 
+func TestUpdateConfig(t *testing.T) {
+	setup(t, startConf, 1)
+	base := configs.ConfigContext.Get(policyGroup).Checksum
+
+	// invalid config is rejected before the checksum is checked
+	req, err := http.NewRequest("PUT", "/ws/v1/config", strings.NewReader(invalidConf))
+	assert.NilError(t, err, httpRequestError)
+	req.Header.Set("If-Match", base)
+	resp := &MockResponseWriter{}
+	updateConfig(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, statusCodeError)
+
+	// no base checksum
+	req, err = http.NewRequest("PUT", "/ws/v1/config", strings.NewReader(updatedConf))
+	assert.NilError(t, err, httpRequestError)
+	resp = &MockResponseWriter{}
+	updateConfig(resp, req)
+	var errInfo dao.YAPIError
+	err = json.Unmarshal(resp.outputBytes, &errInfo)
+	assert.NilError(t, err, unmarshalError)
+	assert.Equal(t, http.StatusBadRequest, errInfo.StatusCode, statusCodeError)
+	assert.Equal(t, MissingConfigChecksum, errInfo.Message, jsonMessageError)
+
+	// stale base checksum
+	req, err = http.NewRequest("PUT", "/ws/v1/config", strings.NewReader(updatedConf))
+	assert.NilError(t, err, httpRequestError)
+	req.Header.Set("If-Match", "stale")
+	resp = &MockResponseWriter{}
+	updateConfig(resp, req)
+	assert.Equal(t, http.StatusConflict, resp.statusCode, statusCodeError)
+	assert.Equal(t, base, configs.ConfigContext.Get(policyGroup).Checksum, "config should not have changed")
+
+	// checksum passed in the config itself
+	req, err = http.NewRequest("PUT", "/ws/v1/config", strings.NewReader(updatedConf+"checksum: "+base+"\n"))
+	assert.NilError(t, err, httpRequestError)
+	resp = &MockResponseWriter{}
+	updateConfig(resp, req)
+	assert.Equal(t, 0, resp.statusCode, statusCodeError)
+	conf := &dao.ConfigDAOInfo{}
+	err = json.Unmarshal(resp.outputBytes, conf)
+	assert.NilError(t, err, unmarshalError)
+	assert.Equal(t, conf.Partitions[0].NodeSortPolicy.Type, "binpacking", "node sort policy not updated")
+	assert.Assert(t, base != conf.Checksum, "checksum did not change")
+	assert.Equal(t, conf.Checksum, configs.ConfigContext.Get(policyGroup).Checksum)
+}
+
 func TestGetClusterUtilJSON(t *testing.T) {
 	setup(t, configDefault, 1)
 
@@ -1174,6 +1251,35 @@ func TestGetPartitionQueuesHandler(t *testing.T) {
 	assertParamsMissing(t, resp)
 }
 
+func TestGetPartitionQueuesHandler_ETag(t *testing.T) {
+	partition := setup(t, configDefault, 1)
+	NewWebApp(schedulerContext.Load(), nil)
+
+	req, err := createRequest(t, "/ws/v1/partition/default/queues", map[string]string{"partition": partitionNameWithoutClusterID})
+	assert.NilError(t, err, "Get Queues for PartitionQueues Handler request failed")
+	resp := &MockResponseWriter{}
+	getPartitionQueues(resp, req)
+	etag := resp.Header().Get("ETag")
+	assert.Assert(t, etag != "", "ETag not set")
+	assert.Assert(t, len(resp.outputBytes) > 0, "expected a response body")
+
+	// nothing changed: not modified without a body
+	req.Header.Set("If-None-Match", etag)
+	resp = &MockResponseWriter{}
+	getPartitionQueues(resp, req)
+	assert.Equal(t, http.StatusNotModified, resp.statusCode)
+	assert.Equal(t, 0, len(resp.outputBytes))
+	assert.Equal(t, etag, resp.Header().Get("ETag"))
+
+	// adding a node changes the root queue resources
+	addNode(t, partition, "node-1", resources.NewResourceFromMap(map[string]resources.Quantity{siCommon.Memory: 1000}))
+	resp = &MockResponseWriter{}
+	getPartitionQueues(resp, req)
+	assert.Equal(t, 0, resp.statusCode, "expected the implicit 200 status")
+	assert.Assert(t, len(resp.outputBytes) > 0, "expected a response body")
+	assert.Assert(t, etag != resp.Header().Get("ETag"), "ETag not changed")
+}
+
 func assertPartitionQueueDaoInfo(t *testing.T, partitionQueueDAOInfo *dao.PartitionQueueDAOInfo, queueName string, partition string, partitionID string, maxResource map[string]int64, gResource map[string]int64, leaf bool, isManaged bool, parent string, templateInfo *dao.TemplateInfo) {
 	assert.Assert(t, partitionQueueDAOInfo.ID != "")
 	assert.Equal(t, partitionQueueDAOInfo.QueueName, queueName)
@@ -1391,6 +1497,48 @@ func TestGetPartitionNodes(t *testing.T) {
 	assertParamsMissing(t, resp)
 }
 
+func TestGetPartitionNodes_ListOptions(t *testing.T) {
+	partition := setup(t, configDefault, 1)
+	nodeRes := resources.NewResourceFromMap(map[string]resources.Quantity{siCommon.Memory: 1000, siCommon.CPU: 1000})
+	node1 := addNode(t, partition, "node-1", nodeRes)
+	node2 := addNode(t, partition, "node-2", nodeRes)
+	addNode(t, partition, "node-3", nodeRes)
+	addAllocatedResource(t, node1, "alloc-1", "app-1", map[string]resources.Quantity{siCommon.Memory: 100})
+	addAllocatedResource(t, node2, "alloc-2", "app-1", map[string]resources.Quantity{siCommon.Memory: 500})
+	NewWebApp(schedulerContext.Load(), nil)
+
+	req, err := createRequest(t, "/ws/v1/partition/default/nodes?sort=-used.memory&limit=2&fields=nodeID", map[string]string{"partition": partitionNameWithoutClusterID})
+	assert.NilError(t, err, "Get Nodes for PartitionNodes Handler request failed")
+	resp := &MockResponseWriter{}
+	getPartitionNodes(resp, req)
+	var nodes []map[string]interface{}
+	err = json.Unmarshal(resp.outputBytes, &nodes)
+	assert.NilError(t, err, unmarshalError)
+	assert.DeepEqual(t, []map[string]interface{}{{"nodeID": "node-2"}, {"nodeID": "node-1"}}, nodes)
+	assert.Equal(t, "3", resp.Header().Get(TotalCountHeader))
+
+	req, err = createRequest(t, "/ws/v1/partition/default/nodes?limit=2&offset=2", map[string]string{"partition": partitionNameWithoutClusterID})
+	assert.NilError(t, err, "Get Nodes for PartitionNodes Handler request failed")
+	resp = &MockResponseWriter{}
+	getPartitionNodes(resp, req)
+	var nodesDao []*dao.NodeDAOInfo
+	err = json.Unmarshal(resp.outputBytes, &nodesDao)
+	assert.NilError(t, err, unmarshalError)
+	assert.Equal(t, 1, len(nodesDao))
+	assert.Equal(t, "node-3", nodesDao[0].NodeID)
+	assert.Equal(t, "3", resp.Header().Get(TotalCountHeader))
+
+	req, err = createRequest(t, "/ws/v1/partition/default/nodes?sort=submissionTime", map[string]string{"partition": partitionNameWithoutClusterID})
+	assert.NilError(t, err, "Get Nodes for PartitionNodes Handler request failed")
+	resp = &MockResponseWriter{}
+	getPartitionNodes(resp, req)
+	var errInfo dao.YAPIError
+	err = json.Unmarshal(resp.outputBytes, &errInfo)
+	assert.NilError(t, err, unmarshalError)
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode)
+	assert.Equal(t, `sort field "submissionTime" is not supported, supported fields: name, used.<resource>`, errInfo.Message)
+}
+
 func TestGetPartitionNode(t *testing.T) {
 	partition := setup(t, configDefault, 1)
 
@@ -2034,6 +2182,9 @@ func assertUserExists(t *testing.T, resp *MockResponseWriter, expected *dao.User
 	var actual *dao.UserResourceUsageDAOInfo
 	err := json.Unmarshal(resp.outputBytes, &actual)
 	assert.NilError(t, err, unmarshalError)
+	// the decayed usage depends on the time of the request
+	assert.Assert(t, actual.DecayedUsage != nil, "decayed usage should be returned for a user with usage")
+	actual.DecayedUsage = nil
 	assert.DeepEqual(t, actual, expected)
 }
 
@@ -2144,6 +2295,22 @@ func TestFullStateDumpPath(t *testing.T) {
 	assert.NilError(t, err)
 	// default config has only one partition
 	verifyStateDumpJSON(t, &aggregated, 1)
+
+	// nothing changed: not modified without a body
+	etag := resp.Header().Get("ETag")
+	assert.Assert(t, etag != "", "ETag not set")
+	req.Header.Set("If-None-Match", etag)
+	resp = &MockResponseWriter{}
+	getFullStateDump(resp, req)
+	assert.Equal(t, http.StatusNotModified, resp.statusCode)
+	assert.Equal(t, 0, len(resp.outputBytes))
+
+	// a config change changes the ETag
+	configs.SetConfigMap(map[string]string{"log.level": "INFO"})
+	resp = &MockResponseWriter{}
+	getFullStateDump(resp, req)
+	assert.Assert(t, resp.statusCode != http.StatusNotModified, "state dump should have been returned")
+	assert.Assert(t, etag != resp.Header().Get("ETag"), "ETag not changed")
 }
 
 func TestSpecificUserResourceUsage(t *testing.T) {
@@ -2347,12 +2514,30 @@ func TestGetEvents(t *testing.T) {
 	checkSingleEvent(t, appEvent, "count=1")
 	checkSingleEvent(t, queueEvent, "start=2")
 
+	// filtered requests
+	checkSingleEvent(t, nodeEvent, "type=node")
+	checkSingleEvent(t, queueEvent, "changeType=REMOVE")
+	checkSingleEvent(t, queueEvent, "changeDetail=queue_app")
+	checkSingleEvent(t, appEvent, "objectID=app")
+	checkSingleEvent(t, appEvent, "type=app,queue&referenceID=alloc")
+	checkSingleEvent(t, nodeEvent, "type=app&type=node&objectID=node")
+	checkSingleEvent(t, queueEvent, "partition=default")
+	checkSingleEvent(t, nodeEvent, "start=1&count=1&referenceID=alloc")
+	req, err := http.NewRequest("GET", "/ws/v1/events/batch?objectID=unknown", strings.NewReader(""))
+	assert.NilError(t, err)
+	eventDao := getEventRecordDao(t, req)
+	assert.Equal(t, 0, len(eventDao.EventRecords))
+	assert.Equal(t, uint64(2), eventDao.HighestID)
+
 	// illegal requests
 	checkIllegalBatchRequest(t, "count=xyz", `strconv.ParseUint: parsing "xyz": invalid syntax`)
 	checkIllegalBatchRequest(t, "count=-100", `strconv.ParseUint: parsing "-100": invalid syntax`)
 	checkIllegalBatchRequest(t, "count=0", `0 is not a valid value for "count`)
 	checkIllegalBatchRequest(t, "start=xyz", `strconv.ParseUint: parsing "xyz": invalid syntax`)
 	checkIllegalBatchRequest(t, "start=-100", `strconv.ParseUint: parsing "-100": invalid syntax`)
+	checkIllegalBatchRequest(t, "type=xyz", InvalidEventType+": xyz")
+	checkIllegalBatchRequest(t, "changeType=xyz", InvalidEventChangeType+": xyz")
+	checkIllegalBatchRequest(t, "changeDetail=xyz", InvalidEventChangeDetail+": xyz")
 
 	// "count" too high
 	maxRESTResponseSize.Store(1)
@@ -2420,6 +2605,135 @@ func TestGetStream(t *testing.T) {
 	assertEvent(t, lines[3], 333, "app-2")
 }
 
+func TestGetStream_Filtered(t *testing.T) {
+	setup(t, configDefault, 1)
+	ev, req := initEventsAndCreateRequest(t)
+	defer ev.Stop()
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req = req.Clone(cancelCtx)
+	req.URL.RawQuery = "type=app&objectID=app-1,app-2"
+
+	resp := NewResponseRecorderWithDeadline() // MockResponseWriter does not implement http.Flusher
+
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		ev.AddEvent(&si.EventRecord{
+			Type:          si.EventRecord_APP,
+			TimestampNano: 111,
+			ObjectID:      "app-1",
+		})
+		ev.AddEvent(&si.EventRecord{
+			Type:          si.EventRecord_NODE,
+			TimestampNano: 222,
+			ObjectID:      "node-1",
+		})
+		ev.AddEvent(&si.EventRecord{
+			Type:          si.EventRecord_APP,
+			TimestampNano: 333,
+			ObjectID:      "app-3",
+		})
+		ev.AddEvent(&si.EventRecord{
+			Type:          si.EventRecord_APP,
+			TimestampNano: 444,
+			ObjectID:      "app-2",
+		})
+		time.Sleep(200 * time.Millisecond)
+		cancel()
+	}()
+	getStream(resp, req)
+
+	output := make([]byte, 256)
+	n, err := resp.Body.Read(output)
+	assert.NilError(t, err, "cannot read response body")
+
+	lines := strings.Split(string(output[:n]), "\n")
+	assert.Equal(t, 4, len(lines), "unexpected stream output: %s", string(output[:n]))
+	assertInstanceUUID(t, lines[0])
+	assertEvent(t, lines[1], 111, "app-1")
+	assertEvent(t, lines[2], 444, "app-2")
+
+	// illegal filter value
+	req, err = http.NewRequest("GET", "/ws/v1/events/stream?changeType=xyz", strings.NewReader(""))
+	assert.NilError(t, err)
+	resp = NewResponseRecorderWithDeadline()
+	getStream(resp, req)
+	n, err = resp.Body.Read(output)
+	assert.NilError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assertYunikornError(t, string(output[:n]), InvalidEventChangeType+": xyz")
+}
+
+func TestGetStream_Resume(t *testing.T) {
+	setup(t, configDefault, 1)
+	ev, _ := initEventsAndCreateRequest(t)
+	defer ev.Stop()
+	for i := 0; i < 3; i++ {
+		ev.AddEvent(&si.EventRecord{TimestampNano: int64(100 + i)})
+	}
+	err := common.WaitForCondition(10*time.Millisecond, time.Second, func() bool {
+		return ev.Store.CountStoredEvents() == 3
+	})
+	assert.NilError(t, err, "events not processed")
+
+	readStream := func(query string, header string) []string {
+		req, err := http.NewRequest("GET", "/ws/v1/events/stream?"+query, strings.NewReader(""))
+		assert.NilError(t, err)
+		if header != "" {
+			req.Header.Set(LastEventIDHeader, header)
+		}
+		cancelCtx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		req = req.Clone(cancelCtx)
+		resp := NewResponseRecorderWithDeadline() // MockResponseWriter does not implement http.Flusher
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			cancel()
+		}()
+		getStream(resp, req)
+		output := make([]byte, 1024)
+		n, err := resp.Body.Read(output)
+		assert.NilError(t, err, "cannot read response body")
+		return strings.Split(string(output[:n]), "\n")
+	}
+
+	// replay from the start ID, "count" is ignored
+	lines := readStream("start=1&count=1", "")
+	assert.Equal(t, 4, len(lines))
+	assertInstanceUUID(t, lines[0])
+	assertStreamEvent(t, lines[1], 1, 101)
+	assertStreamEvent(t, lines[2], 2, 102)
+
+	// resume after the last received event
+	lines = readStream("", "1")
+	assert.Equal(t, 3, len(lines))
+	assertStreamEvent(t, lines[1], 2, 102)
+
+	// query parameter takes precedence over the header
+	lines = readStream("start=0", "1")
+	assert.Equal(t, 5, len(lines))
+	assertStreamEvent(t, lines[1], 0, 100)
+
+	// up to date: nothing replayed
+	lines = readStream("start=3", "")
+	assert.Equal(t, 2, len(lines))
+
+	// ID not in the ring buffer: gap marker then all available events
+	lines = readStream("start=100", "")
+	assert.Equal(t, 6, len(lines))
+	var gap dao.EventStreamGapDAO
+	err = json.Unmarshal([]byte(lines[1]), &gap)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, dao.EventStreamGapDAO{Gap: true, RequestedID: 100, LowestID: 0}, gap)
+	assertStreamEvent(t, lines[2], 0, 100)
+
+	// illegal values
+	lines = readStream("start=xyz", "")
+	assertYunikornError(t, lines[0], `strconv.ParseUint: parsing "xyz": invalid syntax`)
+	lines = readStream("", "xyz")
+	assertYunikornError(t, lines[0], InvalidLastEventID+": xyz")
+}
+
 func TestGetStream_StreamClosedByProducer(t *testing.T) {
 	ev, req := initEventsAndCreateRequest(t)
 	defer ev.Stop()
@@ -2662,6 +2976,15 @@ func assertEvent(t *testing.T, output string, tsNano int64, objectID string) {
 	assert.Equal(t, objectID, evt.ObjectID)
 }
 
+func assertStreamEvent(t *testing.T, output string, eventID uint64, tsNano int64) {
+	t.Helper()
+	var evt dao.EventStreamRecordDAO
+	err := json.Unmarshal([]byte(output), &evt)
+	assert.NilError(t, err)
+	assert.Equal(t, eventID, evt.EventID)
+	assert.Equal(t, tsNano, evt.TimestampNano)
+}
+
 func assertInstanceUUID(t *testing.T, output string) {
 	var id dao.YunikornID
 	err := json.Unmarshal([]byte(output), &id)
@@ -2923,6 +3246,186 @@ func runHealthCheckTest(t *testing.T, expected *dao.SchedulerHealthDAOInfo) {
 	}
 }
 
+func TestGetObjectByID(t *testing.T) {
+	partition := setup(t, configDefault, 1)
+	app := addApp(t, "app-1", partition, queueName, false)
+	nodeRes := resources.NewResourceFromMap(map[string]resources.Quantity{siCommon.Memory: 1000}).ToProto()
+	node := objects.NewNode(&si.NodeInfo{NodeID: nodeID, SchedulableResource: nodeRes})
+	err := partition.AddNode(node)
+	assert.NilError(t, err, "add node to partition should not have failed")
+	queue := partition.GetQueue(queueName)
+	NewWebApp(schedulerContext.Load(), nil)
+
+	// generic lookup for every object type
+	tests := map[string]string{
+		partition.ID: dao.ObjectTypePartition,
+		queue.ID:     dao.ObjectTypeQueue,
+		app.ID:       dao.ObjectTypeApplication,
+		node.ID:      dao.ObjectTypeNode,
+	}
+	for id, objectType := range tests {
+		req, err := createRequest(t, "/ws/v1/objects/"+id, map[string]string{"id": id})
+		assert.NilError(t, err, httpRequestError)
+		resp := &MockResponseWriter{}
+		getObjectByID(resp, req)
+		var objectDao dao.ObjectDAOInfo
+		err = json.Unmarshal(resp.outputBytes, &objectDao)
+		assert.NilError(t, err, unmarshalError)
+		assert.Equal(t, id, objectDao.ID)
+		assert.Equal(t, objectType, objectDao.Type)
+		assert.Equal(t, partitionNameWithoutClusterID, objectDao.Partition)
+		assert.Equal(t, partition.ID, objectDao.PartitionID)
+	}
+
+	// typed lookups
+	req, err := createRequest(t, "/ws/v1/queue/"+queue.ID, map[string]string{"id": queue.ID})
+	assert.NilError(t, err, httpRequestError)
+	resp := &MockResponseWriter{}
+	getQueueByID(resp, req)
+	var queueDao dao.PartitionQueueDAOInfo
+	err = json.Unmarshal(resp.outputBytes, &queueDao)
+	assert.NilError(t, err, unmarshalError)
+	assert.Equal(t, queueName, queueDao.QueueName)
+
+	req, err = createRequest(t, "/ws/v1/application/"+app.ID, map[string]string{"id": app.ID})
+	assert.NilError(t, err, httpRequestError)
+	resp = &MockResponseWriter{}
+	getApplicationByID(resp, req)
+	var appDao dao.ApplicationDAOInfo
+	err = json.Unmarshal(resp.outputBytes, &appDao)
+	assert.NilError(t, err, unmarshalError)
+	assert.Equal(t, "app-1", appDao.ApplicationID)
+
+	req, err = createRequest(t, "/ws/v1/node/"+node.ID, map[string]string{"id": node.ID})
+	assert.NilError(t, err, httpRequestError)
+	resp = &MockResponseWriter{}
+	getNodeByID(resp, req)
+	var nodeDao dao.NodeDAOInfo
+	err = json.Unmarshal(resp.outputBytes, &nodeDao)
+	assert.NilError(t, err, unmarshalError)
+	assert.Equal(t, nodeID, nodeDao.NodeID)
+
+	// type mismatch: a node ID is not a queue
+	req, err = createRequest(t, "/ws/v1/queue/"+node.ID, map[string]string{"id": node.ID})
+	assert.NilError(t, err, httpRequestError)
+	resp = &MockResponseWriter{}
+	getQueueByID(resp, req)
+	assertQueueNotExists(t, resp)
+
+	// invalid and unknown IDs
+	req, err = createRequest(t, "/ws/v1/objects/invalid", map[string]string{"id": "invalid"})
+	assert.NilError(t, err, httpRequestError)
+	resp = &MockResponseWriter{}
+	getObjectByID(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, statusCodeError)
+	unknown := "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+	req, err = createRequest(t, "/ws/v1/objects/"+unknown, map[string]string{"id": unknown})
+	assert.NilError(t, err, httpRequestError)
+	resp = &MockResponseWriter{}
+	getObjectByID(resp, req)
+	assert.Equal(t, http.StatusNotFound, resp.statusCode, statusCodeError)
+
+	// params missing
+	req, err = http.NewRequest("GET", "/ws/v1/objects/", strings.NewReader(""))
+	assert.NilError(t, err, httpRequestError)
+	resp = &MockResponseWriter{}
+	getObjectByID(resp, req)
+	assertParamsMissing(t, resp)
+}
+
+func TestGetApplicationExplain(t *testing.T) {
+	partition := setup(t, configDefault, 1)
+	app := addApp(t, "app-1", partition, queueName, false)
+	nodeRes := resources.NewResourceFromMap(map[string]resources.Quantity{siCommon.Memory: 1000}).ToProto()
+	err := partition.AddNode(objects.NewNode(&si.NodeInfo{NodeID: nodeID, SchedulableResource: nodeRes}))
+	assert.NilError(t, err, "add node to partition should not have failed")
+	ask := objects.NewAllocationFromSI(&si.Allocation{
+		AllocationKey:    "alloc-1",
+		ApplicationID:    "app-1",
+		PartitionName:    partition.Name,
+		ResourcePerAlloc: resources.NewResourceFromMap(map[string]resources.Quantity{siCommon.Memory: 2000}).ToProto(),
+	})
+	err = app.AddAllocationAsk(ask)
+	assert.NilError(t, err, "ask should have been added to app")
+	NewWebApp(schedulerContext.Load(), nil)
+
+	// ask is larger than the cluster
+	req, err := createRequest(t, "/ws/v1/partition/default/application/app-1/explain", map[string]string{"partition": partitionNameWithoutClusterID, "application": "app-1"})
+	assert.NilError(t, err, httpRequestError)
+	resp := &MockResponseWriter{}
+	getApplicationExplain(resp, req)
+	var explainDao dao.ApplicationExplainDAOInfo
+	err = json.Unmarshal(resp.outputBytes, &explainDao)
+	assert.NilError(t, err, unmarshalError)
+	assert.Equal(t, "app-1", explainDao.ApplicationID)
+	assert.Equal(t, app.ID, explainDao.ID)
+	assert.Equal(t, len(explainDao.PendingAsks), 1, "expected one pending ask")
+	assert.Equal(t, "alloc-1", explainDao.PendingAsks[0].AllocationKey)
+	assert.DeepEqual(t, explainDao.PendingAsks[0].Reasons, []*dao.BlockingReasonDAOInfo{
+		{Reason: dao.ReasonClusterCapacity, Queue: "root", ResourceTypes: []string{siCommon.Memory}},
+	})
+
+	// unknown application
+	req, err = createRequest(t, "/ws/v1/partition/default/application/unknown/explain", map[string]string{"partition": partitionNameWithoutClusterID, "application": "unknown"})
+	assert.NilError(t, err, httpRequestError)
+	resp = &MockResponseWriter{}
+	getApplicationExplain(resp, req)
+	assertApplicationNotExists(t, resp)
+
+	// unknown partition
+	req, err = createRequest(t, "/ws/v1/partition/unknown/application/app-1/explain", map[string]string{"partition": "unknown", "application": "app-1"})
+	assert.NilError(t, err, httpRequestError)
+	resp = &MockResponseWriter{}
+	getApplicationExplain(resp, req)
+	assertPartitionNotExists(t, resp)
+}
+
+func TestPlacementDryRun(t *testing.T) {
+	setup(t, configDefault, 1)
+	params := httprouter.Params{httprouter.Param{Key: "partition", Value: partitionNameWithoutClusterID}}
+	newDryRunRequest := func(body string, params httprouter.Params) *http.Request {
+		req, err := http.NewRequest("POST", "/ws/v1/partition/default/placementrules/dryrun", strings.NewReader(body))
+		assert.NilError(t, err, httpRequestError)
+		return req.WithContext(context.WithValue(req.Context(), httprouter.ParamsKey, params))
+	}
+
+	// placed by the implicit provided rule, nothing changes in the partition
+	resp := &MockResponseWriter{}
+	placementDryRun(resp, newDryRunRequest(`{"user": "testuser", "queue": "root.default"}`, params))
+	var result dao.PlacementDryRunDAOInfo
+	err := json.Unmarshal(resp.outputBytes, &result)
+	assert.NilError(t, err, unmarshalError)
+	assert.Equal(t, partitionNameWithoutClusterID, result.Partition)
+	assert.Equal(t, "root.default", result.Queue)
+	assert.Equal(t, "provided", result.Rule)
+	assert.Assert(t, result.QueueExists, "queue should exist")
+	assert.Equal(t, len(result.Rules), 1, "expected only the provided rule to be executed")
+
+	// no queue provided and not forced: falls back to the default queue
+	resp = &MockResponseWriter{}
+	placementDryRun(resp, newDryRunRequest(`{"user": "testuser"}`, params))
+	result = dao.PlacementDryRunDAOInfo{}
+	err = json.Unmarshal(resp.outputBytes, &result)
+	assert.NilError(t, err, unmarshalError)
+	assert.Equal(t, "root.default", result.Queue)
+	assert.Equal(t, "default queue", result.Rule)
+	assert.Equal(t, len(result.Rules), 3, "expected provided, recovery and default queue results")
+	assert.Equal(t, dao.PlacementSkipNoQueue, result.Rules[0].SkipReason)
+	assert.Equal(t, dao.PlacementSkipNotForced, result.Rules[1].SkipReason)
+	assert.Assert(t, result.Rules[2].Matched, "default queue should have matched")
+
+	// invalid requests
+	resp = &MockResponseWriter{}
+	placementDryRun(resp, newDryRunRequest(`{"queue": "root.default"}`, params))
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, statusCodeError)
+	resp = &MockResponseWriter{}
+	placementDryRun(resp, newDryRunRequest(`not json`, params))
+	assert.Equal(t, http.StatusBadRequest, resp.statusCode, statusCodeError)
+	resp = &MockResponseWriter{}
+	placementDryRun(resp, newDryRunRequest(`{"user": "testuser"}`, httprouter.Params{httprouter.Param{Key: "partition", Value: "unknown"}}))
+	assertPartitionNotExists(t, resp)
+}
+
 func TestGetPartitionRuleHandler(t *testing.T) {
 	setup(t, configDefault, 1)
 
@@ -3049,3 +3552,126 @@ func newForeignAlloc(allocationKey string, appID string, nodeID string, resAlloc
 		Priority: priority,
 	})
 }
+
+func TestNodeActions(t *testing.T) {
+	partition := setup(t, configACLs, 1)
+	nodeRes := resources.NewResourceFromMap(map[string]resources.Quantity{siCommon.Memory: 1000, siCommon.CPU: 1000})
+	node := addNode(t, partition, "node-1", nodeRes)
+	NewWebApp(schedulerContext.Load(), nil)
+	router := newRouter()
+	post := func(url, token, body string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("POST", url, strings.NewReader(body))
+		assert.NilError(t, err, httpRequestError)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	// cordon without authentication: unknown operator
+	resp := post("/ws/v1/partition/default/node/node-1/cordon", "", `{"reason":"maintenance"}`)
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var nodeDao dao.NodeDAOInfo
+	assert.NilError(t, json.Unmarshal(resp.Body.Bytes(), &nodeDao), unmarshalError)
+	assert.Assert(t, !nodeDao.Schedulable, "node should be cordoned")
+	assert.Equal(t, "anonymous", nodeDao.CordonedBy)
+	assert.Equal(t, "maintenance", nodeDao.CordonReason)
+
+	// uncordon with an empty body
+	resp = post("/ws/v1/partition/default/node/node-1/uncordon", "", "")
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Assert(t, node.IsSchedulable(), "node should be schedulable")
+	by, reason := node.GetCordonReason()
+	assert.Equal(t, "", by)
+	assert.Equal(t, "", reason)
+
+	// invalid requests
+	resp = post("/ws/v1/partition/default/node/unknown/cordon", "", "")
+	assert.Equal(t, http.StatusNotFound, resp.Code, statusCodeError)
+	resp = post("/ws/v1/partition/unknown/node/node-1/cordon", "", "")
+	assert.Equal(t, http.StatusNotFound, resp.Code, statusCodeError)
+	resp = post("/ws/v1/partition/default/node/node-1/cordon", "", "{")
+	assert.Equal(t, http.StatusBadRequest, resp.Code, statusCodeError)
+	resp = post("/ws/v1/partition/default/node/node-1/drain", "", `{"gracePeriodSeconds":-1}`)
+	assert.Equal(t, http.StatusBadRequest, resp.Code, statusCodeError)
+	var errInfo dao.YAPIError
+	assert.NilError(t, json.Unmarshal(resp.Body.Bytes(), &errInfo), unmarshalError)
+	assert.Equal(t, InvalidGracePeriod, errInfo.Message, jsonMessageError)
+	assert.Assert(t, node.IsSchedulable(), "failed drain should not cordon the node")
+
+	// only the partition admin can change the node when authentication is enabled
+	enableAuth(t, map[string]string{configs.CMRESTAuthTokenFile: writeTokenFile(t, testTokens)})
+	resp = post("/ws/v1/partition/default/node/node-1/drain", "alice-token", "")
+	assert.Equal(t, http.StatusForbidden, resp.Code, statusCodeError)
+	resp = post("/ws/v1/partition/default/node/node-1/drain", "admin-token", `{"reason":"upgrade","gracePeriodSeconds":3600}`)
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	by, reason = node.GetCordonReason()
+	assert.Equal(t, "admin", by)
+	assert.Equal(t, "upgrade", reason)
+	assert.Assert(t, !node.IsSchedulable(), "drained node should be cordoned")
+
+	// uncordon cancels the pending drain
+	resp = post("/ws/v1/partition/default/node/node-1/uncordon", "admin-token", "")
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Assert(t, node.IsSchedulable(), "node should be schedulable")
+}
+
+func TestApplicationActions(t *testing.T) {
+	partition := setup(t, configACLs, 1)
+	alice := security.UserGroup{User: "alice"}
+	addAppWithUserGroup(t, "app-1", partition, "root.a", false, alice)
+	app2 := addAppWithUserGroup(t, "app-2", partition, "root.a", false, alice)
+	app3 := addAppWithUserGroup(t, "app-3", partition, "root.a", false, alice)
+	NewWebApp(schedulerContext.Load(), nil)
+	router := newRouter()
+	post := func(url, token, body string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("POST", url, strings.NewReader(body))
+		assert.NilError(t, err, httpRequestError)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp
+	}
+
+	// move without authentication
+	resp := post("/ws/v1/partition/default/application/app-1/move", "", `{"queue":"root.b"}`)
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	var appDao dao.ApplicationDAOInfo
+	assert.NilError(t, json.Unmarshal(resp.Body.Bytes(), &appDao), unmarshalError)
+	assert.Equal(t, "root.b", appDao.QueueName)
+	assert.Equal(t, 1, len(partition.GetQueue("root.b").GetCopyOfApps()))
+
+	// invalid requests
+	resp = post("/ws/v1/partition/default/application/unknown/move", "", `{"queue":"root.a"}`)
+	assert.Equal(t, http.StatusNotFound, resp.Code, statusCodeError)
+	resp = post("/ws/v1/partition/unknown/application/app-1/fail", "", "")
+	assert.Equal(t, http.StatusNotFound, resp.Code, statusCodeError)
+	resp = post("/ws/v1/partition/default/application/app-1/fail", "", "{")
+	assert.Equal(t, http.StatusBadRequest, resp.Code, statusCodeError)
+	resp = post("/ws/v1/partition/default/application/app-1/move", "", "")
+	assert.Equal(t, http.StatusBadRequest, resp.Code, statusCodeError)
+	var errInfo dao.YAPIError
+	assert.NilError(t, json.Unmarshal(resp.Body.Bytes(), &errInfo), unmarshalError)
+	assert.Equal(t, MissingTargetQueue, errInfo.Message, jsonMessageError)
+	resp = post("/ws/v1/partition/default/application/app-1/move", "", `{"queue":"root"}`)
+	assert.Equal(t, http.StatusBadRequest, resp.Code, statusCodeError)
+	resp = post("/ws/v1/partition/default/application/app-1/move", "", `{"queue":"root.unknown"}`)
+	assert.Equal(t, http.StatusBadRequest, resp.Code, statusCodeError)
+
+	// only the queue admin can change the application when authentication is enabled
+	enableAuth(t, map[string]string{configs.CMRESTAuthTokenFile: writeTokenFile(t, testTokens)})
+	resp = post("/ws/v1/partition/default/application/app-2/fail", "alice-token", "")
+	assert.Equal(t, http.StatusForbidden, resp.Code, statusCodeError)
+	assert.Equal(t, objects.New.String(), app2.CurrentState())
+	resp = post("/ws/v1/partition/default/application/app-2/fail", "admin-token", `{"reason":"stuck"}`)
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Assert(t, app2.IsFailing() || app2.IsFailed(), "application should have failed")
+	resp = post("/ws/v1/partition/default/application/app-3/kill", "admin-token", "")
+	assert.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+	assert.Assert(t, app3.IsFailing() || app3.IsFailed(), "application should have been killed")
+	assert.Assert(t, partition.GetQueue("root.a").GetApplication("app-3") == nil, "killed application should be removed from the queue")
+}
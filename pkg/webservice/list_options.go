@@ -0,0 +1,253 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package webservice
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/G-Research/yunikorn-core/pkg/common/resources"
+	"github.com/G-Research/yunikorn-core/pkg/scheduler/objects"
+	"github.com/G-Research/yunikorn-core/pkg/webservice/dao"
+)
+
+const (
+	// TotalCountHeader is set on list responses to the number of items before pagination.
+	TotalCountHeader = "X-Total-Count"
+
+	sortName           = "name"
+	sortSubmissionTime = "submissionTime"
+	sortUsed           = "used"
+)
+
+var nodeSorters = listSorters[*objects.Node]{
+	sortName: func(node *objects.Node, _ string) listSortKey {
+		return nameKey(node.NodeID)
+	},
+	sortUsed: func(node *objects.Node, resourceName string) listSortKey {
+		return usedKey(node.GetAllocatedResource(), resourceName)
+	},
+}
+
+var appSorters = listSorters[*objects.Application]{
+	sortName: func(app *objects.Application, _ string) listSortKey {
+		return nameKey(app.ApplicationID)
+	},
+	sortSubmissionTime: func(app *objects.Application, _ string) listSortKey {
+		return listSortKey{number: app.SubmissionTime.UnixNano()}
+	},
+	sortUsed: func(app *objects.Application, resourceName string) listSortKey {
+		return usedKey(app.GetAllocatedResource(), resourceName)
+	},
+}
+
+var userSorters = listSorters[*dao.UserResourceUsageDAOInfo]{
+	sortName: func(user *dao.UserResourceUsageDAOInfo, _ string) listSortKey {
+		return nameKey(user.UserName)
+	},
+	sortUsed: func(user *dao.UserResourceUsageDAOInfo, resourceName string) listSortKey {
+		if user.Queues == nil {
+			return listSortKey{}
+		}
+		return usedKey(user.Queues.ResourceUsage, resourceName)
+	},
+}
+
+// listOptions the pagination, sorting and projection options of a list request:
+//   - limit: maximum number of items returned, 0 or not set returns all items
+//   - offset: number of items to skip
+//   - sort: field to sort on, prefixed with "-" for descending order. Sorting on the used resource needs the
+//     resource name, e.g. "used.memory". Items are sorted on name if not set, ties are sorted on name in the
+//     same order.
+//   - fields: comma separated list of the JSON fields returned per item, all fields if not set
+type listOptions struct {
+	limit        int
+	offset       int
+	sortField    string
+	resourceName string
+	descending   bool
+	fields       []string
+}
+
+// listSorters maps the supported sort fields to a function returning the sort key of an item. The resource name
+// is only set for the used resource field. The name field must always be supported.
+type listSorters[T any] map[string]func(item T, resourceName string) listSortKey
+
+// listSortKey the value an item is sorted on: numeric fields use the number, text fields the text.
+type listSortKey struct {
+	number int64
+	text   string
+}
+
+func (k listSortKey) compare(other listSortKey) int {
+	if result := cmp.Compare(k.number, other.number); result != 0 {
+		return result
+	}
+	return strings.Compare(k.text, other.text)
+}
+
+// parseListOptions reads the list options from the query, the sort field must be one of the sorters.
+func parseListOptions[T any](query url.Values, sorters listSorters[T]) (*listOptions, error) {
+	opts := &listOptions{
+		sortField: sortName,
+	}
+	var err error
+	if opts.limit, err = parseNonNegative(query, "limit"); err != nil {
+		return nil, err
+	}
+	if opts.offset, err = parseNonNegative(query, "offset"); err != nil {
+		return nil, err
+	}
+	if sortBy := query.Get("sort"); sortBy != "" {
+		sortBy, opts.descending = strings.CutPrefix(sortBy, "-")
+		field, resourceName, _ := strings.Cut(sortBy, ".")
+		if _, ok := sorters[field]; !ok {
+			return nil, fmt.Errorf("sort field %q is not supported, supported fields: %s", field, strings.Join(getSortFields(sorters), ", "))
+		}
+		if field == sortUsed && resourceName == "" {
+			return nil, fmt.Errorf("sort field %q requires a resource name: used.<resource>", sortUsed)
+		}
+		if field != sortUsed && resourceName != "" {
+			return nil, fmt.Errorf("sort field %q does not take a resource name", field)
+		}
+		opts.sortField = field
+		opts.resourceName = resourceName
+	}
+	opts.fields = getQueryValues(query, "fields")
+	return opts, nil
+}
+
+func parseNonNegative(query url.Values, key string) (int, error) {
+	value := query.Get(key)
+	if value == "" {
+		return 0, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("%q must be a non-negative number: %s", key, value)
+	}
+	return number, nil
+}
+
+func getSortFields[T any](sorters listSorters[T]) []string {
+	fields := make([]string, 0, len(sorters))
+	for field := range sorters {
+		if field == sortUsed {
+			field += ".<resource>"
+		}
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+	return fields
+}
+
+// pageList sorts the items in place, sets the total count header and returns the requested page.
+// The sort keys are read once per item: reading them could require locking the item.
+func pageList[T any](w http.ResponseWriter, items []T, opts *listOptions, sorters listSorters[T]) []T {
+	type keyedItem struct {
+		item T
+		key  listSortKey
+		name listSortKey
+	}
+	keyed := make([]keyedItem, len(items))
+	for i, item := range items {
+		keyed[i] = keyedItem{item: item, name: sorters[sortName](item, "")}
+		if opts.sortField != sortName {
+			keyed[i].key = sorters[opts.sortField](item, opts.resourceName)
+		}
+	}
+	slices.SortFunc(keyed, func(a, b keyedItem) int {
+		result := a.key.compare(b.key)
+		if result == 0 {
+			result = a.name.compare(b.name)
+		}
+		if opts.descending {
+			return -result
+		}
+		return result
+	})
+	for i := range keyed {
+		items[i] = keyed[i].item
+	}
+	w.Header().Set(TotalCountHeader, strconv.Itoa(len(items)))
+	start := min(opts.offset, len(items))
+	end := len(items)
+	if opts.limit > 0 {
+		end = min(start+opts.limit, end)
+	}
+	return items[start:end]
+}
+
+// writeList encodes the items limited to the requested fields.
+func writeList[T any](w http.ResponseWriter, items []T, opts *listOptions) {
+	var result interface{} = items
+	if len(opts.fields) > 0 {
+		projected, err := projectFields(items, opts.fields)
+		if err != nil {
+			buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		result = projected
+	}
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// projectFields converts the items to JSON objects with only the listed top level fields. Unknown fields and
+// fields omitted from the JSON of an item are left out.
+func projectFields[T any](items []T, fields []string) ([]map[string]json.RawMessage, error) {
+	projected := make([]map[string]json.RawMessage, 0, len(items))
+	for _, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		var all map[string]json.RawMessage
+		if err = json.Unmarshal(data, &all); err != nil {
+			return nil, err
+		}
+		object := make(map[string]json.RawMessage, len(fields))
+		for _, field := range fields {
+			if value, ok := all[field]; ok {
+				object[field] = value
+			}
+		}
+		projected = append(projected, object)
+	}
+	return projected, nil
+}
+
+func nameKey(name string) listSortKey {
+	return listSortKey{text: name}
+}
+
+// usedKey returns the quantity of the resource type as key, a nil resource or missing type counts as 0.
+func usedKey(res *resources.Resource, resourceName string) listSortKey {
+	if res == nil {
+		return listSortKey{}
+	}
+	return listSortKey{number: int64(res.Resources[resourceName])}
+}
@@ -0,0 +1,132 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package webservice
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gotest.tools/v3/assert"
+
+	"github.com/G-Research/yunikorn-core/pkg/common/resources"
+)
+
+type testItem struct {
+	Name string              `json:"name"`
+	Used *resources.Resource `json:"used,omitempty"`
+}
+
+var testSorters = listSorters[*testItem]{
+	sortName: func(item *testItem, _ string) listSortKey {
+		return nameKey(item.Name)
+	},
+	sortUsed: func(item *testItem, resourceName string) listSortKey {
+		return usedKey(item.Used, resourceName)
+	},
+}
+
+func TestParseListOptions(t *testing.T) {
+	tests := map[string]struct {
+		query    string
+		expected *listOptions
+		err      string
+	}{
+		"empty":          {query: "", expected: &listOptions{sortField: sortName}},
+		"paged":          {query: "limit=10&offset=20", expected: &listOptions{limit: 10, offset: 20, sortField: sortName}},
+		"name desc":      {query: "sort=-name", expected: &listOptions{sortField: sortName, descending: true}},
+		"used":           {query: "sort=used.nvidia.com/gpu", expected: &listOptions{sortField: sortUsed, resourceName: "nvidia.com/gpu"}},
+		"fields":         {query: "fields=name,used&fields=other", expected: &listOptions{sortField: sortName, fields: []string{"name", "used", "other"}}},
+		"negative limit": {query: "limit=-1", err: `"limit" must be a non-negative number: -1`},
+		"text offset":    {query: "offset=x", err: `"offset" must be a non-negative number: x`},
+		"unknown sort":   {query: "sort=submissionTime", err: `sort field "submissionTime" is not supported, supported fields: name, used.<resource>`},
+		"used no name":   {query: "sort=used", err: `sort field "used" requires a resource name: used.<resource>`},
+		"name resource":  {query: "sort=name.memory", err: `sort field "name" does not take a resource name`},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			query, err := url.ParseQuery(test.query)
+			assert.NilError(t, err)
+			opts, err := parseListOptions(query, testSorters)
+			if test.err != "" {
+				assert.Error(t, err, test.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, test.expected, opts, cmp.AllowUnexported(listOptions{}))
+		})
+	}
+}
+
+func TestPageList(t *testing.T) {
+	createItems := func() []*testItem {
+		return []*testItem{
+			{Name: "c", Used: resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 10})},
+			{Name: "a", Used: resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 30})},
+			{Name: "d"},
+			{Name: "b", Used: resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 10})},
+		}
+	}
+	tests := map[string]struct {
+		opts     *listOptions
+		expected []string
+	}{
+		"default":     {opts: &listOptions{sortField: sortName}, expected: []string{"a", "b", "c", "d"}},
+		"name desc":   {opts: &listOptions{sortField: sortName, descending: true}, expected: []string{"d", "c", "b", "a"}},
+		"used":        {opts: &listOptions{sortField: sortUsed, resourceName: "memory"}, expected: []string{"d", "b", "c", "a"}},
+		"used desc":   {opts: &listOptions{sortField: sortUsed, resourceName: "memory", descending: true}, expected: []string{"a", "c", "b", "d"}},
+		"first page":  {opts: &listOptions{sortField: sortName, limit: 3}, expected: []string{"a", "b", "c"}},
+		"second page": {opts: &listOptions{sortField: sortName, limit: 3, offset: 3}, expected: []string{"d"}},
+		"past end":    {opts: &listOptions{sortField: sortName, offset: 10}, expected: []string{}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := &MockResponseWriter{}
+			page := pageList(resp, createItems(), test.opts, testSorters)
+			names := make([]string, 0, len(page))
+			for _, item := range page {
+				names = append(names, item.Name)
+			}
+			assert.DeepEqual(t, test.expected, names)
+			assert.Equal(t, "4", resp.Header().Get(TotalCountHeader))
+		})
+	}
+}
+
+func TestWriteList(t *testing.T) {
+	items := []*testItem{
+		{Name: "a", Used: resources.NewResourceFromMap(map[string]resources.Quantity{"memory": 30})},
+		{Name: "b"},
+	}
+	resp := &MockResponseWriter{}
+	writeList(resp, items, &listOptions{fields: []string{"used", "unknown"}})
+	var projected []map[string]interface{}
+	assert.NilError(t, json.Unmarshal(resp.outputBytes, &projected))
+	assert.DeepEqual(t, []map[string]interface{}{
+		{"used": map[string]interface{}{"Resources": map[string]interface{}{"memory": float64(30)}}},
+		{},
+	}, projected)
+
+	resp = &MockResponseWriter{}
+	writeList(resp, items, &listOptions{})
+	var all []*testItem
+	assert.NilError(t, json.Unmarshal(resp.outputBytes, &all))
+	assert.DeepEqual(t, items, all)
+}
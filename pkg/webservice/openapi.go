@@ -0,0 +1,498 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package webservice
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/G-Research/yunikorn-core/pkg/common/configs"
+	"github.com/G-Research/yunikorn-core/pkg/log"
+	"github.com/G-Research/yunikorn-core/pkg/webservice/dao"
+)
+
+const (
+	contentJSON = "application/json"
+	contentYAML = "application/x-yaml"
+	contentText = "text/plain"
+
+	schemaRefPrefix = "#/components/schemas/"
+	systemRouteName = "System"
+)
+
+// openAPISpec the generated OpenAPI document served by the web app, regenerate it with "make openapi" after
+// changing a route or a DAO.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// apiOperation documents a route in the OpenAPI specification. The request and response are values of the
+// types that are encoded, nil if the route has no body.
+type apiOperation struct {
+	id          string
+	summary     string
+	request     interface{}
+	requestType string // content type of the request body, JSON if not set
+	response    interface{}
+	contentType string // content type of the response, JSON if not set
+	list        bool   // supports the list options
+	deprecated  bool
+	parameters  []apiParameter
+}
+
+type apiParameter struct {
+	name        string
+	in          string
+	description string
+	integer     bool
+}
+
+var (
+	subtreeParam     = apiParameter{name: "subtree", in: "query", description: "include the child queues"}
+	statusParam      = apiParameter{name: "status", in: "query", description: "application status for the active state"}
+	ifNoneMatchParam = apiParameter{name: "If-None-Match", in: "header", description: "ETag of a previous response"}
+	eventParams      = []apiParameter{
+		{name: "count", in: "query", description: "maximum number of events", integer: true},
+		{name: "start", in: "query", description: "ID of the first event", integer: true},
+		{name: "objectID", in: "query", description: "comma separated object IDs to filter on"},
+		{name: "referenceID", in: "query", description: "comma separated reference IDs to filter on"},
+		{name: "partition", in: "query", description: "comma separated partitions to filter on"},
+		{name: "type", in: "query", description: "comma separated event types to filter on"},
+		{name: "changeType", in: "query", description: "comma separated change types to filter on"},
+		{name: "changeDetail", in: "query", description: "comma separated change details to filter on"},
+	}
+	listParams = []apiParameter{
+		{name: "limit", in: "query", description: "maximum number of items, all items if not set", integer: true},
+		{name: "offset", in: "query", description: "number of items to skip", integer: true},
+		{name: "sort", in: "query", description: "field to sort on, prefixed with - for descending order"},
+		{name: "fields", in: "query", description: "comma separated list of the fields returned per item"},
+	}
+)
+
+// routeOperations documents the routes keyed on method and pattern. All routes, except the system routes, must be
+// documented.
+var routeOperations = map[string]apiOperation{
+	"GET /ws/v1/clusters": {id: "getClusterInfo", summary: "Cluster information",
+		response: []*dao.ClusterDAOInfo{}},
+	"GET /ws/v1/stack": {id: "getStackInfo", summary: "Stack traces of all goroutines",
+		contentType: contentText},
+	"GET /ws/v1/metrics": {id: "getMetrics", summary: "Prometheus metrics",
+		contentType: contentText},
+	"GET /ws/v1/config": {id: "getClusterConfig", summary: "Current configuration, JSON if requested in the Accept header",
+		response: &dao.ConfigDAOInfo{}, contentType: contentYAML},
+	"PUT /ws/v1/config": {id: "updateConfig", summary: "Replace the scheduler configuration",
+		request: &configs.SchedulerConfig{}, requestType: contentYAML, response: &dao.ConfigDAOInfo{},
+		parameters: []apiParameter{{name: "If-Match", in: "header", description: "checksum of the configuration the change is based on"}}},
+	"POST /ws/v1/validate-conf": {id: "validateConf", summary: "Validate a configuration and report the impact",
+		request: &configs.SchedulerConfig{}, requestType: contentYAML, response: &dao.ValidateConfResponse{}},
+	"GET /ws/v1/history/apps": {id: "getApplicationHistory", summary: "Application count history",
+		response: []*dao.ApplicationHistoryDAOInfo{}},
+	"GET /ws/v1/history/containers": {id: "getContainerHistory", summary: "Container count history",
+		response: []*dao.ContainerHistoryDAOInfo{}},
+	"GET /ws/v1/partitions": {id: "getPartitions", summary: "All partitions",
+		response: []*dao.PartitionInfo{}},
+	"GET /ws/v1/partition/:partition/placementrules": {id: "getPartitionRules", summary: "Placement rules of a partition",
+		response: []*dao.RuleDAO{}},
+	"POST /ws/v1/partition/:partition/placementrules/dryrun": {id: "placementDryRun", summary: "Run an application through the placement rules",
+		request: &dao.PlacementDryRunRequest{}, response: &dao.PlacementDryRunDAOInfo{}},
+	"GET /ws/v1/partition/:partition/queues": {id: "getPartitionQueues", summary: "Queue hierarchy of a partition",
+		response: &dao.PartitionQueueDAOInfo{}, parameters: []apiParameter{ifNoneMatchParam}},
+	"GET /ws/v1/partition/:partition/queue/:queue": {id: "getPartitionQueue", summary: "Queue of a partition",
+		response: &dao.PartitionQueueDAOInfo{}, parameters: []apiParameter{subtreeParam}},
+	"GET /ws/v1/partition/:partition/nodes": {id: "getPartitionNodes", summary: "Nodes of a partition",
+		response: []*dao.NodeDAOInfo{}, list: true},
+	"GET /ws/v1/partition/:partition/node/:node": {id: "getPartitionNode", summary: "Node of a partition",
+		response: &dao.NodeDAOInfo{}},
+	"POST /ws/v1/partition/:partition/node/:node/cordon": {id: "cordonNode", summary: "Stop placing new allocations on a node",
+		request: &dao.NodeActionRequest{}, response: &dao.NodeDAOInfo{}},
+	"POST /ws/v1/partition/:partition/node/:node/uncordon": {id: "uncordonNode", summary: "Allow new allocations on a node, cancels a pending drain",
+		request: &dao.NodeActionRequest{}, response: &dao.NodeDAOInfo{}},
+	"POST /ws/v1/partition/:partition/node/:node/drain": {id: "drainNode", summary: "Cordon a node and release its allocations after the grace period",
+		request: &dao.NodeActionRequest{}, response: &dao.NodeDAOInfo{}},
+	"GET /ws/v1/partition/:partition/queue/:queue/applications": {id: "getQueueApplications", summary: "Applications of a queue",
+		response: []*dao.ApplicationDAOInfo{}, list: true},
+	"GET /ws/v1/partition/:partition/queue/:queue/application/:application": {id: "getQueueApplication", summary: "Application of a queue",
+		response: &dao.ApplicationDAOInfo{}},
+	"GET /ws/v1/partition/:partition/application/:application": {id: "getApplication", summary: "Application of a partition",
+		response: &dao.ApplicationDAOInfo{}},
+	"GET /ws/v1/partition/:partition/application/:application/explain": {id: "getApplicationExplain", summary: "Why the pending asks of an application are not scheduled",
+		response: &dao.ApplicationExplainDAOInfo{}},
+	"POST /ws/v1/partition/:partition/application/:application/fail": {id: "failApplication", summary: "Fail an application, the RM releases its allocations",
+		request: &dao.ApplicationActionRequest{}, response: &dao.ApplicationDAOInfo{}},
+	"POST /ws/v1/partition/:partition/application/:application/kill": {id: "killApplication", summary: "Fail an application and remove its allocations immediately",
+		request: &dao.ApplicationActionRequest{}, response: &dao.ApplicationDAOInfo{}},
+	"POST /ws/v1/partition/:partition/application/:application/move": {id: "moveApplication", summary: "Move an application to another leaf queue",
+		request: &dao.ApplicationMoveRequest{}, response: &dao.ApplicationDAOInfo{}},
+	"GET /ws/v1/partition/:partition/applications/:state": {id: "getPartitionApplicationsByState", summary: "Applications of a partition in a state",
+		response: []*dao.ApplicationDAOInfo{}, list: true, parameters: []apiParameter{statusParam}},
+	"GET /ws/v1/partition/:partition/queue/:queue/applications/:state": {id: "getQueueApplicationsByState", summary: "Applications of a queue in a state",
+		response: []*dao.ApplicationDAOInfo{}, parameters: []apiParameter{statusParam}},
+	"GET /ws/v1/partition/:partition/usage/users": {id: "getUsersResourceUsage", summary: "Resource usage of all users",
+		response: []*dao.UserResourceUsageDAOInfo{}, list: true},
+	"GET /ws/v1/partition/:partition/usage/user/:user": {id: "getUserResourceUsage", summary: "Resource usage of a user",
+		response: &dao.UserResourceUsageDAOInfo{}},
+	"GET /ws/v1/partition/:partition/usage/groups": {id: "getGroupsResourceUsage", summary: "Resource usage of all groups",
+		response: []*dao.GroupResourceUsageDAOInfo{}},
+	"GET /ws/v1/partition/:partition/usage/group/:group": {id: "getGroupResourceUsage", summary: "Resource usage of a group",
+		response: &dao.GroupResourceUsageDAOInfo{}},
+	"GET /ws/v1/objects/:id": {id: "getObjectByID", summary: "Partition, queue, application or node by ID",
+		response: &dao.ObjectDAOInfo{}, parameters: []apiParameter{subtreeParam}},
+	"GET /ws/v1/queue/:id": {id: "getQueueByID", summary: "Queue by ID",
+		response: &dao.PartitionQueueDAOInfo{}, parameters: []apiParameter{subtreeParam}},
+	"GET /ws/v1/application/:id": {id: "getApplicationByID", summary: "Application by ID",
+		response: &dao.ApplicationDAOInfo{}},
+	"GET /ws/v1/node/:id": {id: "getNodeByID", summary: "Node by ID",
+		response: &dao.NodeDAOInfo{}},
+	"GET /ws/v1/fullstatedump": {id: "getFullStateDump", summary: "State of the scheduler",
+		response: &AggregatedStateInfo{}, parameters: []apiParameter{ifNoneMatchParam}},
+	"GET /ws/v1/events/batch": {id: "getEvents", summary: "Batch of events",
+		response: &dao.EventRecordDAO{}, parameters: eventParams},
+	"GET /ws/v1/events/stream": {id: "getStream", summary: "Stream of newline delimited events after the scheduler ID and an optional gap marker",
+		response: &dao.EventStreamRecordDAO{}, parameters: append(eventParams[:len(eventParams):len(eventParams)],
+			apiParameter{name: LastEventIDHeader, in: "header", description: "ID of the last event received", integer: true})},
+	"GET /ws/v1/scheduler/healthcheck": {id: "checkHealthStatus", summary: "Last health check result",
+		response: &dao.SchedulerHealthDAOInfo{}},
+	"GET /ws/v1/scheduler/node-utilization": {id: "getNodeUtilisation", summary: "Node utilisation of the dominant resource",
+		response: &dao.NodesUtilDAOInfo{}, deprecated: true},
+	"GET /ws/v1/scheduler/node-utilizations": {id: "getNodeUtilisations", summary: "Node utilisation of all resources",
+		response: []*dao.PartitionNodesUtilDAOInfo{}},
+	"GET /ws/v1/openapi.json": {id: "getOpenAPISpec", summary: "OpenAPI specification of the REST API",
+		response: map[string]interface{}{}},
+}
+
+// getOpenAPISpec serves the generated specification.
+func getOpenAPISpec(w http.ResponseWriter, _ *http.Request) {
+	writeHeaders(w)
+	if _, err := w.Write(openAPISpec); err != nil {
+		log.Log(log.REST).Error("GetOpenAPISpec error", zap.Error(err))
+	}
+}
+
+// apiSchema a subset of the OpenAPI schema object.
+type apiSchema struct {
+	Ref                  string                `json:"$ref,omitempty"`
+	Type                 string                `json:"type,omitempty"`
+	Format               string                `json:"format,omitempty"`
+	Items                *apiSchema            `json:"items,omitempty"`
+	Properties           map[string]*apiSchema `json:"properties,omitempty"`
+	AdditionalProperties *apiSchema            `json:"additionalProperties,omitempty"`
+}
+
+// buildOpenAPISpec generates the OpenAPI document from the routes and the types listed in routeOperations.
+func buildOpenAPISpec() ([]byte, error) {
+	sb := &schemaBuilder{schemas: make(map[string]*apiSchema), types: make(map[string]reflect.Type)}
+	errorSchema, err := sb.schemaOf(reflect.TypeOf(dao.YAPIError{}))
+	if err != nil {
+		return nil, err
+	}
+	paths := make(map[string]map[string]interface{})
+	documented := make(map[string]bool)
+	for _, webRoute := range webRoutes {
+		if webRoute.Name == systemRouteName {
+			continue
+		}
+		key := routeKey(webRoute)
+		op, ok := routeOperations[key]
+		if !ok {
+			return nil, fmt.Errorf("route %s is not documented", key)
+		}
+		documented[key] = true
+		operation, err := sb.buildOperation(webRoute, op, errorSchema)
+		if err != nil {
+			return nil, fmt.Errorf("route %s: %w", key, err)
+		}
+		path := getOpenAPIPath(webRoute.Pattern)
+		if paths[path] == nil {
+			paths[path] = make(map[string]interface{})
+		}
+		paths[path][strings.ToLower(webRoute.Method)] = operation
+	}
+	for key := range routeOperations {
+		if !documented[key] {
+			return nil, fmt.Errorf("documented route %s does not exist", key)
+		}
+	}
+	spec := map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]string{
+			"title":   "YuniKorn scheduler REST API",
+			"version": "v1",
+		},
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": sb.schemas},
+	}
+	data, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+func (sb *schemaBuilder) buildOperation(webRoute route, op apiOperation, errorSchema *apiSchema) (map[string]interface{}, error) {
+	operation := map[string]interface{}{
+		"operationId": op.id,
+		"summary":     op.summary,
+		"tags":        []string{webRoute.Name},
+	}
+	if op.deprecated {
+		operation["deprecated"] = true
+	}
+	var parameters []map[string]interface{}
+	for _, segment := range strings.Split(webRoute.Pattern, "/") {
+		if name, ok := strings.CutPrefix(segment, ":"); ok {
+			parameters = append(parameters, map[string]interface{}{
+				"name":     name,
+				"in":       "path",
+				"required": true,
+				"schema":   &apiSchema{Type: "string"},
+			})
+		}
+	}
+	params := op.parameters
+	if op.list {
+		params = append(listParams[:len(listParams):len(listParams)], params...)
+	}
+	for _, param := range params {
+		schema := &apiSchema{Type: "string"}
+		if param.integer {
+			schema = &apiSchema{Type: "integer", Format: "int64"}
+		}
+		parameters = append(parameters, map[string]interface{}{
+			"name":        param.name,
+			"in":          param.in,
+			"description": param.description,
+			"schema":      schema,
+		})
+	}
+	if len(parameters) > 0 {
+		operation["parameters"] = parameters
+	}
+	if op.request != nil {
+		schema, err := sb.schemaOf(reflect.TypeOf(op.request))
+		if err != nil {
+			return nil, err
+		}
+		operation["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content":  getContent(op.requestType, schema),
+		}
+	}
+	response := map[string]interface{}{"description": "OK"}
+	if op.response != nil {
+		schema, err := sb.schemaOf(reflect.TypeOf(op.response))
+		if err != nil {
+			return nil, err
+		}
+		// the configuration is returned as JSON if requested
+		if op.contentType == contentYAML {
+			response["content"] = getContent(contentYAML, schema, contentJSON)
+		} else {
+			response["content"] = getContent(op.contentType, schema)
+		}
+	} else {
+		response["content"] = getContent(op.contentType, &apiSchema{Type: "string"})
+	}
+	if op.list {
+		response["headers"] = map[string]interface{}{
+			TotalCountHeader: map[string]interface{}{
+				"description": "number of items before pagination",
+				"schema":      &apiSchema{Type: "integer"},
+			},
+		}
+	}
+	operation["responses"] = map[string]interface{}{
+		"200": response,
+		"default": map[string]interface{}{
+			"description": "Error",
+			"content":     getContent(contentJSON, errorSchema),
+		},
+	}
+	return operation, nil
+}
+
+// getContent returns the content map for the schema, JSON if the content type is not set.
+func getContent(contentType string, schema *apiSchema, extra ...string) map[string]interface{} {
+	if contentType == "" {
+		contentType = contentJSON
+	}
+	content := map[string]interface{}{contentType: map[string]interface{}{"schema": schema}}
+	for _, other := range extra {
+		content[other] = map[string]interface{}{"schema": schema}
+	}
+	return content
+}
+
+// getOpenAPIPath converts the router parameters to OpenAPI path parameters: "/queue/:queue" to "/queue/{queue}".
+func getOpenAPIPath(pattern string) string {
+	segments := strings.Split(pattern, "/")
+	for i, segment := range segments {
+		if name, ok := strings.CutPrefix(segment, ":"); ok {
+			segments[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// schemaBuilder converts Go types to schemas following the encoding/json rules. Structs are added as named
+// component schemas.
+type schemaBuilder struct {
+	schemas map[string]*apiSchema
+	types   map[string]reflect.Type
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+	marshalerType  = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	daoPkgPath     = reflect.TypeOf(dao.YAPIError{}).PkgPath()
+)
+
+func (sb *schemaBuilder) schemaOf(t reflect.Type) (*apiSchema, error) {
+	switch {
+	case t == timeType:
+		return &apiSchema{Type: "string", Format: "date-time"}, nil
+	case t == rawMessageType:
+		return &apiSchema{}, nil
+	case t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType):
+		return nil, fmt.Errorf("type %s has a custom JSON encoding", t)
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &apiSchema{Type: "boolean"}, nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &apiSchema{Type: "integer", Format: "int32"}, nil
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &apiSchema{Type: "integer", Format: "int64"}, nil
+	case reflect.Float32:
+		return &apiSchema{Type: "number", Format: "float"}, nil
+	case reflect.Float64:
+		return &apiSchema{Type: "number", Format: "double"}, nil
+	case reflect.String:
+		return &apiSchema{Type: "string"}, nil
+	case reflect.Interface:
+		return &apiSchema{}, nil
+	case reflect.Pointer:
+		return sb.schemaOf(t.Elem())
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &apiSchema{Type: "string", Format: "byte"}, nil
+		}
+		items, err := sb.schemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &apiSchema{Type: "array", Items: items}, nil
+	case reflect.Map:
+		values, err := sb.schemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &apiSchema{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Struct:
+		return sb.structRef(t)
+	default:
+		return nil, fmt.Errorf("type %s cannot be encoded as JSON", t)
+	}
+}
+
+// structRef returns a reference to the component schema of the struct, adding it if needed. Structs outside the
+// dao package are named after their package, configs and dao use the same type names.
+func (sb *schemaBuilder) structRef(t reflect.Type) (*apiSchema, error) {
+	name := t.Name()
+	if name == "" {
+		return nil, fmt.Errorf("anonymous struct %s is not supported", t)
+	}
+	if t.PkgPath() != daoPkgPath {
+		name = path.Base(t.PkgPath()) + "." + name
+	}
+	if existing, ok := sb.types[name]; ok {
+		if existing != t {
+			return nil, fmt.Errorf("schema name %s is used by %s and %s", name, existing, t)
+		}
+		return &apiSchema{Ref: schemaRefPrefix + name}, nil
+	}
+	// register before adding the fields: types can be recursive
+	schema := &apiSchema{Type: "object", Properties: make(map[string]*apiSchema)}
+	sb.types[name] = t
+	sb.schemas[name] = schema
+	if err := sb.addFields(schema, t); err != nil {
+		return nil, err
+	}
+	return &apiSchema{Ref: schemaRefPrefix + name}, nil
+}
+
+// addFields adds the encoded fields of the struct to the schema. Fields of embedded structs without a JSON name
+// are promoted, unless the outer struct has a field with the same name.
+func (sb *schemaBuilder) addFields(schema *apiSchema, t reflect.Type) error {
+	var embedded []reflect.Type
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				embedded = append(embedded, ft)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		var fieldSchema *apiSchema
+		if strings.Contains(opts, "string") {
+			fieldSchema = &apiSchema{Type: "string"}
+		} else {
+			var err error
+			if fieldSchema, err = sb.schemaOf(field.Type); err != nil {
+				return fmt.Errorf("field %s.%s: %w", t.Name(), field.Name, err)
+			}
+		}
+		schema.Properties[name] = fieldSchema
+	}
+	for _, et := range embedded {
+		promoted := &apiSchema{Properties: make(map[string]*apiSchema)}
+		if err := sb.addFields(promoted, et); err != nil {
+			return err
+		}
+		for name, fieldSchema := range promoted.Properties {
+			if _, ok := schema.Properties[name]; !ok {
+				schema.Properties[name] = fieldSchema
+			}
+		}
+	}
+	return nil
+}
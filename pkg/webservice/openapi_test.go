@@ -0,0 +1,117 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package webservice
+
+import (
+	"encoding/json"
+	"flag"
+	"net/http"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+// updateOpenAPI returns true if the tests run with the -update flag, registered by gotest.tools.
+func updateOpenAPI() bool {
+	update := flag.Lookup("update")
+	return update != nil && update.Value.String() == "true"
+}
+
+// TestOpenAPISpec fails when a route or a DAO changed without regenerating the specification.
+func TestOpenAPISpec(t *testing.T) {
+	generated, err := buildOpenAPISpec()
+	assert.NilError(t, err)
+	if updateOpenAPI() {
+		assert.NilError(t, os.WriteFile("openapi.json", generated, 0o644)) //nolint:gosec // generated source file
+		return
+	}
+	assert.Equal(t, string(openAPISpec), string(generated),
+		"openapi.json is out of date, regenerate it with: make openapi")
+}
+
+func TestBuildOpenAPISpecRoutes(t *testing.T) {
+	original := webRoutes
+	defer func() {
+		webRoutes = original
+	}()
+	webRoutes = append(original[:len(original):len(original)], route{"Scheduler", "GET", "/ws/v1/undocumented", getOpenAPISpec})
+	_, err := buildOpenAPISpec()
+	assert.Error(t, err, "route GET /ws/v1/undocumented is not documented")
+
+	webRoutes = original[1:]
+	_, err = buildOpenAPISpec()
+	assert.Error(t, err, "documented route GET /ws/v1/clusters does not exist")
+}
+
+func TestSchemaOf(t *testing.T) {
+	type inner struct {
+		Name  string `json:"name"`
+		Count int
+	}
+	type tree struct {
+		*inner
+		Name     int               `json:"id"`
+		Children []tree            `json:"children,omitempty"`
+		Labels   map[string]string `json:"labels"`
+		Created  time.Time         `json:"created"`
+		Any      interface{}       `json:"any"`
+		Quoted   int64             `json:"quoted,string"`
+		Ignored  string            `json:"-"`
+		hidden   string
+	}
+	sb := &schemaBuilder{schemas: make(map[string]*apiSchema), types: make(map[string]reflect.Type)}
+	ref, err := sb.schemaOf(reflect.TypeOf([]*tree{}))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, &apiSchema{Type: "array", Items: &apiSchema{Ref: "#/components/schemas/webservice.tree"}}, ref)
+	assert.DeepEqual(t, &apiSchema{Type: "object", Properties: map[string]*apiSchema{
+		"id":       {Type: "integer", Format: "int64"},
+		"name":     {Type: "string"},
+		"Count":    {Type: "integer", Format: "int64"},
+		"children": {Type: "array", Items: &apiSchema{Ref: "#/components/schemas/webservice.tree"}},
+		"labels":   {Type: "object", AdditionalProperties: &apiSchema{Type: "string"}},
+		"created":  {Type: "string", Format: "date-time"},
+		"any":      {},
+		"quoted":   {Type: "string"},
+	}}, sb.schemas["webservice.tree"])
+	assert.Equal(t, 1, len(sb.schemas), "embedded struct should not be a separate schema")
+
+	_, err = sb.schemaOf(reflect.TypeOf(make(chan int)))
+	assert.Error(t, err, "type chan int cannot be encoded as JSON")
+	_, err = sb.schemaOf(reflect.TypeOf(json.Number("")))
+	assert.NilError(t, err)
+	_, err = sb.schemaOf(reflect.TypeOf(struct{ A int }{}))
+	assert.ErrorContains(t, err, "anonymous struct")
+}
+
+func TestGetOpenAPISpec(t *testing.T) {
+	req, err := http.NewRequest("GET", "/ws/v1/openapi.json", nil)
+	assert.NilError(t, err)
+	resp := &MockResponseWriter{}
+	getOpenAPISpec(resp, req)
+	assert.Equal(t, 0, resp.statusCode)
+	var spec map[string]interface{}
+	assert.NilError(t, json.Unmarshal(resp.outputBytes, &spec))
+	assert.Equal(t, "3.0.3", spec["openapi"])
+	paths, ok := spec["paths"].(map[string]interface{})
+	assert.Assert(t, ok, "paths missing from the specification")
+	assert.Assert(t, paths["/ws/v1/partition/{partition}/queue/{queue}"] != nil, "queue path missing from the specification")
+}
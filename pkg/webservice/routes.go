@@ -65,6 +65,14 @@ var webRoutes = routes{
 		getClusterConfig,
 	},
 
+	// endpoint to update the current conf
+	route{
+		"Scheduler",
+		"PUT",
+		"/ws/v1/config",
+		updateConfig,
+	},
+
 	// endpoint to validate conf
 	route{
 		"Scheduler",
@@ -98,6 +106,12 @@ var webRoutes = routes{
 		"/ws/v1/partition/:partition/placementrules",
 		getPartitionRules,
 	},
+	route{
+		"Scheduler",
+		"POST",
+		"/ws/v1/partition/:partition/placementrules/dryrun",
+		placementDryRun,
+	},
 	route{
 		"Scheduler",
 		"GET",
@@ -122,6 +136,25 @@ var webRoutes = routes{
 		"/ws/v1/partition/:partition/node/:node",
 		getPartitionNode,
 	},
+	// endpoints to change the schedulable state of a node
+	route{
+		"Scheduler",
+		"POST",
+		"/ws/v1/partition/:partition/node/:node/cordon",
+		cordonNode,
+	},
+	route{
+		"Scheduler",
+		"POST",
+		"/ws/v1/partition/:partition/node/:node/uncordon",
+		uncordonNode,
+	},
+	route{
+		"Scheduler",
+		"POST",
+		"/ws/v1/partition/:partition/node/:node/drain",
+		drainNode,
+	},
 	route{
 		"Scheduler",
 		"GET",
@@ -140,6 +173,30 @@ var webRoutes = routes{
 		"/ws/v1/partition/:partition/application/:application",
 		getApplication,
 	},
+	route{
+		"Scheduler",
+		"GET",
+		"/ws/v1/partition/:partition/application/:application/explain",
+		getApplicationExplain,
+	},
+	route{
+		"Scheduler",
+		"POST",
+		"/ws/v1/partition/:partition/application/:application/fail",
+		failApplication,
+	},
+	route{
+		"Scheduler",
+		"POST",
+		"/ws/v1/partition/:partition/application/:application/kill",
+		killApplication,
+	},
+	route{
+		"Scheduler",
+		"POST",
+		"/ws/v1/partition/:partition/application/:application/move",
+		moveApplication,
+	},
 	route{
 		"Scheduler",
 		"GET",
@@ -176,6 +233,30 @@ var webRoutes = routes{
 		"/ws/v1/partition/:partition/usage/group/:group",
 		getGroupResourceUsage,
 	},
+	route{
+		"Scheduler",
+		"GET",
+		"/ws/v1/objects/:id",
+		getObjectByID,
+	},
+	route{
+		"Scheduler",
+		"GET",
+		"/ws/v1/queue/:id",
+		getQueueByID,
+	},
+	route{
+		"Scheduler",
+		"GET",
+		"/ws/v1/application/:id",
+		getApplicationByID,
+	},
+	route{
+		"Scheduler",
+		"GET",
+		"/ws/v1/node/:id",
+		getNodeByID,
+	},
 	route{
 		"Scheduler",
 		"GET",
@@ -194,6 +275,13 @@ var webRoutes = routes{
 		"/ws/v1/events/stream",
 		getStream,
 	},
+	// endpoint to retrieve the OpenAPI specification of the REST API
+	route{
+		"Scheduler",
+		"GET",
+		"/ws/v1/openapi.json",
+		getOpenAPISpec,
+	},
 	// endpoint to retrieve CPU, Memory profiling data,
 	// this works with pprof tool. By default, pprof endpoints
 	// are only registered to http.DefaultServeMux. Here, we
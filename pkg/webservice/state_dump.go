@@ -55,6 +55,9 @@ type AggregatedStateInfo struct {
 
 func getFullStateDump(w http.ResponseWriter, r *http.Request) {
 	writeHeaders(w)
+	if checkNotModified(w, r, getStateDumpETagParts(schedulerContext.Load().GetPartitionMapClone())...) {
+		return
+	}
 	if err := doStateDump(w); err != nil {
 		buildJSONErrorResponse(w, err.Error(), http.StatusInternalServerError)
 	}
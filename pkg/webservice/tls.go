@@ -0,0 +1,164 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package webservice
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/G-Research/yunikorn-core/pkg/common"
+	"github.com/G-Research/yunikorn-core/pkg/common/configs"
+	"github.com/G-Research/yunikorn-core/pkg/locking"
+	"github.com/G-Research/yunikorn-core/pkg/log"
+)
+
+// certificateCheckInterval is the minimum time between checks of the certificate files for changes
+var certificateCheckInterval = 10 * time.Second
+
+// tlsReloader serves the TLS configuration of the webservice and reloads the certificates when the files change.
+// The files are checked during a handshake, at most once per certificateCheckInterval. A failed reload keeps the
+// current certificates.
+type tlsReloader struct {
+	certFile          string
+	keyFile           string
+	clientCAFile      string
+	requireClientCert bool
+
+	config    *tls.Config // configuration returned for new connections
+	modTimes  []time.Time // modification times of the loaded files
+	lastCheck time.Time   // last time the files were checked
+
+	locking.Mutex
+}
+
+// newTLSConfig returns the TLS configuration of the webservice from the configuration, nil if TLS is not
+// configured. The certificates are loaded before returning.
+func newTLSConfig() (*tls.Config, error) {
+	configMap := configs.GetConfigMap()
+	certFile := configMap[configs.CMRESTTLSCertFile]
+	keyFile := configMap[configs.CMRESTTLSKeyFile]
+	if certFile == "" && keyFile == "" {
+		return nil, nil
+	}
+	if certFile == "" || keyFile == "" {
+		return nil, fmt.Errorf("both %s and %s must be set", configs.CMRESTTLSCertFile, configs.CMRESTTLSKeyFile)
+	}
+	tr := &tlsReloader{
+		certFile:          certFile,
+		keyFile:           keyFile,
+		clientCAFile:      configMap[configs.CMRESTTLSClientCAFile],
+		requireClientCert: common.GetConfigurationBool(configMap, configs.CMRESTTLSRequireClientCert, configs.DefaultRESTTLSRequireClientCert),
+	}
+	if tr.requireClientCert && tr.clientCAFile == "" {
+		return nil, fmt.Errorf("%s requires %s to be set", configs.CMRESTTLSRequireClientCert, configs.CMRESTTLSClientCAFile)
+	}
+	if err := tr.load(); err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		GetConfigForClient: tr.getConfigForClient,
+	}, nil
+}
+
+// getConfigForClient returns the current configuration, reloading the files if they changed.
+func (tr *tlsReloader) getConfigForClient(_ *tls.ClientHelloInfo) (*tls.Config, error) {
+	tr.Lock()
+	defer tr.Unlock()
+	if time.Since(tr.lastCheck) >= certificateCheckInterval {
+		tr.lastCheck = time.Now()
+		if modTimes, err := tr.getModTimes(); err != nil {
+			log.Log(log.REST).Warn("Failed to check the REST API certificate files", zap.Error(err))
+		} else if !slices.EqualFunc(modTimes, tr.modTimes, time.Time.Equal) {
+			if err = tr.loadLocked(); err != nil {
+				log.Log(log.REST).Error("Failed to reload the REST API certificates, keeping the current certificates",
+					zap.Error(err))
+			} else {
+				log.Log(log.REST).Info("Reloaded the REST API certificates")
+			}
+		}
+	}
+	return tr.config, nil
+}
+
+func (tr *tlsReloader) load() error {
+	tr.Lock()
+	defer tr.Unlock()
+	tr.lastCheck = time.Now()
+	return tr.loadLocked()
+}
+
+// loadLocked reads the certificate, key and client CA files.
+// Lock free call, must be called holding the reloader lock.
+func (tr *tlsReloader) loadLocked() error {
+	// the modification times are read first: a change during the load is picked up by the next check
+	modTimes, err := tr.getModTimes()
+	if err != nil {
+		return err
+	}
+	cert, err := tls.LoadX509KeyPair(tr.certFile, tr.keyFile)
+	if err != nil {
+		return err
+	}
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+	if tr.clientCAFile != "" {
+		var pem []byte
+		pem, err = os.ReadFile(tr.clientCAFile)
+		if err != nil {
+			return err
+		}
+		config.ClientCAs = x509.NewCertPool()
+		if !config.ClientCAs.AppendCertsFromPEM(pem) {
+			return errors.New("no certificates found in the client CA file")
+		}
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+		if tr.requireClientCert {
+			config.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+	tr.config = config
+	tr.modTimes = modTimes
+	return nil
+}
+
+func (tr *tlsReloader) getModTimes() ([]time.Time, error) {
+	files := []string{tr.certFile, tr.keyFile}
+	if tr.clientCAFile != "" {
+		files = append(files, tr.clientCAFile)
+	}
+	modTimes := make([]time.Time, len(files))
+	for i, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		modTimes[i] = info.ModTime()
+	}
+	return modTimes, nil
+}
@@ -0,0 +1,251 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package webservice
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/G-Research/yunikorn-core/pkg/common/configs"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NilError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NilError(t, err)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns the PEM encoded certificate and key signed by the CA.
+func (ca *testCA) issue(t *testing.T, serial int64, subject pkix.Name, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      subject,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	assert.NilError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NilError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+func writeFile(t *testing.T, path string, content []byte, modTime time.Time) {
+	assert.NilError(t, os.WriteFile(path, content, 0o600))
+	assert.NilError(t, os.Chtimes(path, modTime, modTime))
+}
+
+// startTLSServer serves the router with the TLS configuration and returns the address.
+func startTLSServer(t *testing.T, config *tls.Config) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	server := &http.Server{Handler: newRouter(), ReadHeaderTimeout: time.Second}
+	go func() {
+		_ = server.Serve(tls.NewListener(listener, config))
+	}()
+	t.Cleanup(func() {
+		_ = server.Close()
+	})
+	return "https://" + listener.Addr().String()
+}
+
+// tlsGet sends a GET request on a new connection and returns the response and the server certificate serial.
+func tlsGet(url string, ca *testCA, clientCert *tls.Certificate) (*http.Response, int64, error) {
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	config := &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}
+	if clientCert != nil {
+		config.Certificates = []tls.Certificate{*clientCert}
+	}
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: config, DisableKeepAlives: true}}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, 0, err
+	}
+	_ = resp.Body.Close()
+	return resp, resp.TLS.PeerCertificates[0].SerialNumber.Int64(), nil
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	original := configs.GetConfigMap()
+	defer configs.SetConfigMap(original)
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+
+	configs.SetConfigMap(map[string]string{})
+	config, err := newTLSConfig()
+	assert.NilError(t, err)
+	assert.Assert(t, config == nil, "TLS should not be configured")
+
+	configs.SetConfigMap(map[string]string{configs.CMRESTTLSCertFile: certFile})
+	_, err = newTLSConfig()
+	assert.Error(t, err, "both rest.tls.certFile and rest.tls.keyFile must be set")
+
+	configs.SetConfigMap(map[string]string{
+		configs.CMRESTTLSCertFile:          certFile,
+		configs.CMRESTTLSKeyFile:           keyFile,
+		configs.CMRESTTLSRequireClientCert: "true",
+	})
+	_, err = newTLSConfig()
+	assert.Error(t, err, "rest.tls.requireClientCert requires rest.tls.clientCAFile to be set")
+
+	configs.SetConfigMap(map[string]string{
+		configs.CMRESTTLSCertFile: certFile,
+		configs.CMRESTTLSKeyFile:  keyFile,
+	})
+	_, err = newTLSConfig()
+	assert.Assert(t, os.IsNotExist(err), "expected a missing file error")
+}
+
+func TestTLSReload(t *testing.T) {
+	setup(t, configDefault, 1)
+	interval := certificateCheckInterval
+	certificateCheckInterval = 0
+	original := configs.GetConfigMap()
+	defer func() {
+		certificateCheckInterval = interval
+		configs.SetConfigMap(original)
+	}()
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	modTime := time.Now().Add(-time.Minute)
+	certPEM, keyPEM := ca.issue(t, 10, pkix.Name{CommonName: "server"}, x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, certPEM, modTime)
+	writeFile(t, keyFile, keyPEM, modTime)
+
+	configs.SetConfigMap(map[string]string{
+		configs.CMRESTTLSCertFile: certFile,
+		configs.CMRESTTLSKeyFile:  keyFile,
+	})
+	config, err := newTLSConfig()
+	assert.NilError(t, err)
+	url := startTLSServer(t, config) + "/ws/v1/clusters"
+	resp, serial, err := tlsGet(url, ca, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int64(10), serial)
+
+	// a broken key keeps the current certificate
+	writeFile(t, keyFile, []byte("broken"), modTime.Add(time.Second))
+	_, serial, err = tlsGet(url, ca, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, int64(10), serial)
+
+	// rotated certificate is used for new connections
+	certPEM, keyPEM = ca.issue(t, 11, pkix.Name{CommonName: "server"}, x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, certPEM, modTime.Add(2*time.Second))
+	writeFile(t, keyFile, keyPEM, modTime.Add(2*time.Second))
+	_, serial, err = tlsGet(url, ca, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, int64(11), serial)
+}
+
+func TestTLSClientCert(t *testing.T) {
+	setup(t, configDefault, 1)
+	original := configs.GetConfigMap()
+	defer configs.SetConfigMap(original)
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	caFile := filepath.Join(dir, "ca.crt")
+	certPEM, keyPEM := ca.issue(t, 10, pkix.Name{CommonName: "server"}, x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, certPEM, time.Now())
+	writeFile(t, keyFile, keyPEM, time.Now())
+	writeFile(t, caFile, ca.pem, time.Now())
+	clientPEM, clientKeyPEM := ca.issue(t, 20, pkix.Name{CommonName: "alice", Organization: []string{"devs"}}, x509.ExtKeyUsageClientAuth)
+	clientCert, err := tls.X509KeyPair(clientPEM, clientKeyPEM)
+	assert.NilError(t, err)
+
+	// optional client certificate used for authentication
+	configs.SetConfigMap(map[string]string{
+		configs.CMRESTTLSCertFile:     certFile,
+		configs.CMRESTTLSKeyFile:      keyFile,
+		configs.CMRESTTLSClientCAFile: caFile,
+		configs.CMRESTAuthEnabled:     "true",
+		configs.CMRESTAuthClientCert:  "true",
+	})
+	config, err := newTLSConfig()
+	assert.NilError(t, err)
+	url := startTLSServer(t, config) + "/ws/v1/clusters"
+	resp, _, err := tlsGet(url, ca, &clientCert)
+	assert.NilError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp, _, err = tlsGet(url, ca, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	// required client certificate fails the handshake
+	configs.SetConfigMap(map[string]string{
+		configs.CMRESTTLSCertFile:          certFile,
+		configs.CMRESTTLSKeyFile:           keyFile,
+		configs.CMRESTTLSClientCAFile:      caFile,
+		configs.CMRESTTLSRequireClientCert: "true",
+	})
+	config, err = newTLSConfig()
+	assert.NilError(t, err)
+	url = startTLSServer(t, config) + "/ws/v1/clusters"
+	_, _, err = tlsGet(url, ca, nil)
+	assert.Assert(t, err != nil, "request without client certificate should have failed")
+	resp, _, err = tlsGet(url, ca, &clientCert)
+	assert.NilError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
@@ -44,7 +44,7 @@ type WebService struct {
 func newRouter() *httprouter.Router {
 	router := httprouter.New()
 	for _, webRoute := range webRoutes {
-		handler := loggingHandler(webRoute.HandlerFunc, webRoute.Name)
+		handler := loggingHandler(authHandler(webRoute), webRoute.Name)
 		router.Handler(webRoute.Method, webRoute.Pattern, handler)
 	}
 	return router
@@ -62,18 +62,33 @@ func loggingHandler(inner http.Handler, name string) http.HandlerFunc {
 	}
 }
 
-// StartWebApp starts the web app on the default port.
+// StartWebApp starts the web app on the default port. The web app serves HTTPS if a certificate is configured,
+// it is not started if the certificate cannot be loaded.
 func (m *WebService) StartWebApp() {
+	tlsConfig, err := newTLSConfig()
+	if err != nil {
+		log.Log(log.REST).Error("web-app not started, failed to load the TLS configuration",
+			zap.Error(err))
+		return
+	}
 	router := newRouter()
 	m.httpServer = &http.Server{
 		Addr:              ":9080",
 		Handler:           router,
 		ReadHeaderTimeout: 10 * time.Second,
+		TLSConfig:         tlsConfig,
 	}
 
-	log.Log(log.REST).Info("web-app started", zap.Int("port", 9080))
+	log.Log(log.REST).Info("web-app started",
+		zap.Int("port", 9080),
+		zap.Bool("tls", tlsConfig != nil))
 	go func() {
-		httpError := m.httpServer.ListenAndServe()
+		var httpError error
+		if tlsConfig != nil {
+			httpError = m.httpServer.ListenAndServeTLS("", "")
+		} else {
+			httpError = m.httpServer.ListenAndServe()
+		}
 		if httpError != nil && !errors.Is(httpError, http.ErrServerClosed) {
 			log.Log(log.REST).Error("HTTP serving error",
 				zap.Error(httpError))